@@ -20,6 +20,7 @@ package agents
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
@@ -28,16 +29,18 @@ import (
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/sessionmanager"
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/rpcclient"
 	"github.com/fiorix/go-diameter/diam"
-	"github.com/fiorix/go-diameter/diam/datatype"
-	"github.com/fiorix/go-diameter/diam/sm"
 )
 
 func NewDiameterAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnection,
 	pubsubs rpcclient.RpcClientConnection) (*DiameterAgent, error) {
-	da := &DiameterAgent{cgrCfg: cgrCfg, smg: smg, pubsubs: pubsubs, connMux: new(sync.Mutex)}
+	da := &DiameterAgent{cgrCfg: cgrCfg, smg: smg, pubsubs: pubsubs, connMux: new(sync.Mutex),
+		sessions: make(map[string]*dmtSession), ratingGroups: make(map[string]utils.StringMap), sessionsMux: new(sync.RWMutex),
+		pendingAnswers: make(map[uint32]chan *diam.Message), pendingMux: new(sync.Mutex),
+		peers: make(map[diam.Conn]*diameterPeer), peersMux: new(sync.RWMutex)}
 	if reflect.ValueOf(da.pubsubs).IsNil() {
 		da.pubsubs = nil // Empty it so we can check it later
 	}
@@ -51,34 +54,41 @@ func NewDiameterAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnectio
 }
 
 type DiameterAgent struct {
-	cgrCfg  *config.CGRConfig
-	smg     rpcclient.RpcClientConnection // Connection towards CGR-SMG component
-	pubsubs rpcclient.RpcClientConnection // Connection towards CGR-PubSub component
-	connMux *sync.Mutex                   // Protect connection for read/write
-}
-
-// Creates the message handlers
-func (self *DiameterAgent) handlers() diam.Handler {
-	settings := &sm.Settings{
-		OriginHost:       datatype.DiameterIdentity(self.cgrCfg.DiameterAgentCfg().OriginHost),
-		OriginRealm:      datatype.DiameterIdentity(self.cgrCfg.DiameterAgentCfg().OriginRealm),
-		VendorID:         datatype.Unsigned32(self.cgrCfg.DiameterAgentCfg().VendorId),
-		ProductName:      datatype.UTF8String(self.cgrCfg.DiameterAgentCfg().ProductName),
-		FirmwareRevision: datatype.Unsigned32(utils.DIAMETER_FIRMWARE_REVISION),
-	}
-	dSM := sm.New(settings)
-	dSM.HandleFunc("CCR", self.handleCCR)
-	dSM.HandleFunc("ALL", self.handleALL)
+	cgrCfg         *config.CGRConfig
+	smg            rpcclient.RpcClientConnection // Connection towards CGR-SMG component
+	pubsubs        rpcclient.RpcClientConnection // Connection towards CGR-PubSub component
+	connMux        *sync.Mutex                   // Protect connection for read/write
+	sessions       map[string]*dmtSession        // Diameter sessions indexed on CGRID, used to reach the peer owning them
+	ratingGroups   map[string]utils.StringMap    // Rating groups charged within Multiple-Services-Credit-Control, indexed on Diameter Session-Id
+	sessionsMux    *sync.RWMutex                 // Protect sessions and ratingGroups
+	pendingAnswers map[uint32]chan *diam.Message // Answers expected to server initiated requests, indexed on HopByHopID
+	pendingMux     *sync.Mutex                   // Protect pendingAnswers
+	peers          map[diam.Conn]*diameterPeer   // Peers which passed capabilities exchange
+	peersMux       *sync.RWMutex                 // Protect peers
+}
+
+// Creates the message handlers for one listener
+func (self *DiameterAgent) handlers(lstn *config.DiameterListenerCfg) diam.Handler {
+	dMux := diam.NewServeMux()
+	dMux.HandleFunc("CER", func(c diam.Conn, m *diam.Message) { self.handleCER(lstn, c, m) })
+	dMux.HandleFunc("DWR", self.handleDWR)
+	dMux.HandleFunc("DPR", self.handleDPR)
+	dMux.HandleFunc("CCR", self.handleCCR)
+	dMux.HandleFunc("DWA", self.handleAnswer)
+	dMux.HandleFunc("RAA", self.handleAnswer)
+	dMux.HandleFunc("ASA", self.handleAnswer)
+	dMux.HandleFunc("ALL", self.handleALL)
 	go func() {
-		for err := range dSM.ErrorReports() {
-			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> StateMachine error: %+v", err))
+		for err := range dMux.ErrorReports() {
+			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Listener: %s, error: %+v", lstn.Listen, err))
 		}
 	}()
-	return dSM
+	return dMux
 }
 
 func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestProcessor,
 	processorVars map[string]string, cca *CCA) (bool, error) {
+	lstn := self.connListener(ccr.conn) // answer with the identity of the listener the request came in
 	passesAllFilters := true
 	for _, fldFilter := range reqProcessor.RequestFilter {
 		if passes, _ := passesFieldFilter(ccr.diamMessage, fldFilter, nil); !passes {
@@ -93,12 +103,12 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> CCR message: %s", ccr.diamMessage))
 	}
 	if !reqProcessor.AppendCCA {
-		*cca = *NewBareCCAFromCCR(ccr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+		*cca = *NewBareCCAFromCCR(ccr, lstn.OriginHost, lstn.OriginRealm)
 	}
 	smgEv, err := ccr.AsSMGenericEvent(reqProcessor.CCRFields)
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v AsSMGenericEvent, error: %s", ccr.diamMessage, err))
-		*cca = *NewBareCCAFromCCR(ccr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+		*cca = *NewBareCCAFromCCR(ccr, lstn.OriginHost, lstn.OriginRealm)
 		if err := messageSetAVPsWithPath(cca.diamMessage, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
 			false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
 			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v messageSetAVPsWithPath, error: %s", cca.diamMessage, err.Error()))
@@ -112,7 +122,7 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 	if reqProcessor.PublishEvent && self.pubsubs != nil {
 		evt, err := smgEv.AsMapStringString()
 		if err != nil {
-			*cca = *NewBareCCAFromCCR(ccr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+			*cca = *NewBareCCAFromCCR(ccr, lstn.OriginHost, lstn.OriginRealm)
 			if err := messageSetAVPsWithPath(cca.diamMessage, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
 				false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
 				return false, err
@@ -122,7 +132,7 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 		}
 		var reply string
 		if err := self.pubsubs.Call("PubSubV1.Publish", engine.CgrEvent(evt), &reply); err != nil {
-			*cca = *NewBareCCAFromCCR(ccr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+			*cca = *NewBareCCAFromCCR(ccr, lstn.OriginHost, lstn.OriginRealm)
 			if err := messageSetAVPsWithPath(cca.diamMessage, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
 				false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
 				return false, err
@@ -137,59 +147,14 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 	if reqProcessor.DryRun { // DryRun does not send over network
 		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> SMGenericEvent: %+v", smgEv))
 		processorVars[CGRResultCode] = strconv.Itoa(diam.LimitedSuccess)
+	} else if msccCCRs := self.multipleServicesCCRs(ccr); reqProcessor.MultipleServices && len(msccCCRs) != 0 {
+		self.processMultipleServices(msccCCRs, reqProcessor, processorVars, cca)
 	} else { // Find out maxUsage over APIs
-		switch ccr.CCRequestType {
-		case 1:
-			err = self.smg.Call("SMGenericV2.InitiateSession", smgEv, &maxUsage)
-		case 2:
-			err = self.smg.Call("SMGenericV2.UpdateSession", smgEv, &maxUsage)
-		case 3, 4: // Handle them together since we generate CDR for them
-			var rpl string
-			if ccr.CCRequestType == 3 {
-				err = self.smg.Call("SMGenericV1.TerminateSession", smgEv, &rpl)
-			} else if ccr.CCRequestType == 4 {
-				err = self.smg.Call("SMGenericV2.ChargeEvent", smgEv.Clone(), &maxUsage)
-				if maxUsage == 0 {
-					smgEv[utils.Usage] = 0 // For CDR not to debit
-				}
-			}
-			if self.cgrCfg.DiameterAgentCfg().CreateCDR &&
-				(!self.cgrCfg.DiameterAgentCfg().CDRRequiresSession || err == nil || !strings.HasSuffix(err.Error(), utils.ErrNoActiveSession.Error())) { // Check if CDR requires session
-				if errCdr := self.smg.Call("SMGenericV1.ProcessCDR", smgEv, &rpl); errCdr != nil {
-					err = errCdr
-				}
-			}
-		}
-		if err != nil {
+		if maxUsage, err = self.dispatchSMGEvent(ccr, smgEv); err != nil {
 			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v, API error: %s", ccr.diamMessage, err))
-			switch { // Prettify some errors
-			case strings.HasSuffix(err.Error(), utils.ErrAccountNotFound.Error()):
-				processorVars[CGRError] = utils.ErrAccountNotFound.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrUserNotFound.Error()):
-				processorVars[CGRError] = utils.ErrUserNotFound.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrInsufficientCredit.Error()):
-				processorVars[CGRError] = utils.ErrInsufficientCredit.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrAccountDisabled.Error()):
-				processorVars[CGRError] = utils.ErrAccountDisabled.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrRatingPlanNotFound.Error()):
-				processorVars[CGRError] = utils.ErrRatingPlanNotFound.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrUnauthorizedDestination.Error()):
-				processorVars[CGRError] = utils.ErrUnauthorizedDestination.Error()
-			default: // Unknown error
-				processorVars[CGRError] = err.Error()
-				processorVars[CGRResultCode] = strconv.Itoa(DiameterRatingFailed)
-			}
-		}
-		if maxUsage < 0 {
-			maxUsage = 0
+			setProcessorVarsError(processorVars, err)
 		}
-		if prevMaxUsageStr, hasKey := processorVars[CGRMaxUsage]; hasKey {
-			prevMaxUsage, _ := utils.ParseDurationWithNanosecs(prevMaxUsageStr)
-			if prevMaxUsage < maxUsage {
-				maxUsage = prevMaxUsage
-			}
-		}
-		processorVars[CGRMaxUsage] = strconv.FormatInt(maxUsage.Nanoseconds(), 10)
+		setProcessorVarsMaxUsage(processorVars, maxUsage)
 	}
 	if err := messageSetAVPsWithPath(cca.diamMessage, []interface{}{"Result-Code"}, processorVars[CGRResultCode],
 		false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
@@ -209,16 +174,183 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 	return true, nil
 }
 
+// dispatchSMGEvent sends the event to SMG based on the CC-Request-Type, returning the maximum usage authorized
+func (self *DiameterAgent) dispatchSMGEvent(ccr *CCR, smgEv sessionmanager.SMGenericEvent) (maxUsage time.Duration, err error) {
+	maxUsage, err = self.callSMG(ccr.CCRequestType, smgEv)
+	switch ccr.CCRequestType { // Keep track of the peer owning the session so we can send requests towards it
+	case 1, 2:
+		if err == nil {
+			self.setSession(smgEv.GetCGRID(utils.META_DEFAULT), ccr)
+		}
+	case 3:
+		self.removeSession(smgEv.GetCGRID(utils.META_DEFAULT))
+	}
+	return
+}
+
+// callSMG calls the SMG API corresponding to the CC-Request-Type, generating also the CDR for terminate and event
+func (self *DiameterAgent) callSMG(ccReqType int, smgEv sessionmanager.SMGenericEvent) (maxUsage time.Duration, err error) {
+	switch ccReqType {
+	case 1:
+		err = self.smg.Call("SMGenericV2.InitiateSession", smgEv, &maxUsage)
+	case 2:
+		err = self.smg.Call("SMGenericV2.UpdateSession", smgEv, &maxUsage)
+	case 3, 4: // Handle them together since we generate CDR for them
+		var rpl string
+		if ccReqType == 3 {
+			err = self.smg.Call("SMGenericV1.TerminateSession", smgEv, &rpl)
+		} else if ccReqType == 4 {
+			err = self.smg.Call("SMGenericV2.ChargeEvent", smgEv.Clone(), &maxUsage)
+			if maxUsage == 0 {
+				smgEv[utils.Usage] = 0 // For CDR not to debit
+			}
+		}
+		if self.cgrCfg.DiameterAgentCfg().CreateCDR &&
+			(!self.cgrCfg.DiameterAgentCfg().CDRRequiresSession || err == nil || !strings.HasSuffix(err.Error(), utils.ErrNoActiveSession.Error())) { // Check if CDR requires session
+			if errCdr := self.smg.Call("SMGenericV1.ProcessCDR", smgEv, &rpl); errCdr != nil {
+				err = errCdr
+			}
+		}
+	}
+	if maxUsage < 0 {
+		maxUsage = 0
+	}
+	return
+}
+
+// multipleServicesCCRs returns one CCR per Multiple-Services-Credit-Control within ccr,
+// a CCR-T terminates also the rating groups tracked for the Diameter session but not reported in it
+func (self *DiameterAgent) multipleServicesCCRs(ccr *CCR) (msccCCRs []*CCR) {
+	msccCCRs = ccr.multipleServicesCCRs()
+	if ccr.CCRequestType != 3 {
+		return
+	}
+	reported := make(utils.StringMap)
+	for _, msccCCR := range msccCCRs {
+		reported[msccCCR.msccRatingGroup()] = true
+	}
+	var unreported []string
+	for _, ratingGroup := range self.sessionRatingGroups(ccr.SessionId) {
+		if !reported[ratingGroup] {
+			unreported = append(unreported, ratingGroup)
+		}
+	}
+	rgCCRs, err := ccr.ratingGroupsCCRs(unreported)
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Terminating rating groups: %+v of session: %s, error: %s",
+			unreported, ccr.SessionId, err))
+		return
+	}
+	return append(msccCCRs, rgCCRs...)
+}
+
+// processMultipleServices handles each Multiple-Services-Credit-Control within CCR as own session,
+// answering with one Multiple-Services-Credit-Control AVP per Rating-Group
+// errors are reported within the Multiple-Services-Credit-Control, the top level Result-Code staying DIAMETER_SUCCESS
+func (self *DiameterAgent) processMultipleServices(msccCCRs []*CCR, reqProcessor *config.DARequestProcessor,
+	processorVars map[string]string, cca *CCA) {
+	for _, msccCCR := range msccCCRs {
+		ratingGroup := msccCCR.msccRatingGroup()
+		if msccCCR.CCRequestType == 2 && !self.hasRatingGroup(msccCCR.SessionId, ratingGroup) {
+			initCCR := *msccCCR // rating group first seen in update, initiate it's session
+			initCCR.CCRequestType = 1
+			msccCCR = &initCCR
+		}
+		resultCode := diam.Success
+		var maxUsage time.Duration
+		smgEv, err := msccCCR.AsSMGenericEvent(reqProcessor.CCRFields)
+		if err == nil {
+			if len(reqProcessor.Flags) != 0 {
+				smgEv[utils.CGRFlags] = reqProcessor.Flags.String()
+			}
+			if ratingGroup != "" { // Each rating group is charged within it's own session
+				for _, fldName := range []string{utils.OriginID, utils.InitialOriginID} {
+					if _, hasIt := smgEv[fldName]; hasIt {
+						smgEv[fldName] = utils.ConcatenatedKey(smgEv.GetOriginID(fldName), ratingGroup)
+					}
+				}
+				smgEv[RatingGroup] = ratingGroup
+			}
+			maxUsage, err = self.dispatchSMGEvent(msccCCR, smgEv)
+		}
+		switch msccCCR.CCRequestType {
+		case 1, 2:
+			if err == nil {
+				self.setRatingGroup(msccCCR.SessionId, ratingGroup)
+			}
+		case 3:
+			self.removeRatingGroup(msccCCR.SessionId, ratingGroup)
+		}
+		if err != nil {
+			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v, rating group: %s, error: %s",
+				msccCCR.diamMessage, ratingGroup, err))
+			setProcessorVarsError(processorVars, err)
+			processorVars[CGRResultCode] = strconv.Itoa(diam.Success) // failure reported per rating group
+			resultCode = DiameterRatingFailed
+			if strings.HasSuffix(err.Error(), utils.ErrInsufficientCredit.Error()) {
+				resultCode = DiameterCreditLimitReached
+			}
+		}
+		grantUnits := msccCCR.CCRequestType != 3 // Termination does not grant units
+		if grantUnits && err == nil && maxUsage == 0 {
+			resultCode = DiameterCreditLimitReached
+		}
+		setProcessorVarsMaxUsage(processorVars, maxUsage)
+		if err := cca.AddMultipleServicesCreditControl(ratingGroup, resultCode,
+			grantUnits, msccCCR.msccRequestsVolume(), maxUsage); err != nil {
+			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Answering message: %+v, rating group: %s, error: %s",
+				msccCCR.diamMessage, ratingGroup, err))
+		}
+	}
+}
+
+// setProcessorVarsError populates CGRError and CGRResultCode out of the error received from SMG
+func setProcessorVarsError(processorVars map[string]string, err error) {
+	switch { // Prettify some errors
+	case strings.HasSuffix(err.Error(), utils.ErrAccountNotFound.Error()):
+		processorVars[CGRError] = utils.ErrAccountNotFound.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrUserNotFound.Error()):
+		processorVars[CGRError] = utils.ErrUserNotFound.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrInsufficientCredit.Error()):
+		processorVars[CGRError] = utils.ErrInsufficientCredit.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrAccountDisabled.Error()):
+		processorVars[CGRError] = utils.ErrAccountDisabled.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrRatingPlanNotFound.Error()):
+		processorVars[CGRError] = utils.ErrRatingPlanNotFound.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrUnauthorizedDestination.Error()):
+		processorVars[CGRError] = utils.ErrUnauthorizedDestination.Error()
+	default: // Unknown error
+		processorVars[CGRError] = err.Error()
+		processorVars[CGRResultCode] = strconv.Itoa(DiameterRatingFailed)
+	}
+}
+
+// setProcessorVarsMaxUsage populates CGRMaxUsage, keeping the smallest value out of the processed ones
+func setProcessorVarsMaxUsage(processorVars map[string]string, maxUsage time.Duration) {
+	if prevMaxUsageStr, hasKey := processorVars[CGRMaxUsage]; hasKey {
+		prevMaxUsage, _ := utils.ParseDurationWithNanosecs(prevMaxUsageStr)
+		if prevMaxUsage < maxUsage {
+			maxUsage = prevMaxUsage
+		}
+	}
+	processorVars[CGRMaxUsage] = strconv.FormatInt(maxUsage.Nanoseconds(), 10)
+}
+
 func (self *DiameterAgent) handlerCCR(c diam.Conn, m *diam.Message) {
 	ccr, err := NewCCRFromDiameterMessage(m, self.cgrCfg.DiameterAgentCfg().DebitInterval)
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Unmarshaling message: %s, error: %s", m, err))
 		return
 	}
-	cca := NewBareCCAFromCCR(ccr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+	ccr.conn = c
+	lstn := self.connListener(c)
+	cca := NewBareCCAFromCCR(ccr, lstn.OriginHost, lstn.OriginRealm)
 	var processed, lclProcessed bool
 	processorVars := make(map[string]string) // Shared between processors
 	for _, reqProcessor := range self.cgrCfg.DiameterAgentCfg().RequestProcessors {
+		if !processorMatches(reqProcessor, m) {
+			continue
+		}
 		lclProcessed, err = self.processCCR(ccr, reqProcessor, processorVars, cca)
 		if lclProcessed { // Process local so we don't overwrite globally
 			processed = lclProcessed
@@ -245,13 +377,356 @@ func (self *DiameterAgent) handlerCCR(c diam.Conn, m *diam.Message) {
 // Simply dispatch the handling in goroutines
 // Could be futher improved with rate control
 func (self *DiameterAgent) handleCCR(c diam.Conn, m *diam.Message) {
+	if !self.acceptPeer(c, m) {
+		return
+	}
+	self.touchPeer(c)
 	go self.handlerCCR(c, m)
 }
 
+// dispatchRequestEvent sends the event built out of a generic request to SMG, the API being selected via processor flags
+// dispatched will be false if none of the flags asks for SMG, the answer being built only out of templates
+func (self *DiameterAgent) dispatchRequestEvent(flags utils.StringMap,
+	smgEv sessionmanager.SMGenericEvent) (maxUsage time.Duration, dispatched bool, err error) {
+	var ccReqType int
+	switch {
+	case flags[MetaAuth]:
+		err = self.smg.Call("SMGenericV2.GetMaxUsage", smgEv, &maxUsage)
+		if maxUsage < 0 {
+			maxUsage = 0
+		}
+		return maxUsage, true, err
+	case flags[MetaInitiate]:
+		ccReqType = 1
+	case flags[MetaUpdate]:
+		ccReqType = 2
+	case flags[MetaTerminate]:
+		ccReqType = 3
+	case flags[MetaEvent]:
+		ccReqType = 4
+	default:
+		return
+	}
+	maxUsage, err = self.callSMG(ccReqType, smgEv)
+	return maxUsage, true, err
+}
+
+// setRatingFailedAnswer resets the answer to a bare one carrying DIAMETER_RATING_FAILED as Result-Code
+func (self *DiameterAgent) setRatingFailedAnswer(m, ans *diam.Message, lstn *config.DiameterListenerCfg) error {
+	*ans = *newBareAnswer(m, lstn.OriginHost, lstn.OriginRealm)
+	return messageSetAVPsWithPath(ans, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
+		false, self.cgrCfg.DiameterAgentCfg().Timezone)
+}
+
+// processRequest handles requests other than Credit-Control, building the event and the answer out of processor templates
+func (self *DiameterAgent) processRequest(c diam.Conn, m *diam.Message, reqProcessor *config.DARequestProcessor,
+	processorVars map[string]string, ans *diam.Message) (bool, error) {
+	if !processorMatches(reqProcessor, m) {
+		return false, nil
+	}
+	for _, fldFilter := range reqProcessor.RequestFilter {
+		if passes, _ := passesFieldFilter(m, fldFilter, nil); !passes {
+			return false, nil // Not going with this processor further
+		}
+	}
+	lstn := self.connListener(c)
+	if reqProcessor.DryRun { // DryRun should log the matching processor as well as the received request
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> RequestProcessor: %s", reqProcessor.Id))
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> Request message: %s", m))
+	}
+	if !reqProcessor.AppendCCA {
+		*ans = *newBareAnswer(m, lstn.OriginHost, lstn.OriginRealm)
+	}
+	smgEv, err := messageAsSMGenericEvent(m, reqProcessor.CCRFields, DIAMETER_REQUEST, self.cgrCfg.DiameterAgentCfg().DebitInterval)
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v messageAsSMGenericEvent, error: %s", m, err))
+		if err := self.setRatingFailedAnswer(m, ans, lstn); err != nil {
+			return false, err
+		}
+		return false, ErrDiameterRatingFailed
+	}
+	if len(reqProcessor.Flags) != 0 {
+		smgEv[utils.CGRFlags] = reqProcessor.Flags.String() // Populate CGRFlags automatically
+	}
+	if reqProcessor.PublishEvent && self.pubsubs != nil {
+		evt, err := smgEv.AsMapStringString()
+		if err == nil {
+			var reply string
+			err = self.pubsubs.Call("PubSubV1.Publish", engine.CgrEvent(evt), &reply)
+		}
+		if err != nil {
+			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v failed publishing event, error: %s", m, err))
+			if err := self.setRatingFailedAnswer(m, ans, lstn); err != nil {
+				return false, err
+			}
+			return false, ErrDiameterRatingFailed
+		}
+	}
+	processorVars[CGRResultCode] = strconv.Itoa(diam.Success)
+	processorVars[CGRError] = ""
+	if reqProcessor.DryRun { // DryRun does not send over network
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> SMGenericEvent: %+v", smgEv))
+		processorVars[CGRResultCode] = strconv.Itoa(diam.LimitedSuccess)
+	} else if maxUsage, dispatched, err := self.dispatchRequestEvent(reqProcessor.Flags, smgEv); dispatched {
+		if err != nil {
+			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v, API error: %s", m, err))
+			setProcessorVarsError(processorVars, err)
+		}
+		setProcessorVarsMaxUsage(processorVars, maxUsage)
+	}
+	if err := messageSetAVPsWithPath(ans, []interface{}{"Result-Code"}, processorVars[CGRResultCode],
+		false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
+		return false, err
+	}
+	if err := setAnswerAVPs(m, ans, reqProcessor.CCAFields, processorVars, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Answer setAnswerAVPs for message: %+v, error: %s", m, err))
+		if err := self.setRatingFailedAnswer(m, ans, lstn); err != nil {
+			return false, err
+		}
+		return false, ErrDiameterRatingFailed
+	}
+	if reqProcessor.DryRun {
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> Answer message: %s", ans))
+	}
+	return true, nil
+}
+
+// handlerRequest answers requests other than Credit-Control using the request processors configured for them
+func (self *DiameterAgent) handlerRequest(c diam.Conn, m *diam.Message) {
+	lstn := self.connListener(c)
+	ans := newBareAnswer(m, lstn.OriginHost, lstn.OriginRealm)
+	var processed, lclProcessed bool
+	var err error
+	processorVars := make(map[string]string) // Shared between processors
+	for _, reqProcessor := range self.cgrCfg.DiameterAgentCfg().RequestProcessors {
+		lclProcessed, err = self.processRequest(c, m, reqProcessor, processorVars, ans)
+		if lclProcessed { // Process local so we don't overwrite globally
+			processed = lclProcessed
+		}
+		if err != nil || (lclProcessed && !reqProcessor.ContinueOnSuccess) {
+			break
+		}
+	}
+	if err != nil && err != ErrDiameterRatingFailed {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v, error: %s", m, err))
+		return
+	} else if !processed {
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> No request processor enabled for message from %s, ignoring request:\n%s", c.RemoteAddr(), m))
+		return
+	}
+	self.connMux.Lock()
+	defer self.connMux.Unlock()
+	if _, err := ans.WriteTo(c); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Failed to write message to %s: %s\n%s\n", c.RemoteAddr(), err, ans))
+	}
+}
+
+// handleAnswer dispatches the answers received for our own requests (RAA/ASA)
+func (self *DiameterAgent) handleAnswer(c diam.Conn, m *diam.Message) {
+	self.touchPeer(c)
+	self.pendingMux.Lock()
+	ansChan, hasIt := self.pendingAnswers[m.Header.HopByHopID]
+	delete(self.pendingAnswers, m.Header.HopByHopID)
+	self.pendingMux.Unlock()
+	if !hasIt {
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Received unexpected answer from %s:\n%s", c.RemoteAddr(), m))
+		return
+	}
+	ansChan <- m
+}
+
+// handleALL dispatches the requests without own handler towards the generic request processors
 func (self *DiameterAgent) handleALL(c diam.Conn, m *diam.Message) {
-	utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Received unexpected message from %s:\n%s", c.RemoteAddr(), m))
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Received unexpected message from %s:\n%s", c.RemoteAddr(), m))
+		return
+	}
+	if !self.acceptPeer(c, m) {
+		return
+	}
+	self.touchPeer(c)
+	go self.handlerRequest(c, m)
 }
 
+// ListenAndServe binds all the configured listeners before serving on them,
+// the first one failing closes the others so the agent does not keep running partially
 func (self *DiameterAgent) ListenAndServe() error {
-	return diam.ListenAndServe(self.cgrCfg.DiameterAgentCfg().Listen, self.handlers(), nil)
+	lstns := self.listeners()
+	netLstns := make([]net.Listener, 0, len(lstns))
+	defer func() {
+		for _, l := range netLstns {
+			l.Close()
+		}
+	}()
+	for _, lstn := range lstns {
+		l, err := net.Listen(lstn.ListenNet, lstn.Listen)
+		if err != nil {
+			return err
+		}
+		netLstns = append(netLstns, l)
+	}
+	errChan := make(chan error, len(lstns))
+	for i, lstn := range lstns {
+		go func(lstn *config.DiameterListenerCfg, l net.Listener) {
+			utils.Logger.Info(fmt.Sprintf("<DiameterAgent> Start listening on <%s> over <%s> as <%s>",
+				lstn.Listen, lstn.ListenNet, lstn.OriginHost))
+			srv := &diam.Server{Network: lstn.ListenNet, Addr: lstn.Listen, Handler: self.handlers(lstn)}
+			errChan <- srv.Serve(l)
+		}(lstn, netLstns[i])
+	}
+	return <-errChan
+}
+
+func (self *DiameterAgent) setSession(cgrID string, ccr *CCR) {
+	if ccr.conn == nil { // Not received over network
+		return
+	}
+	self.sessionsMux.Lock()
+	self.sessions[cgrID] = newDmtSession(ccr)
+	self.sessionsMux.Unlock()
+}
+
+func (self *DiameterAgent) removeSession(cgrID string) {
+	self.sessionsMux.Lock()
+	delete(self.sessions, cgrID)
+	self.sessionsMux.Unlock()
+}
+
+// removeConnSessions stops tracking the sessions owned by the peer behind connection c
+func (self *DiameterAgent) removeConnSessions(c diam.Conn) {
+	self.sessionsMux.Lock()
+	for cgrID, dSess := range self.sessions {
+		if dSess.conn == c {
+			delete(self.sessions, cgrID)
+		}
+	}
+	self.sessionsMux.Unlock()
+}
+
+func (self *DiameterAgent) getSession(cgrID string) (dSess *dmtSession, hasIt bool) {
+	self.sessionsMux.RLock()
+	dSess, hasIt = self.sessions[cgrID]
+	self.sessionsMux.RUnlock()
+	return
+}
+
+// setRatingGroup tracks the ratingGroup as active within the Diameter session
+func (self *DiameterAgent) setRatingGroup(sessionID, ratingGroup string) {
+	self.sessionsMux.Lock()
+	if _, hasIt := self.ratingGroups[sessionID]; !hasIt {
+		self.ratingGroups[sessionID] = make(utils.StringMap)
+	}
+	self.ratingGroups[sessionID][ratingGroup] = true
+	self.sessionsMux.Unlock()
+}
+
+func (self *DiameterAgent) removeRatingGroup(sessionID, ratingGroup string) {
+	self.sessionsMux.Lock()
+	delete(self.ratingGroups[sessionID], ratingGroup)
+	if len(self.ratingGroups[sessionID]) == 0 {
+		delete(self.ratingGroups, sessionID)
+	}
+	self.sessionsMux.Unlock()
+}
+
+func (self *DiameterAgent) hasRatingGroup(sessionID, ratingGroup string) (hasIt bool) {
+	self.sessionsMux.RLock()
+	hasIt = self.ratingGroups[sessionID][ratingGroup]
+	self.sessionsMux.RUnlock()
+	return
+}
+
+// sessionRatingGroups returns the rating groups active within the Diameter session
+func (self *DiameterAgent) sessionRatingGroups(sessionID string) (ratingGroups []string) {
+	self.sessionsMux.RLock()
+	ratingGroups = self.ratingGroups[sessionID].Slice()
+	self.sessionsMux.RUnlock()
+	return
+}
+
+// sendRequest writes the request towards the peer and waits for it's answer, maximum ReplyTimeout
+func (self *DiameterAgent) sendRequest(c diam.Conn, m *diam.Message) (*diam.Message, error) {
+	ansChan := make(chan *diam.Message, 1)
+	self.pendingMux.Lock()
+	self.pendingAnswers[m.Header.HopByHopID] = ansChan
+	self.pendingMux.Unlock()
+	defer func() {
+		self.pendingMux.Lock()
+		delete(self.pendingAnswers, m.Header.HopByHopID)
+		self.pendingMux.Unlock()
+	}()
+	self.connMux.Lock()
+	_, err := m.WriteTo(c)
+	self.connMux.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case ans := <-ansChan:
+		return ans, nil
+	case <-time.After(self.cgrCfg.DiameterAgentCfg().ReplyTimeout):
+		return nil, utils.ErrTimedOut
+	}
+}
+
+// sendSessionRequest sends a server initiated request (RAR/ASR) towards the peer owning the session
+func (self *DiameterAgent) sendSessionRequest(cgrID string, cmdCode uint32) error {
+	dSess, hasIt := self.getSession(cgrID)
+	if !hasIt {
+		return utils.ErrNotFound
+	}
+	lstn := self.connListener(dSess.conn)
+	ans, err := self.sendRequest(dSess.conn, dSess.asDiameterRequest(cmdCode, lstn.OriginHost, lstn.OriginRealm))
+	if err != nil {
+		return err
+	}
+	return resultCodeAsError(ans)
+}
+
+// Call implements rpcclient.RpcClientConnection interface so SMG can reach us back
+func (self *DiameterAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	parts := strings.Split(serviceMethod, ".")
+	if len(parts) != 2 {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// get method
+	method := reflect.ValueOf(self).MethodByName(parts[0][len(parts[0])-2:] + parts[1]) // Inherit the version in the method
+	if !method.IsValid() {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// construct the params
+	params := []reflect.Value{reflect.ValueOf(args), reflect.ValueOf(reply)}
+	ret := method.Call(params)
+	if len(ret) != 1 {
+		return utils.ErrServerError
+	}
+	if ret[0].Interface() == nil {
+		return nil
+	}
+	err, ok := ret[0].Interface().(error)
+	if !ok {
+		return utils.ErrServerError
+	}
+	return err
+}
+
+// V1DisconnectSession sends Abort-Session-Request towards the peer owning the session
+func (self *DiameterAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) (err error) {
+	cgrID := sessionmanager.SMGenericEvent(args.EventStart).GetCGRID(utils.META_DEFAULT)
+	if err = self.sendSessionRequest(cgrID, diam.AbortSession); err != nil {
+		return
+	}
+	self.removeSession(cgrID) // session aborted by the peer, no further requests towards it
+	*reply = utils.OK
+	return
+}
+
+// V1ReAuthorizeSession sends Re-Auth-Request towards the peer owning the session
+func (self *DiameterAgent) V1ReAuthorizeSession(args utils.AttrReAuthorizeSession, reply *string) (err error) {
+	if err = self.sendSessionRequest(
+		sessionmanager.SMGenericEvent(args.EventStart).GetCGRID(utils.META_DEFAULT), diam.ReAuth); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
 }
@@ -0,0 +1,195 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
+	"github.com/fiorix/go-diameter/diam/datatype"
+)
+
+func TestDiameterAgentProcessRequest(t *testing.T) {
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	da := &DiameterAgent{cgrCfg: cgrCfg,
+		peers: make(map[diam.Conn]*diameterPeer), peersMux: new(sync.RWMutex)}
+	m := diam.NewMessage(306, diam.RequestFlag, 16777217, 1, 1, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("shudr1"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(16777217))
+	m.NewAVP(avp.UserName, avp.Mbit, 0, datatype.UTF8String("1001"))
+	ans := newBareAnswer(m, "CGR-DA", "cgrates.org")
+	reqProcessor := &config.DARequestProcessor{Id: "SH_UDR", CommandCode: diam.CreditControl}
+	if processed, err := da.processRequest(nil, m, reqProcessor, map[string]string{}, ans); err != nil {
+		t.Error(err)
+	} else if processed {
+		t.Error("Not expecting processor for other command to process the request")
+	}
+	reqProcessor = &config.DARequestProcessor{Id: "SH_UDR", CommandCode: 306, ApplicationID: 16777217,
+		CCRFields: []*config.CfgCdrField{
+			&config.CfgCdrField{Tag: "Account", Type: utils.META_COMPOSED, FieldId: utils.Account,
+				Value: utils.ParseRSRFieldsMustCompile("User-Name", utils.INFIELD_SEP), Mandatory: true},
+		},
+		CCAFields: []*config.CfgCdrField{
+			&config.CfgCdrField{Tag: "UserName", Type: utils.META_COMPOSED, FieldId: "User-Name",
+				Value: utils.ParseRSRFieldsMustCompile("User-Name", utils.INFIELD_SEP), Mandatory: true},
+		},
+	}
+	processorVars := make(map[string]string)
+	if processed, err := da.processRequest(nil, m, reqProcessor, processorVars, ans); err != nil {
+		t.Error(err)
+	} else if !processed {
+		t.Error("Request not processed")
+	}
+	if processorVars[CGRResultCode] != "2001" {
+		t.Errorf("Received processorVars: %+v", processorVars)
+	}
+	if rcAVP, err := ans.FindAVP(avp.ResultCode, 0); err != nil {
+		t.Error(err)
+	} else if rc := avpValAsString(rcAVP); rc != "2001" {
+		t.Errorf("Received Result-Code: %s", rc)
+	}
+	if unAVP, err := ans.FindAVP(avp.UserName, 0); err != nil {
+		t.Error(err)
+	} else if un := avpValAsString(unAVP); un != "1001" {
+		t.Errorf("Received User-Name: %s", un)
+	}
+}
+
+func TestDiameterAgentMultipleServicesCCRs(t *testing.T) {
+	da := &DiameterAgent{ratingGroups: make(map[string]utils.StringMap), sessionsMux: new(sync.RWMutex)}
+	da.setRatingGroup("dasess1", "1")
+	da.setRatingGroup("dasess1", "2")
+	if !da.hasRatingGroup("dasess1", "2") {
+		t.Error("Rating group 2 should be tracked")
+	}
+	ccr := &CCR{SessionId: "dasess1", AuthApplicationId: 4, CCRequestType: 2, CCRequestNumber: 1}
+	ccr.diamMessage = ccr.AsBareDiameterMessage()
+	if msccCCRs := da.multipleServicesCCRs(ccr); len(msccCCRs) != 0 { // Update without MSCC touches no rating group
+		t.Errorf("Unexpected CCRs: %+v", msccCCRs)
+	}
+	ccr = &CCR{SessionId: "dasess1", AuthApplicationId: 4, CCRequestType: 3, CCRequestNumber: 2}
+	ccr.diamMessage = ccr.AsBareDiameterMessage()
+	ccr.diamMessage.NewAVP("Multiple-Services-Credit-Control", avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(432, avp.Mbit, 0, datatype.Unsigned32(2)), // Rating-Group
+		},
+	})
+	msccCCRs := da.multipleServicesCCRs(ccr)
+	if len(msccCCRs) != 2 {
+		t.Fatalf("Unexpected CCRs: %+v", msccCCRs)
+	}
+	for i, eRatingGroup := range []string{"2", "1"} { // Reported first, tracked after
+		if ratingGroup := msccCCRs[i].msccRatingGroup(); ratingGroup != eRatingGroup {
+			t.Errorf("Expecting: %s, received: %s", eRatingGroup, ratingGroup)
+		}
+	}
+	ccr = &CCR{SessionId: "dasess1", AuthApplicationId: 4, CCRequestType: 3, CCRequestNumber: 2}
+	ccr.diamMessage = ccr.AsBareDiameterMessage()
+	if msccCCRs := da.multipleServicesCCRs(ccr); len(msccCCRs) != 2 { // Termination without MSCC
+		t.Errorf("Unexpected CCRs: %+v", msccCCRs)
+	}
+	da.removeRatingGroup("dasess1", "1")
+	da.removeRatingGroup("dasess1", "2")
+	if _, hasIt := da.ratingGroups["dasess1"]; hasIt {
+		t.Error("Session should not be tracked anymore")
+	}
+}
+
+// dmtTestConn identifies a peer connection within tests
+type dmtTestConn struct {
+	diam.Conn
+	closed bool
+}
+
+func (c *dmtTestConn) Close() {
+	c.closed = true
+}
+
+func (c *dmtTestConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 3868}
+}
+
+func TestDiameterAgentRemoveConnSessions(t *testing.T) {
+	da := &DiameterAgent{sessions: make(map[string]*dmtSession), sessionsMux: new(sync.RWMutex),
+		peers: make(map[diam.Conn]*diameterPeer), peersMux: new(sync.RWMutex)}
+	conn1, conn2 := new(dmtTestConn), new(dmtTestConn)
+	da.sessions["cgrid1"] = &dmtSession{conn: conn1, sessionID: "dasess1"}
+	da.sessions["cgrid2"] = &dmtSession{conn: conn1, sessionID: "dasess2"}
+	da.sessions["cgrid3"] = &dmtSession{conn: conn2, sessionID: "dasess3"}
+	da.removePeer(conn1)
+	if _, hasIt := da.getSession("cgrid1"); hasIt {
+		t.Error("Session of closed connection still tracked")
+	}
+	if _, hasIt := da.getSession("cgrid2"); hasIt {
+		t.Error("Session of closed connection still tracked")
+	}
+	if _, hasIt := da.getSession("cgrid3"); !hasIt {
+		t.Error("Session of open connection not tracked")
+	}
+}
+
+func TestDiameterAgentAcceptPeer(t *testing.T) {
+	da := &DiameterAgent{peers: make(map[diam.Conn]*diameterPeer), peersMux: new(sync.RWMutex)}
+	peerConn, unknownConn := new(dmtTestConn), new(dmtTestConn)
+	da.peers[peerConn] = &diameterPeer{conn: peerConn, stopWatchdog: make(chan struct{})}
+	m := diam.NewRequest(diam.CreditControl, 4, nil)
+	if !da.acceptPeer(peerConn, m) {
+		t.Error("Peer not accepted")
+	} else if peerConn.closed {
+		t.Error("Peer connection closed")
+	}
+	if da.acceptPeer(unknownConn, m) {
+		t.Error("Connection without capabilities exchange accepted")
+	} else if !unknownConn.closed {
+		t.Error("Connection without capabilities exchange not closed")
+	}
+}
+
+func TestDiameterAgentListenAndServeBindFailure(t *testing.T) {
+	busyLstn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busyLstn.Close()
+	freeLstn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	freeAddr := freeLstn.Addr().String()
+	freeLstn.Close()
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	cgrCfg.DiameterAgentCfg().Listen = freeAddr
+	cgrCfg.DiameterAgentCfg().Listeners = []*config.DiameterListenerCfg{
+		&config.DiameterListenerCfg{Listen: busyLstn.Addr().String()}}
+	da := &DiameterAgent{cgrCfg: cgrCfg}
+	if err := da.ListenAndServe(); err == nil {
+		t.Error("Expecting bind error")
+	}
+	// main listener should be released once the extra one failed
+	if l, err := net.Listen("tcp", freeAddr); err != nil {
+		t.Error(err)
+	} else {
+		l.Close()
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
+	"github.com/fiorix/go-diameter/diam/datatype"
+)
+
+const (
+	DiameterPeerOpen            = "OPEN"
+	DiameterPeerSuspect         = "SUSPECT" // one watchdog failed, RFC 3539
+	DiameterNoCommonApplication = 5010
+	DiameterRelayApplicationID  = 0xffffffff
+	diameterMaxWatchdogFailures = 2
+	diameterDefaultListenNet    = "tcp"
+)
+
+// diameterPeer is one peer which passed capabilities exchange on our listeners
+type diameterPeer struct {
+	conn         diam.Conn
+	listener     *config.DiameterListenerCfg
+	info         utils.DiameterPeer
+	stopWatchdog chan struct{}
+}
+
+// listeners returns the main listener followed by the extra ones, unset fields being inherited from the main one
+func (self *DiameterAgent) listeners() (lstns []*config.DiameterListenerCfg) {
+	daCfg := self.cgrCfg.DiameterAgentCfg()
+	mainLstn := &config.DiameterListenerCfg{Listen: daCfg.Listen, ListenNet: daCfg.ListenNet,
+		OriginHost: daCfg.OriginHost, OriginRealm: daCfg.OriginRealm,
+		VendorId: daCfg.VendorId, ProductName: daCfg.ProductName, Applications: daCfg.Applications}
+	if mainLstn.ListenNet == "" {
+		mainLstn.ListenNet = diameterDefaultListenNet
+	}
+	lstns = append(lstns, mainLstn)
+	for _, lstnCfg := range daCfg.Listeners {
+		lstn := *lstnCfg
+		if lstn.ListenNet == "" {
+			lstn.ListenNet = mainLstn.ListenNet
+		}
+		if lstn.OriginHost == "" {
+			lstn.OriginHost = mainLstn.OriginHost
+		}
+		if lstn.OriginRealm == "" {
+			lstn.OriginRealm = mainLstn.OriginRealm
+		}
+		if lstn.VendorId == 0 {
+			lstn.VendorId = mainLstn.VendorId
+		}
+		if lstn.ProductName == "" {
+			lstn.ProductName = mainLstn.ProductName
+		}
+		if lstn.Applications == nil {
+			lstn.Applications = mainLstn.Applications
+		}
+		lstns = append(lstns, &lstn)
+	}
+	return
+}
+
+// commonApplications returns the applications we support out of the ones offered by the peer
+// no applications configured means accepting all offered
+func commonApplications(supported, offered []int) (common []int) {
+	if len(supported) == 0 {
+		return offered
+	}
+	for _, offApp := range offered {
+		if offApp == DiameterRelayApplicationID { // relay agents support all applications
+			return supported
+		}
+	}
+	for _, supApp := range supported {
+		for _, offApp := range offered {
+			if supApp == offApp {
+				common = append(common, supApp)
+				break
+			}
+		}
+	}
+	return
+}
+
+// capabilitiesAnswer builds the CEA/DWA/DPA answer carrying our identity
+func capabilitiesAnswer(m *diam.Message, resultCode uint32, lstn *config.DiameterListenerCfg) *diam.Message {
+	a := m.Answer(resultCode)
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity(lstn.OriginHost))
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity(lstn.OriginRealm))
+	return a
+}
+
+// handleCER negotiates the capabilities with a new peer and starts supervising it
+func (self *DiameterAgent) handleCER(lstn *config.DiameterListenerCfg, c diam.Conn, m *diam.Message) {
+	var cer struct {
+		OriginHost                  string `avp:"Origin-Host"`
+		OriginRealm                 string `avp:"Origin-Realm"`
+		AuthApplicationId           []int  `avp:"Auth-Application-Id"`
+		VendorSpecificApplicationId []struct {
+			AuthApplicationId int `avp:"Auth-Application-Id"`
+		} `avp:"Vendor-Specific-Application-Id"`
+	}
+	if err := m.Unmarshal(&cer); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Unmarshaling CER: %s, error: %s", m, err))
+		return
+	}
+	offered := cer.AuthApplicationId
+	for _, vsApp := range cer.VendorSpecificApplicationId {
+		if vsApp.AuthApplicationId != 0 {
+			offered = append(offered, vsApp.AuthApplicationId)
+		}
+	}
+	common := commonApplications(lstn.Applications, offered)
+	resultCode := uint32(diam.Success)
+	if len(common) == 0 {
+		resultCode = DiameterNoCommonApplication
+	}
+	a := capabilitiesAnswer(m, resultCode, lstn)
+	if host, _, err := net.SplitHostPort(c.LocalAddr().String()); err == nil {
+		a.NewAVP(avp.HostIPAddress, avp.Mbit, 0, datatype.Address(net.ParseIP(host)))
+	}
+	a.NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(lstn.VendorId))
+	a.NewAVP(avp.ProductName, 0, 0, datatype.UTF8String(lstn.ProductName))
+	a.NewAVP(avp.FirmwareRevision, 0, 0, datatype.Unsigned32(utils.DIAMETER_FIRMWARE_REVISION))
+	for _, appID := range common {
+		a.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(appID))
+	}
+	self.connMux.Lock()
+	_, err := a.WriteTo(c)
+	self.connMux.Unlock()
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Failed to write CEA to %s: %s", c.RemoteAddr(), err))
+		return
+	}
+	if resultCode != diam.Success {
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> No common application with peer: %s, offered: %v",
+			cer.OriginHost, offered))
+		c.Close()
+		return
+	}
+	now := time.Now()
+	p := &diameterPeer{conn: c, listener: lstn, stopWatchdog: make(chan struct{}),
+		info: utils.DiameterPeer{OriginHost: cer.OriginHost, OriginRealm: cer.OriginRealm,
+			RemoteAddr: c.RemoteAddr().String(), Listener: lstn.Listen, State: DiameterPeerOpen,
+			Applications: common, ConnectedAt: now, LastActivity: now}}
+	self.peersMux.Lock()
+	if prevPeer, hasIt := self.peers[c]; hasIt { // CER repeated on the same connection
+		close(prevPeer.stopWatchdog)
+	}
+	self.peers[c] = p
+	self.peersMux.Unlock()
+	if cn, canNotify := c.(diam.CloseNotifier); canNotify {
+		go func() {
+			<-cn.CloseNotify()
+			self.removePeer(c)
+		}()
+	}
+	if self.cgrCfg.DiameterAgentCfg().WatchdogInterval > 0 {
+		go self.watchdog(p)
+	}
+}
+
+// handleDWR answers the watchdog requests of our peers
+func (self *DiameterAgent) handleDWR(c diam.Conn, m *diam.Message) {
+	self.touchPeer(c)
+	a := capabilitiesAnswer(m, diam.Success, self.connListener(c))
+	self.connMux.Lock()
+	defer self.connMux.Unlock()
+	if _, err := a.WriteTo(c); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Failed to write DWA to %s: %s", c.RemoteAddr(), err))
+	}
+}
+
+// handleDPR answers the peer disconnecting and stops supervising it
+func (self *DiameterAgent) handleDPR(c diam.Conn, m *diam.Message) {
+	a := capabilitiesAnswer(m, diam.Success, self.connListener(c))
+	self.removePeer(c)
+	self.connMux.Lock()
+	defer self.connMux.Unlock()
+	if _, err := a.WriteTo(c); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Failed to write DPA to %s: %s", c.RemoteAddr(), err))
+	}
+}
+
+// watchdog sends Device-Watchdog-Request towards the peer when idle, closing the connection after repeated failures
+func (self *DiameterAgent) watchdog(p *diameterPeer) {
+	interval := self.cgrCfg.DiameterAgentCfg().WatchdogInterval
+	for {
+		select {
+		case <-p.stopWatchdog:
+			return
+		case <-time.After(interval):
+		}
+		self.peersMux.RLock()
+		lastActivity := p.info.LastActivity
+		self.peersMux.RUnlock()
+		if time.Since(lastActivity) < interval { // traffic on connection, no need of watchdog
+			continue
+		}
+		dwr := diam.NewRequest(diam.DeviceWatchdog, 0, nil)
+		dwr.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity(p.listener.OriginHost))
+		dwr.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity(p.listener.OriginRealm))
+		ans, err := self.sendRequest(p.conn, dwr)
+		if err == nil {
+			err = resultCodeAsError(ans)
+		}
+		self.peersMux.Lock()
+		if err == nil {
+			p.info.State = DiameterPeerOpen
+			p.info.WatchdogFailures = 0
+			p.info.LastActivity = time.Now()
+			self.peersMux.Unlock()
+			continue
+		}
+		p.info.State = DiameterPeerSuspect
+		p.info.WatchdogFailures += 1
+		failures := p.info.WatchdogFailures
+		self.peersMux.Unlock()
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Watchdog failed for peer: %s, error: %s", p.info.OriginHost, err))
+		if failures >= diameterMaxWatchdogFailures {
+			utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Closing connection to unresponsive peer: %s", p.info.OriginHost))
+			self.removePeer(p.conn)
+			p.conn.Close()
+			return
+		}
+	}
+}
+
+// acceptPeer checks that the connection passed capabilities exchange, closing it otherwise as RFC 6733 requires
+func (self *DiameterAgent) acceptPeer(c diam.Conn, m *diam.Message) bool {
+	self.peersMux.RLock()
+	_, hasIt := self.peers[c]
+	self.peersMux.RUnlock()
+	if !hasIt {
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Closing connection from %s, request received before capabilities exchange:\n%s",
+			c.RemoteAddr(), m))
+		c.Close()
+	}
+	return hasIt
+}
+
+// touchPeer records activity on the peer connection
+func (self *DiameterAgent) touchPeer(c diam.Conn) {
+	self.peersMux.Lock()
+	if p, hasIt := self.peers[c]; hasIt {
+		p.info.LastActivity = time.Now()
+	}
+	self.peersMux.Unlock()
+}
+
+func (self *DiameterAgent) removePeer(c diam.Conn) {
+	self.peersMux.Lock()
+	if p, hasIt := self.peers[c]; hasIt {
+		close(p.stopWatchdog)
+		delete(self.peers, c)
+	}
+	self.peersMux.Unlock()
+	self.removeConnSessions(c)
+}
+
+// connListener returns the configuration of the listener the connection came in, defaulting to the main one
+func (self *DiameterAgent) connListener(c diam.Conn) *config.DiameterListenerCfg {
+	self.peersMux.RLock()
+	defer self.peersMux.RUnlock()
+	if p, hasIt := self.peers[c]; hasIt {
+		return p.listener
+	}
+	return self.listeners()[0]
+}
+
+// V1GetPeers returns the state of the peers connected to our listeners
+func (self *DiameterAgent) V1GetPeers(ignored string, reply *[]*utils.DiameterPeer) error {
+	self.peersMux.RLock()
+	peers := make([]*utils.DiameterPeer, 0, len(self.peers))
+	for _, p := range self.peers {
+		pInfo := p.info
+		peers = append(peers, &pInfo)
+	}
+	self.peersMux.RUnlock()
+	if len(peers) == 0 {
+		return utils.ErrNotFound
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ConnectedAt.Before(peers[j].ConnectedAt)
+	})
+	*reply = peers
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/fiorix/go-diameter/diam"
+)
+
+func TestCommonApplications(t *testing.T) {
+	if common := commonApplications(nil, []int{4, 16777238}); !reflect.DeepEqual([]int{4, 16777238}, common) {
+		t.Errorf("Received: %+v", common)
+	}
+	if common := commonApplications([]int{4}, []int{16777238, 4}); !reflect.DeepEqual([]int{4}, common) {
+		t.Errorf("Received: %+v", common)
+	}
+	if common := commonApplications([]int{4, 16777238}, []int{DiameterRelayApplicationID}); !reflect.DeepEqual([]int{4, 16777238}, common) {
+		t.Errorf("Received: %+v", common)
+	}
+	if common := commonApplications([]int{4}, []int{16777238}); len(common) != 0 {
+		t.Errorf("Received: %+v", common)
+	}
+}
+
+func TestDiameterAgentListeners(t *testing.T) {
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	cgrCfg.DiameterAgentCfg().Applications = []int{4}
+	cgrCfg.DiameterAgentCfg().Listeners = []*config.DiameterListenerCfg{
+		&config.DiameterListenerCfg{Listen: "127.0.0.1:3869", OriginHost: "CGR-DA2"},
+	}
+	da := &DiameterAgent{cgrCfg: cgrCfg}
+	eLstns := []*config.DiameterListenerCfg{
+		&config.DiameterListenerCfg{Listen: "127.0.0.1:3868", ListenNet: "tcp", OriginHost: "CGR-DA",
+			OriginRealm: "cgrates.org", ProductName: "CGRateS", Applications: []int{4}},
+		&config.DiameterListenerCfg{Listen: "127.0.0.1:3869", ListenNet: "tcp", OriginHost: "CGR-DA2",
+			OriginRealm: "cgrates.org", ProductName: "CGRateS", Applications: []int{4}},
+	}
+	if lstns := da.listeners(); !reflect.DeepEqual(eLstns, lstns) {
+		t.Errorf("Expecting: %+v, received: %+v", eLstns, lstns)
+	}
+}
+
+func TestDiameterAgentV1GetPeers(t *testing.T) {
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	da := &DiameterAgent{cgrCfg: cgrCfg,
+		peers: make(map[diam.Conn]*diameterPeer), peersMux: new(sync.RWMutex)}
+	var peers []*utils.DiameterPeer
+	if err := da.V1GetPeers("", &peers); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	lstn := da.listeners()[0]
+	ePeer := utils.DiameterPeer{OriginHost: "pgw1.example.org", OriginRealm: "example.org",
+		RemoteAddr: "10.0.0.1:42000", Listener: lstn.Listen, State: DiameterPeerOpen, Applications: []int{4}}
+	da.peers[nil] = &diameterPeer{listener: lstn, info: ePeer, stopWatchdog: make(chan struct{})}
+	if err := da.V1GetPeers("", &peers); err != nil {
+		t.Error(err)
+	} else if len(peers) != 1 || !reflect.DeepEqual(ePeer, *peers[0]) {
+		t.Errorf("Expecting: %+v, received: %+v", ePeer, peers)
+	}
+	if connLstn := da.connListener(nil); connLstn != lstn {
+		t.Errorf("Expecting: %+v, received: %+v", lstn, connLstn)
+	}
+	da.removePeer(nil)
+	if err := da.V1GetPeers("", &peers); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
@@ -49,14 +49,22 @@ func init() {
 }
 
 const (
-	META_CCR_USAGE       = "*ccr_usage"
-	META_VALUE_EXPONENT  = "*value_exponent"
-	META_SUM             = "*sum"
-	DIAMETER_CCR         = "DIAMETER_CCR"
-	DiameterRatingFailed = 5031
-	CGRError             = "CGRError"
-	CGRMaxUsage          = "CGRMaxUsage"
-	CGRResultCode        = "CGRResultCode"
+	META_CCR_USAGE             = "*ccr_usage"
+	META_VALUE_EXPONENT        = "*value_exponent"
+	META_SUM                   = "*sum"
+	DIAMETER_CCR               = "DIAMETER_CCR"
+	DIAMETER_REQUEST           = "DIAMETER_REQUEST"
+	MetaAuth                   = "*auth"
+	MetaInitiate               = "*initiate"
+	MetaUpdate                 = "*update"
+	MetaTerminate              = "*terminate"
+	MetaEvent                  = "*event"
+	DiameterRatingFailed       = 5031
+	DiameterCreditLimitReached = 4012
+	CGRError                   = "CGRError"
+	CGRMaxUsage                = "CGRMaxUsage"
+	CGRResultCode              = "CGRResultCode"
+	RatingGroup                = "RatingGroup"
 )
 
 var (
@@ -202,7 +210,7 @@ func metaHandler(m *diam.Message, processorVars map[string]string,
 		}
 		switch reqType {
 		case datatype.Enumerated(1), datatype.Enumerated(2):
-			if reqUnitAVPs, err := m.FindAVPsWithPath([]interface{}{"Requested-Service-Unit", "CC-Time"}, dict.UndefinedVendorID); err != nil {
+			if reqUnitAVPs, err := ccTimeAVPs(m, "Requested-Service-Unit"); err != nil {
 				return "", err
 			} else if len(reqUnitAVPs) == 0 {
 				return "", errors.New("Requested-Service-Unit>CC-Time not found")
@@ -210,7 +218,7 @@ func metaHandler(m *diam.Message, processorVars map[string]string,
 				return "", fmt.Errorf("Requested-Service-Unit>CC-Time must be Unsigned32 and not %v", reqUnitAVPs[0].Data.Type())
 			}
 		case datatype.Enumerated(3), datatype.Enumerated(4):
-			if usedUnitAVPs, err := m.FindAVPsWithPath([]interface{}{"Used-Service-Unit", "CC-Time"}, dict.UndefinedVendorID); err != nil {
+			if usedUnitAVPs, err := ccTimeAVPs(m, "Used-Service-Unit"); err != nil {
 				return "", err
 			} else if len(usedUnitAVPs) != 0 {
 				if usedUnit, ok = usedUnitAVPs[0].Data.(datatype.Unsigned32); !ok {
@@ -223,6 +231,14 @@ func metaHandler(m *diam.Message, processorVars map[string]string,
 	return "", nil
 }
 
+// ccTimeAVPs returns the CC-Time AVPs within service unit, looking also inside Multiple-Services-Credit-Control
+func ccTimeAVPs(m *diam.Message, serviceUnit string) ([]*diam.AVP, error) {
+	if avps, err := m.FindAVPsWithPath([]interface{}{serviceUnit, "CC-Time"}, dict.UndefinedVendorID); err != nil || len(avps) != 0 {
+		return avps, err
+	}
+	return m.FindAVPsWithPath([]interface{}{"Multiple-Services-Credit-Control", serviceUnit, "CC-Time"}, dict.UndefinedVendorID)
+}
+
 // metaValueExponent will multiply the float value with the exponent provided.
 // Expects 2 arguments in template separated by |
 func metaValueExponent(m *diam.Message, processorVars map[string]string,
@@ -543,6 +559,7 @@ type CCR struct {
 	} `avp:"Service-Information"`
 	diamMessage   *diam.Message // Used to parse fields with CGR templates
 	debitInterval time.Duration // Configured debit interval
+	conn          diam.Conn     // Connection the CCR was received on
 }
 
 // AsBareDiameterMessage converts CCR into a bare DiameterMessage
@@ -621,10 +638,16 @@ func (self *CCR) AsDiameterMessage() (*diam.Message, error) {
 
 // Extracts data out of CCR into a SMGenericEvent based on the configured template
 func (self *CCR) AsSMGenericEvent(cfgFlds []*config.CfgCdrField) (sessionmanager.SMGenericEvent, error) {
+	return messageAsSMGenericEvent(self.diamMessage, cfgFlds, DIAMETER_CCR, self.debitInterval)
+}
+
+// messageAsSMGenericEvent converts a diameter message into SMGenericEvent based on the request template
+func messageAsSMGenericEvent(m *diam.Message, cfgFlds []*config.CfgCdrField, eventName string,
+	debitInterval time.Duration) (sessionmanager.SMGenericEvent, error) {
 	outMap := make(map[string]string) // work with it so we can append values to keys
-	outMap[utils.EVENT_NAME] = DIAMETER_CCR
+	outMap[utils.EVENT_NAME] = eventName
 	for _, cfgFld := range cfgFlds {
-		fmtOut, err := fieldOutVal(self.diamMessage, cfgFld, self.debitInterval, nil)
+		fmtOut, err := fieldOutVal(m, cfgFld, debitInterval, nil)
 		if err != nil {
 			if err == ErrFilterNotPassing {
 				continue // Do nothing in case of Filter not passing
@@ -644,6 +667,137 @@ func (self *CCR) AsSMGenericEvent(cfgFlds []*config.CfgCdrField) (sessionmanager
 	return sessionmanager.SMGenericEvent(utils.ConvertMapValStrIf(outMap)), nil
 }
 
+// multipleServicesCCRs splits the CCR into one CCR per Multiple-Services-Credit-Control AVP,
+// each of them keeping the rest of the AVPs so the same templates can be applied
+func (self *CCR) multipleServicesCCRs() (msccCCRs []*CCR) {
+	for _, a := range self.diamMessage.AVP {
+		if a.Code == 456 { // Multiple-Services-Credit-Control
+			msccCCRs = append(msccCCRs, self.withMSCC(a))
+		}
+	}
+	return
+}
+
+// ratingGroupsCCRs builds one CCR for each of the ratingGroups, carrying a Multiple-Services-Credit-Control
+// with just the Rating-Group (ie: to terminate the rating groups not reported in a CCR-T)
+func (self *CCR) ratingGroupsCCRs(ratingGroups []string) (msccCCRs []*CCR, err error) {
+	for _, ratingGroup := range ratingGroups {
+		var msccAVPs []*diam.AVP
+		if ratingGroup != "" {
+			rg, err := strconv.ParseUint(ratingGroup, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			msccAVPs = append(msccAVPs, diam.NewAVP(432, avp.Mbit, 0, datatype.Unsigned32(rg))) // Rating-Group
+		}
+		msccCCRs = append(msccCCRs, self.withMSCC(
+			diam.NewAVP(456, avp.Mbit, 0, &diam.GroupedAVP{AVP: msccAVPs})))
+	}
+	return
+}
+
+// withMSCC returns a copy of the CCR having msccAVP as the only Multiple-Services-Credit-Control
+func (self *CCR) withMSCC(msccAVP *diam.AVP) *CCR {
+	m := diam.NewMessage(self.diamMessage.Header.CommandCode, self.diamMessage.Header.CommandFlags, self.diamMessage.Header.ApplicationID,
+		self.diamMessage.Header.HopByHopID, self.diamMessage.Header.EndToEndID, self.diamMessage.Dictionary())
+	for _, a := range self.diamMessage.AVP {
+		if a.Code != 456 { // Multiple-Services-Credit-Control
+			m.AddAVP(a)
+		}
+	}
+	m.AddAVP(msccAVP)
+	msccCCR := *self
+	msccCCR.diamMessage = m
+	return &msccCCR
+}
+
+// msccRatingGroup returns the Rating-Group of the first Multiple-Services-Credit-Control AVP in the CCR
+func (self *CCR) msccRatingGroup() string {
+	avps, err := self.diamMessage.FindAVPsWithPath([]interface{}{"Multiple-Services-Credit-Control", "Rating-Group"}, dict.UndefinedVendorID)
+	if err != nil || len(avps) == 0 {
+		return ""
+	}
+	return avpValAsString(avps[0])
+}
+
+// msccRequestsVolume checks if the Multiple-Services-Credit-Control AVP is requesting volume instead of time units
+func (self *CCR) msccRequestsVolume() bool {
+	for _, unitAVP := range []string{"CC-Total-Octets", "CC-Input-Octets", "CC-Output-Octets"} {
+		if avps, err := self.diamMessage.FindAVPsWithPath([]interface{}{"Multiple-Services-Credit-Control", "Requested-Service-Unit", unitAVP},
+			dict.UndefinedVendorID); err == nil && len(avps) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func newDmtSession(ccr *CCR) *dmtSession {
+	return &dmtSession{conn: ccr.conn, sessionID: ccr.SessionId,
+		peerHost: ccr.OriginHost, peerRealm: ccr.OriginRealm,
+		applicationID: ccr.diamMessage.Header.ApplicationID, authApplicationID: ccr.AuthApplicationId}
+}
+
+// dmtSession holds the details needed to send requests towards the peer owning the session
+type dmtSession struct {
+	conn              diam.Conn
+	sessionID         string
+	peerHost          string
+	peerRealm         string
+	applicationID     uint32
+	authApplicationID int
+}
+
+// asDiameterRequest builds a server initiated request for the session (eg: RAR, ASR)
+func (self *dmtSession) asDiameterRequest(cmdCode uint32, originHost, originRealm string) *diam.Message {
+	m := diam.NewRequest(cmdCode, self.applicationID, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String(self.sessionID))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity(originHost))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity(originRealm))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity(self.peerRealm))
+	m.NewAVP(avp.DestinationHost, avp.Mbit, 0, datatype.DiameterIdentity(self.peerHost))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(self.authApplicationID))
+	if cmdCode == diam.ReAuth {
+		m.NewAVP(285, avp.Mbit, 0, datatype.Enumerated(0)) // Re-Auth-Request-Type: AUTHORIZE_ONLY
+	}
+	return m
+}
+
+// resultCodeAsError returns error if the answer is not carrying DIAMETER_SUCCESS as Result-Code
+func resultCodeAsError(m *diam.Message) error {
+	rcAVP, err := m.FindAVP("Result-Code", 0)
+	if err != nil {
+		return err
+	} else if rcAVP == nil {
+		return errors.New("Result-Code not found")
+	}
+	if rc := avpValAsString(rcAVP); rc != strconv.Itoa(diam.Success) {
+		return fmt.Errorf("Unexpected Result-Code: %s", rc)
+	}
+	return nil
+}
+
+// processorMatches checks if the request processor is configured for the command and application of the message
+func processorMatches(reqProcessor *config.DARequestProcessor, m *diam.Message) bool {
+	return uint32(reqProcessor.CommandCode) == m.Header.CommandCode &&
+		(reqProcessor.ApplicationID == 0 || uint32(reqProcessor.ApplicationID) == m.Header.ApplicationID)
+}
+
+// newBareAnswer builds the answer for a generic request, echoing Session-Id and Auth-Application-Id when present
+func newBareAnswer(m *diam.Message, originHost, originRealm string) *diam.Message {
+	ans := diam.NewMessage(m.Header.CommandCode, m.Header.CommandFlags&^diam.RequestFlag, m.Header.ApplicationID,
+		m.Header.HopByHopID, m.Header.EndToEndID, m.Dictionary())
+	if sIDAVP, err := m.FindAVP(avp.SessionID, 0); err == nil && sIDAVP != nil {
+		ans.AddAVP(sIDAVP)
+	}
+	ans.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity(originHost))
+	ans.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity(originRealm))
+	if authAppAVP, err := m.FindAVP(avp.AuthApplicationID, 0); err == nil && authAppAVP != nil {
+		ans.AddAVP(authAppAVP)
+	}
+	ans.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.Success))
+	return ans
+}
+
 func NewBareCCAFromCCR(ccr *CCR, originHost, originRealm string) *CCA {
 	cca := &CCA{SessionId: ccr.SessionId, AuthApplicationId: ccr.AuthApplicationId, CCRequestType: ccr.CCRequestType, CCRequestNumber: ccr.CCRequestNumber,
 		OriginHost: originHost, OriginRealm: originRealm,
@@ -693,10 +847,16 @@ func (self *CCA) AsDiameterMessage() *diam.Message {
 
 // SetProcessorAVPs will add AVPs to self.diameterMessage based on template defined in processor.CCAFields
 func (self *CCA) SetProcessorAVPs(reqProcessor *config.DARequestProcessor, processorVars map[string]string) error {
-	for _, cfgFld := range reqProcessor.CCAFields {
-		fmtOut, err := fieldOutVal(self.ccrMessage, cfgFld, nil, processorVars)
+	return setAnswerAVPs(self.ccrMessage, self.diamMessage, reqProcessor.CCAFields, processorVars, self.timezone)
+}
+
+// setAnswerAVPs populates the answer based on template, values being searched first in request and then in answer
+func setAnswerAVPs(reqMsg, ansMsg *diam.Message, cfgFlds []*config.CfgCdrField,
+	processorVars map[string]string, timezone string) error {
+	for _, cfgFld := range cfgFlds {
+		fmtOut, err := fieldOutVal(reqMsg, cfgFld, nil, processorVars)
 		if err == ErrFilterNotPassing { // Field not in or filter not passing, try match in answer
-			fmtOut, err = fieldOutVal(self.diamMessage, cfgFld, nil, processorVars)
+			fmtOut, err = fieldOutVal(ansMsg, cfgFld, nil, processorVars)
 		}
 		if err != nil {
 			if err == ErrFilterNotPassing {
@@ -704,9 +864,9 @@ func (self *CCA) SetProcessorAVPs(reqProcessor *config.DARequestProcessor, proce
 			}
 			return err
 		}
-		if err := messageSetAVPsWithPath(self.diamMessage,
+		if err := messageSetAVPsWithPath(ansMsg,
 			splitIntoInterface(cfgFld.FieldId, utils.HIERARCHY_SEP),
-			fmtOut, cfgFld.Append, self.timezone); err != nil {
+			fmtOut, cfgFld.Append, timezone); err != nil {
 			return err
 		}
 		if cfgFld.BreakOnSuccess { // don't look for another field
@@ -715,3 +875,30 @@ func (self *CCA) SetProcessorAVPs(reqProcessor *config.DARequestProcessor, proce
 	}
 	return nil
 }
+
+// AddMultipleServicesCreditControl appends one Multiple-Services-Credit-Control AVP to the answer
+// grantUnits will add Granted-Service-Unit with maxUsage as CC-Total-Octets for volume or CC-Time otherwise
+func (self *CCA) AddMultipleServicesCreditControl(ratingGroup string, resultCode int,
+	grantUnits, volume bool, maxUsage time.Duration) error {
+	var msccAVPs []*diam.AVP
+	if grantUnits {
+		var unitAVP *diam.AVP
+		if volume {
+			unitAVP = diam.NewAVP(421, avp.Mbit, 0, datatype.Unsigned64(maxUsage.Nanoseconds())) // CC-Total-Octets
+		} else {
+			unitAVP = diam.NewAVP(420, avp.Mbit, 0, datatype.Unsigned32(maxUsage.Seconds())) // CC-Time
+		}
+		msccAVPs = append(msccAVPs, diam.NewAVP(431, avp.Mbit, 0, &diam.GroupedAVP{ // Granted-Service-Unit
+			AVP: []*diam.AVP{unitAVP}}))
+	}
+	if ratingGroup != "" {
+		rg, err := strconv.ParseUint(ratingGroup, 10, 32)
+		if err != nil {
+			return err
+		}
+		msccAVPs = append(msccAVPs, diam.NewAVP(432, avp.Mbit, 0, datatype.Unsigned32(rg))) // Rating-Group
+	}
+	msccAVPs = append(msccAVPs, diam.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(resultCode)))
+	_, err := self.diamMessage.NewAVP("Multiple-Services-Credit-Control", avp.Mbit, 0, &diam.GroupedAVP{AVP: msccAVPs})
+	return err
+}
@@ -512,3 +512,236 @@ func TestPassesFieldFilter(t *testing.T) {
 		t.Error("Does not pass")
 	}
 }
+
+func TestCCRMultipleServicesCCRs(t *testing.T) {
+	ccr := &CCR{
+		SessionId:         "ccrmscc1",
+		AuthApplicationId: 4,
+		CCRequestType:     2,
+		CCRequestNumber:   1,
+	}
+	ccr.diamMessage = ccr.AsBareDiameterMessage()
+	if msccCCRs := ccr.multipleServicesCCRs(); len(msccCCRs) != 0 {
+		t.Errorf("Unexpected CCRs: %+v", msccCCRs)
+	}
+	ccr.diamMessage.NewAVP("Multiple-Services-Credit-Control", avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(437, avp.Mbit, 0, &diam.GroupedAVP{ // Requested-Service-Unit
+				AVP: []*diam.AVP{
+					diam.NewAVP(420, avp.Mbit, 0, datatype.Unsigned32(300)), // CC-Time
+				},
+			}),
+			diam.NewAVP(432, avp.Mbit, 0, datatype.Unsigned32(1)), // Rating-Group
+		},
+	})
+	ccr.diamMessage.NewAVP("Multiple-Services-Credit-Control", avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(437, avp.Mbit, 0, &diam.GroupedAVP{ // Requested-Service-Unit
+				AVP: []*diam.AVP{
+					diam.NewAVP(421, avp.Mbit, 0, datatype.Unsigned64(1048576)), // CC-Total-Octets
+				},
+			}),
+			diam.NewAVP(432, avp.Mbit, 0, datatype.Unsigned32(2)), // Rating-Group
+		},
+	})
+	msccCCRs := ccr.multipleServicesCCRs()
+	if len(msccCCRs) != 2 {
+		t.Fatalf("Unexpected CCRs: %+v", msccCCRs)
+	}
+	for i, eRatingGroup := range []string{"1", "2"} {
+		if avps, err := msccCCRs[i].diamMessage.FindAVPsWithPath([]interface{}{"Multiple-Services-Credit-Control"}, dict.UndefinedVendorID); err != nil {
+			t.Error(err)
+		} else if len(avps) != 1 {
+			t.Errorf("Unexpected MSCC AVPs: %+v", avps)
+		}
+		if ratingGroup := msccCCRs[i].msccRatingGroup(); ratingGroup != eRatingGroup {
+			t.Errorf("Expecting: %s, received: %s", eRatingGroup, ratingGroup)
+		}
+		if msccCCRs[i].SessionId != ccr.SessionId || msccCCRs[i].CCRequestType != ccr.CCRequestType {
+			t.Errorf("Unexpected CCR: %+v", msccCCRs[i])
+		}
+	}
+	if msccCCRs[0].msccRequestsVolume() {
+		t.Error("First MSCC should request time")
+	}
+	if !msccCCRs[1].msccRequestsVolume() {
+		t.Error("Second MSCC should request volume")
+	}
+	if usage, err := metaHandler(msccCCRs[0].diamMessage, nil, META_CCR_USAGE, "", time.Duration(300)*time.Second); err != nil {
+		t.Error(err)
+	} else if usage != "5m0s" {
+		t.Errorf("Unexpected usage: %s", usage)
+	}
+}
+
+func TestCCRRatingGroupsCCRs(t *testing.T) {
+	ccr := &CCR{
+		SessionId:         "ccrmscc3",
+		AuthApplicationId: 4,
+		CCRequestType:     3,
+		CCRequestNumber:   2,
+	}
+	ccr.diamMessage = ccr.AsBareDiameterMessage()
+	msccCCRs, err := ccr.ratingGroupsCCRs([]string{"1", ""})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(msccCCRs) != 2 {
+		t.Fatalf("Unexpected CCRs: %+v", msccCCRs)
+	}
+	for i, eRatingGroup := range []string{"1", ""} {
+		if avps, err := msccCCRs[i].diamMessage.FindAVPsWithPath([]interface{}{"Multiple-Services-Credit-Control"}, dict.UndefinedVendorID); err != nil {
+			t.Error(err)
+		} else if len(avps) != 1 {
+			t.Errorf("Unexpected MSCC AVPs: %+v", avps)
+		}
+		if ratingGroup := msccCCRs[i].msccRatingGroup(); ratingGroup != eRatingGroup {
+			t.Errorf("Expecting: %s, received: %s", eRatingGroup, ratingGroup)
+		}
+		if msccCCRs[i].SessionId != ccr.SessionId || msccCCRs[i].CCRequestType != ccr.CCRequestType {
+			t.Errorf("Unexpected CCR: %+v", msccCCRs[i])
+		}
+	}
+	if _, err := ccr.ratingGroupsCCRs([]string{"notanumber"}); err == nil {
+		t.Error("Expecting error for invalid rating group")
+	}
+}
+
+func TestCCAAddMultipleServicesCreditControl(t *testing.T) {
+	ccr := &CCR{
+		SessionId:         "ccrmscc2",
+		AuthApplicationId: 4,
+		CCRequestType:     1,
+		CCRequestNumber:   0,
+	}
+	ccr.diamMessage = ccr.AsBareDiameterMessage()
+	cca := NewBareCCAFromCCR(ccr, "CGR-DA", "cgrates.org")
+	eMessage := NewBareCCAFromCCR(ccr, "CGR-DA", "cgrates.org").AsDiameterMessage()
+	eMessage.NewAVP("Multiple-Services-Credit-Control", avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(431, avp.Mbit, 0, &diam.GroupedAVP{ // Granted-Service-Unit
+				AVP: []*diam.AVP{
+					diam.NewAVP(420, avp.Mbit, 0, datatype.Unsigned32(300)), // CC-Time
+				},
+			}),
+			diam.NewAVP(432, avp.Mbit, 0, datatype.Unsigned32(1)), // Rating-Group
+			diam.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.Success)),
+		},
+	})
+	eMessage.NewAVP("Multiple-Services-Credit-Control", avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(431, avp.Mbit, 0, &diam.GroupedAVP{ // Granted-Service-Unit
+				AVP: []*diam.AVP{
+					diam.NewAVP(421, avp.Mbit, 0, datatype.Unsigned64(0)), // CC-Total-Octets
+				},
+			}),
+			diam.NewAVP(432, avp.Mbit, 0, datatype.Unsigned32(2)), // Rating-Group
+			diam.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(DiameterCreditLimitReached)),
+		},
+	})
+	if err := cca.AddMultipleServicesCreditControl("1", diam.Success, true, false, time.Duration(300)*time.Second); err != nil {
+		t.Error(err)
+	}
+	if err := cca.AddMultipleServicesCreditControl("2", DiameterCreditLimitReached, true, true, 0); err != nil {
+		t.Error(err)
+	}
+	if ccaMsg := cca.AsDiameterMessage(); fmt.Sprintf("%q", eMessage) != fmt.Sprintf("%q", ccaMsg) {
+		t.Errorf("Expecting: %+v, received: %+v", eMessage, ccaMsg)
+	}
+	if err := cca.AddMultipleServicesCreditControl("invalid", diam.Success, false, false, 0); err == nil {
+		t.Error("Expecting error for invalid rating group")
+	}
+}
+
+func TestDmtSessionAsDiameterRequest(t *testing.T) {
+	ccr := &CCR{
+		SessionId:         "dmtsess1",
+		OriginHost:        "client.example.org",
+		OriginRealm:       "example.org",
+		AuthApplicationId: 4,
+		CCRequestType:     1,
+	}
+	ccr.diamMessage = ccr.AsBareDiameterMessage()
+	dSess := newDmtSession(ccr)
+	asr := dSess.asDiameterRequest(diam.AbortSession, "CGR-DA", "cgrates.org")
+	if asr.Header.CommandCode != diam.AbortSession || asr.Header.CommandFlags&diam.RequestFlag == 0 {
+		t.Errorf("Unexpected header: %+v", asr.Header)
+	}
+	for avpName, eVal := range map[string]string{
+		"Session-Id":          "dmtsess1",
+		"Origin-Host":         "CGR-DA",
+		"Origin-Realm":        "cgrates.org",
+		"Destination-Host":    "client.example.org",
+		"Destination-Realm":   "example.org",
+		"Auth-Application-Id": "4",
+	} {
+		if a, err := asr.FindAVP(avpName, 0); err != nil {
+			t.Error(err)
+		} else if a == nil {
+			t.Errorf("AVP %s not found", avpName)
+		} else if val := avpValAsString(a); val != eVal {
+			t.Errorf("AVP %s, expecting: %s, received: %s", avpName, eVal, val)
+		}
+	}
+	if a, err := asr.FindAVP("Re-Auth-Request-Type", 0); err != nil {
+		t.Error(err)
+	} else if a != nil {
+		t.Errorf("Unexpected Re-Auth-Request-Type in ASR: %+v", a)
+	}
+	rar := dSess.asDiameterRequest(diam.ReAuth, "CGR-DA", "cgrates.org")
+	if rar.Header.CommandCode != diam.ReAuth {
+		t.Errorf("Unexpected header: %+v", rar.Header)
+	}
+	if a, err := rar.FindAVP("Re-Auth-Request-Type", 0); err != nil {
+		t.Error(err)
+	} else if a == nil {
+		t.Error("Re-Auth-Request-Type not found in RAR")
+	}
+}
+
+func TestResultCodeAsError(t *testing.T) {
+	m := diam.NewMessage(diam.AbortSession, 0, 4, 1, 1, nil)
+	if err := resultCodeAsError(m); err == nil {
+		t.Error("Expecting error for missing Result-Code")
+	}
+	m.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.Success))
+	if err := resultCodeAsError(m); err != nil {
+		t.Error(err)
+	}
+	m = diam.NewMessage(diam.ReAuth, 0, 4, 1, 1, nil)
+	m.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(5002)) // DIAMETER_UNKNOWN_SESSION_ID
+	if err := resultCodeAsError(m); err == nil {
+		t.Error("Expecting error for unsuccessful Result-Code")
+	}
+}
+
+func TestProcessorMatches(t *testing.T) {
+	m := diam.NewMessage(306, diam.RequestFlag, 16777217, 1, 1, nil) // Sh User-Data-Request
+	if !processorMatches(&config.DARequestProcessor{CommandCode: 306}, m) {
+		t.Error("Expecting processor to match any application")
+	}
+	if !processorMatches(&config.DARequestProcessor{CommandCode: 306, ApplicationID: 16777217}, m) {
+		t.Error("Expecting processor to match")
+	}
+	if processorMatches(&config.DARequestProcessor{CommandCode: 306, ApplicationID: 16777236}, m) {
+		t.Error("Not expecting processor to match other application")
+	}
+	if processorMatches(&config.DARequestProcessor{CommandCode: diam.CreditControl}, m) {
+		t.Error("Not expecting processor to match other command")
+	}
+}
+
+func TestNewBareAnswer(t *testing.T) {
+	m := diam.NewMessage(306, diam.RequestFlag, 16777217, 1, 2, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("shudr1"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(16777217))
+	m.NewAVP(avp.UserName, avp.Mbit, 0, datatype.UTF8String("1001"))
+	eAns := diam.NewMessage(306, 0, 16777217, 1, 2, nil)
+	eAns.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("shudr1"))
+	eAns.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("CGR-DA"))
+	eAns.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("cgrates.org"))
+	eAns.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(16777217))
+	eAns.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.Success))
+	if ans := newBareAnswer(m, "CGR-DA", "cgrates.org"); !reflect.DeepEqual(eAns, ans) {
+		t.Errorf("Expecting: %+v, received: %+v", eAns, ans)
+	}
+}
@@ -169,11 +169,17 @@ func radReqAsSMGEvent(radPkt *radigo.Packet, procVars map[string]string, procFla
 
 // radReplyAppendAttributes appends attributes to a RADIUS reply based on predefined template
 func radReplyAppendAttributes(reply *radigo.Packet, procVars map[string]string,
+	cfgFlds []*config.CfgCdrField) (err error) {
+	return radPktAppendAttributes(reply, reply, procVars, cfgFlds)
+}
+
+// radPktAppendAttributes appends attributes to a RADIUS packet based on predefined template, values being extracted out of srcPkt
+func radPktAppendAttributes(pkt, srcPkt *radigo.Packet, procVars map[string]string,
 	cfgFlds []*config.CfgCdrField) (err error) {
 	for _, cfgFld := range cfgFlds {
 		passedAllFilters := true
 		for _, fldFilter := range cfgFld.FieldFilter {
-			if !radPassesFieldFilter(reply, procVars, fldFilter) {
+			if !radPassesFieldFilter(srcPkt, procVars, fldFilter) {
 				passedAllFilters = false
 				break
 			}
@@ -181,18 +187,18 @@ func radReplyAppendAttributes(reply *radigo.Packet, procVars map[string]string,
 		if !passedAllFilters {
 			continue
 		}
-		fmtOut, err := radFieldOutVal(reply, procVars, cfgFld)
+		fmtOut, err := radFieldOutVal(srcPkt, procVars, cfgFld)
 		if err != nil {
 			return err
 		}
 		if cfgFld.FieldId == MetaRadReplyCode { // Special case used to control the reply code of RADIUS reply
-			if err = reply.SetCodeWithName(fmtOut); err != nil {
+			if err = pkt.SetCodeWithName(fmtOut); err != nil {
 				return err
 			}
 			continue
 		}
 		attrName, vendorName := attrVendorFromPath(cfgFld.FieldId)
-		if err = reply.AddAVPWithName(attrName, fmtOut, vendorName); err != nil {
+		if err = pkt.AddAVPWithName(attrName, fmtOut, vendorName); err != nil {
 			return err
 		}
 		if cfgFld.BreakOnSuccess {
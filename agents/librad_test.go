@@ -278,3 +278,42 @@ func TestRadReplyAppendAttributes(t *testing.T) {
 		t.Errorf("Expecting: 30, received: %s", avps[0].GetStringValue())
 	}
 }
+
+func TestRadPktAppendAttributes(t *testing.T) {
+	acctReq := radigo.NewPacket(radigo.AccountingRequest, 3, dictRad, coder, "CGRateS.org")
+	if err := acctReq.AddAVPWithName("User-Name", "1001", ""); err != nil {
+		t.Error(err)
+	}
+	if err := acctReq.AddAVPWithName("Acct-Session-Id", "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0", ""); err != nil {
+		t.Error(err)
+	}
+	acctReq.SetAVPValues()
+	dmReq := radigo.NewPacket(radDisconnectRequest, 4, dictRad, coder, "CGRateS.org")
+	dmFlds := []*config.CfgCdrField{
+		&config.CfgCdrField{Tag: "UserName", FieldId: "User-Name", Type: utils.META_COMPOSED,
+			FieldFilter: utils.ParseRSRFieldsMustCompile("User-Name", utils.INFIELD_SEP),
+			Value:       utils.ParseRSRFieldsMustCompile("User-Name", utils.INFIELD_SEP)},
+		&config.CfgCdrField{Tag: "AcctSessionId", FieldId: "Acct-Session-Id", Type: utils.META_COMPOSED,
+			FieldFilter: utils.ParseRSRFieldsMustCompile("Acct-Session-Id", utils.INFIELD_SEP),
+			Value:       utils.ParseRSRFieldsMustCompile("Acct-Session-Id", utils.INFIELD_SEP)},
+		&config.CfgCdrField{Tag: "NASIPAddress", FieldId: "NAS-IP-Address", Type: utils.META_COMPOSED,
+			FieldFilter: utils.ParseRSRFieldsMustCompile("NAS-IP-Address", utils.INFIELD_SEP),
+			Value:       utils.ParseRSRFieldsMustCompile("NAS-IP-Address", utils.INFIELD_SEP)},
+	}
+	if err := radPktAppendAttributes(dmReq, acctReq, map[string]string{MetaRadReqType: MetaRadDisconnect}, dmFlds); err != nil {
+		t.Error(err)
+	}
+	if avps := dmReq.AttributesWithName("User-Name", ""); len(avps) == 0 {
+		t.Error("Cannot find User-Name in request")
+	} else if avps[0].GetStringValue() != "1001" {
+		t.Errorf("Expecting: 1001, received: %s", avps[0].GetStringValue())
+	}
+	if avps := dmReq.AttributesWithName("Acct-Session-Id", ""); len(avps) == 0 {
+		t.Error("Cannot find Acct-Session-Id in request")
+	} else if avps[0].GetStringValue() != "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0" {
+		t.Errorf("Received: %s", avps[0].GetStringValue())
+	}
+	if avps := dmReq.AttributesWithName("NAS-IP-Address", ""); len(avps) != 0 { // not present in accounting request
+		t.Errorf("Unexpected NAS-IP-Address: %+v", avps)
+	}
+}
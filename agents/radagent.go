@@ -21,6 +21,7 @@ package agents
 import (
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
@@ -54,8 +55,9 @@ func NewRadiusAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnection)
 		}
 	}
 	dicts := radigo.NewDictionaries(dts)
-	ra = &RadiusAgent{cgrCfg: cgrCfg, smg: smg}
 	secrets := radigo.NewSecrets(cgrCfg.RadiusAgentCfg().ClientSecrets)
+	ra = &RadiusAgent{cgrCfg: cgrCfg, smg: smg, dicts: dicts, secrets: secrets, coder: radigo.NewCoder(),
+		sessions: make(map[string]*radSession), sessionsMux: new(sync.RWMutex), dynAuthMux: new(sync.Mutex)}
 	ra.rsAuth = radigo.NewServer(cgrCfg.RadiusAgentCfg().ListenNet,
 		cgrCfg.RadiusAgentCfg().ListenAuth, secrets, dicts,
 		map[radigo.PacketCode]func(*radigo.Packet) (*radigo.Packet, error){
@@ -69,10 +71,18 @@ func NewRadiusAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnection)
 }
 
 type RadiusAgent struct {
-	cgrCfg *config.CGRConfig             // reference for future config reloads
-	smg    rpcclient.RpcClientConnection // Connection towards CGR-SMG component
-	rsAuth *radigo.Server
-	rsAcct *radigo.Server
+	cgrCfg        *config.CGRConfig             // reference for future config reloads
+	smg           rpcclient.RpcClientConnection // Connection towards CGR-SMG component
+	rsAuth        *radigo.Server
+	rsAcct        *radigo.Server
+	dicts         *radigo.Dictionaries
+	secrets       *radigo.Secrets
+	coder         radigo.Coder           // encodes the Dynamic Authorization requests
+	sessions      map[string]*radSession // sessions indexed on CGRID, used to reach the client owning them
+	sessionsMux   *sync.RWMutex          // protect sessions and sessionsSwept
+	sessionsSwept time.Time              // last time sessions were checked for expiry
+	dynAuthMux    *sync.Mutex            // protect dynAuthID
+	dynAuthID     uint8                  // identifier of the last Dynamic Authorization request sent
 }
 
 // handleAuth handles RADIUS Authorization request
@@ -211,6 +221,14 @@ func (ra *RadiusAgent) processRequest(reqProcessor *config.RARequestProcessor,
 		default:
 			err = fmt.Errorf("unsupported radius request type: <%s>", processorVars[MetaRadReqType])
 		}
+		switch processorVars[MetaRadReqType] { // keep track of the client owning the session so we can reach it with Disconnect/CoA
+		case MetaRadAcctStart, MetaRadAcctUpdate:
+			if err == nil {
+				ra.setSession(smgEv.GetCGRID(utils.META_DEFAULT), req)
+			}
+		case MetaRadAcctStop:
+			ra.removeSession(smgEv.GetCGRID(utils.META_DEFAULT))
+		}
 		if err != nil {
 			processorVars[MetaCGRError] = err.Error()
 			return false, err
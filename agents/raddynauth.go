@@ -0,0 +1,303 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/sessionmanager"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/radigo"
+	"github.com/cgrates/rpcclient"
+)
+
+const (
+	MetaRadDisconnect = "*radDisconnectReq"
+	MetaRadCoA        = "*radCoAReq"
+	RadDynAuthPort    = "3799" // default Dynamic Authorization port, RFC 5176
+	radHeaderLen      = 20     // Code, Identifier, Length and Authenticator
+)
+
+// Dynamic Authorization packet codes, RFC 5176
+const (
+	radDisconnectRequest radigo.PacketCode = 40
+	radDisconnectACK     radigo.PacketCode = 41
+	radDisconnectNAK     radigo.PacketCode = 42
+	radCoARequest        radigo.PacketCode = 43
+	radCoAACK            radigo.PacketCode = 44
+	radCoANAK            radigo.PacketCode = 45
+)
+
+// radSession holds the details needed to reach the client owning the session with Disconnect and CoA requests
+type radSession struct {
+	clientIP string         // identifies secret, dictionary and Dynamic Authorization address of the client
+	acctReq  *radigo.Packet // last accounting request received for the session, source for request attributes
+	updated  time.Time      // last time the session was refreshed by accounting
+}
+
+// radClientIP returns the IP of the client which sent us the packet
+func radClientIP(pkt *radigo.Packet) string {
+	if pkt.RemoteAddr() == nil { // not received over network
+		return ""
+	}
+	host, _, err := net.SplitHostPort(pkt.RemoteAddr().String())
+	if err != nil {
+		return pkt.RemoteAddr().String()
+	}
+	return host
+}
+
+// setSession tracks the session, forgetting the ones not refreshed within session_ttl (ie: lost Accounting-Stop)
+func (ra *RadiusAgent) setSession(cgrID string, req *radigo.Packet) {
+	clientIP := radClientIP(req)
+	if clientIP == "" {
+		return
+	}
+	now := time.Now()
+	ra.sessionsMux.Lock()
+	ra.sessions[cgrID] = &radSession{clientIP: clientIP, acctReq: req, updated: now}
+	if ttl := ra.cgrCfg.RadiusAgentCfg().SessionTTL; ttl > 0 && now.Sub(ra.sessionsSwept) >= ttl {
+		for sCgrID, rSess := range ra.sessions {
+			if now.Sub(rSess.updated) >= ttl {
+				delete(ra.sessions, sCgrID)
+			}
+		}
+		ra.sessionsSwept = now
+	}
+	ra.sessionsMux.Unlock()
+}
+
+func (ra *RadiusAgent) removeSession(cgrID string) {
+	ra.sessionsMux.Lock()
+	delete(ra.sessions, cgrID)
+	ra.sessionsMux.Unlock()
+}
+
+func (ra *RadiusAgent) getSession(cgrID string) (rSess *radSession, hasIt bool) {
+	ra.sessionsMux.RLock()
+	rSess, hasIt = ra.sessions[cgrID]
+	ra.sessionsMux.RUnlock()
+	if hasIt {
+		if ttl := ra.cgrCfg.RadiusAgentCfg().SessionTTL; ttl > 0 && time.Since(rSess.updated) >= ttl {
+			return nil, false
+		}
+	}
+	return
+}
+
+// dynAuthAddress returns the address where the client listens for Dynamic Authorization requests
+func (ra *RadiusAgent) dynAuthAddress(clientIP string) string {
+	if addr, hasIt := ra.cgrCfg.RadiusAgentCfg().ClientDaAddresses[clientIP]; hasIt {
+		return addr
+	}
+	return net.JoinHostPort(clientIP, RadDynAuthPort)
+}
+
+// nextDynAuthID returns the identifier for the next Dynamic Authorization request
+func (ra *RadiusAgent) nextDynAuthID() uint8 {
+	ra.dynAuthMux.Lock()
+	defer ra.dynAuthMux.Unlock()
+	ra.dynAuthID++
+	return ra.dynAuthID
+}
+
+// exchangeDynAuth sends the request towards the Dynamic Authorization server of the client over own connection,
+// so the reply is waited maximum reply_timeout without leaving anything behind
+func (ra *RadiusAgent) exchangeDynAuth(clientIP string, req *radigo.Packet) (rpl *radigo.Packet, err error) {
+	replyTimeout := ra.cgrCfg.RadiusAgentCfg().ReplyTimeout
+	conn, err := net.DialTimeout(ra.cgrCfg.RadiusAgentCfg().ListenNet, ra.dynAuthAddress(clientIP), replyTimeout)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(replyTimeout)); err != nil {
+		return
+	}
+	var buf [4096]byte
+	n, err := req.Encode(buf[:])
+	if err != nil {
+		return
+	}
+	secret := ra.secrets.GetSecret(clientIP)
+	radSetRequestAuthenticator(buf[:n], secret)
+	reqID, reqAuth := buf[1], append([]byte{}, buf[4:20]...) // the reply needs to match them
+	if _, err = conn.Write(buf[:n]); err != nil {
+		return
+	}
+	for { // discard the replies not answering our request, RFC 5176 2.3
+		if n, err = conn.Read(buf[:]); err != nil {
+			if netErr, isNetErr := err.(net.Error); isNetErr && netErr.Timeout() {
+				err = utils.ErrTimedOut
+			}
+			return
+		}
+		if n >= radHeaderLen {
+			n = radPktLen(buf[:n]) // octets outside Length are padding
+		}
+		if n >= radHeaderLen && buf[1] == reqID &&
+			radResponseAuthenticatorOK(buf[:n], reqAuth, secret) {
+			break
+		}
+		utils.Logger.Warning(fmt.Sprintf("<RadiusAgent> discarding reply from %s with invalid Identifier or Response Authenticator",
+			conn.RemoteAddr()))
+	}
+	rpl = radigo.NewPacket(0, 0, ra.dicts.GetInstance(clientIP), ra.coder, secret)
+	if err = rpl.Decode(buf[:n]); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// radSetRequestAuthenticator computes the Request Authenticator of an encoded Disconnect/CoA-Request, RFC 5176 2.3
+func radSetRequestAuthenticator(encPkt []byte, secret string) {
+	copy(encPkt[4:20], make([]byte, 16))
+	auth := md5.Sum(append(append([]byte{}, encPkt...), secret...))
+	copy(encPkt[4:20], auth[:])
+}
+
+// radPktLen returns the Length out of an encoded packet header, 0 if it does not fit the received octets
+func radPktLen(encPkt []byte) int {
+	if pktLen := int(binary.BigEndian.Uint16(encPkt[2:4])); pktLen <= len(encPkt) {
+		return pktLen
+	}
+	return 0
+}
+
+// radResponseAuthenticatorOK checks the Response Authenticator of an encoded Disconnect/CoA reply
+// against the Request Authenticator of the request it answers, RFC 5176 2.3
+func radResponseAuthenticatorOK(encRpl, reqAuth []byte, secret string) bool {
+	chkPkt := append([]byte{}, encRpl...)
+	copy(chkPkt[4:20], reqAuth)
+	auth := md5.Sum(append(chkPkt, secret...))
+	return hmac.Equal(auth[:], encRpl[4:20])
+}
+
+// sendDynAuthRequest sends Disconnect-Request or CoA-Request towards the client owning the session, waiting for it's ACK/NAK
+func (ra *RadiusAgent) sendDynAuthRequest(eventStart map[string]interface{}, reqType string) (err error) {
+	smgEv := sessionmanager.SMGenericEvent(eventStart)
+	cgrID := smgEv.GetCGRID(utils.META_DEFAULT)
+	rSess, hasIt := ra.getSession(cgrID)
+	if !hasIt {
+		return utils.ErrNotFound
+	}
+	reqCode, ackCode, nakCode := radDisconnectRequest, radDisconnectACK, radDisconnectNAK
+	cfgFlds := ra.cgrCfg.RadiusAgentCfg().DisconnectFields
+	if reqType == MetaRadCoA {
+		reqCode, ackCode, nakCode = radCoARequest, radCoAACK, radCoANAK
+		cfgFlds = ra.cgrCfg.RadiusAgentCfg().CoAFields
+	}
+	procVars, err := smgEv.AsMapStringString() // so the templates can reference session fields
+	if err != nil {
+		return
+	}
+	procVars[MetaRadReqType] = reqType
+	if reqType == MetaRadCoA && ra.smg != nil { // new limits of the session, ie: for Session-Timeout
+		var maxUsage time.Duration
+		if err := ra.smg.Call("SMGenericV2.GetMaxUsage", smgEv, &maxUsage); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<RadiusAgent> Cannot get max usage for session with CGRID: %s, error: %s",
+				cgrID, err))
+		} else if maxUsage > 0 {
+			procVars[MetaCGRMaxUsage] = strconv.Itoa(int(maxUsage))
+		}
+	}
+	req := radigo.NewPacket(reqCode, ra.nextDynAuthID(), ra.dicts.GetInstance(rSess.clientIP),
+		ra.coder, ra.secrets.GetSecret(rSess.clientIP))
+	if err = radPktAppendAttributes(req, rSess.acctReq, procVars, cfgFlds); err != nil {
+		return
+	}
+	rpl, err := ra.exchangeDynAuth(rSess.clientIP, req)
+	if err != nil {
+		return
+	}
+	switch rpl.Code {
+	case ackCode:
+		utils.Logger.Info(fmt.Sprintf("<RadiusAgent> %s for session with CGRID: %s acknowledged by %s",
+			reqType, cgrID, ra.dynAuthAddress(rSess.clientIP)))
+		if reqType == MetaRadDisconnect {
+			ra.removeSession(cgrID)
+		}
+	case nakCode:
+		rpl.SetAVPValues()
+		err = fmt.Errorf("NAK received from %s", ra.dynAuthAddress(rSess.clientIP))
+		if avps := rpl.AttributesWithName("Error-Cause", ""); len(avps) != 0 {
+			errCause := avps[0].GetStringValue()
+			err = fmt.Errorf("NAK received from %s, Error-Cause: %s", ra.dynAuthAddress(rSess.clientIP), errCause)
+			if errCause == "503" || errCause == "Session-Context-Not-Found" { // client does not know the session anymore
+				ra.removeSession(cgrID)
+			}
+		}
+		utils.Logger.Warning(fmt.Sprintf("<RadiusAgent> %s for session with CGRID: %s, error: %s", reqType, cgrID, err))
+	default:
+		err = fmt.Errorf("unexpected reply code: %d", rpl.Code)
+	}
+	return
+}
+
+// Call implements rpcclient.RpcClientConnection interface so SMG can reach us back
+func (ra *RadiusAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	parts := strings.Split(serviceMethod, ".")
+	if len(parts) != 2 {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// get method
+	method := reflect.ValueOf(ra).MethodByName(parts[0][len(parts[0])-2:] + parts[1]) // Inherit the version in the method
+	if !method.IsValid() {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// construct the params
+	params := []reflect.Value{reflect.ValueOf(args), reflect.ValueOf(reply)}
+	ret := method.Call(params)
+	if len(ret) != 1 {
+		return utils.ErrServerError
+	}
+	if ret[0].Interface() == nil {
+		return nil
+	}
+	err, ok := ret[0].Interface().(error)
+	if !ok {
+		return utils.ErrServerError
+	}
+	return err
+}
+
+// V1DisconnectSession sends Disconnect-Request towards the client owning the session
+func (ra *RadiusAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) (err error) {
+	if err = ra.sendDynAuthRequest(args.EventStart, MetaRadDisconnect); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// V1ReAuthorizeSession sends CoA-Request towards the client owning the session so it can apply the new limits
+func (ra *RadiusAgent) V1ReAuthorizeSession(args utils.AttrReAuthorizeSession, reply *string) (err error) {
+	if err = ra.sendDynAuthRequest(args.EventStart, MetaRadCoA); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
@@ -0,0 +1,185 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bytes"
+	"crypto/md5"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/sessionmanager"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/radigo"
+)
+
+func TestRadiusAgentDynAuthAddress(t *testing.T) {
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	cgrCfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{"10.0.0.2": "10.0.0.3:1700"}
+	ra := &RadiusAgent{cgrCfg: cgrCfg}
+	if addr := ra.dynAuthAddress("10.0.0.1"); addr != "10.0.0.1:3799" {
+		t.Errorf("Received: %s", addr)
+	}
+	if addr := ra.dynAuthAddress("10.0.0.2"); addr != "10.0.0.3:1700" {
+		t.Errorf("Received: %s", addr)
+	}
+}
+
+func TestRadiusAgentV1DisconnectSession(t *testing.T) {
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	ra := &RadiusAgent{cgrCfg: cgrCfg,
+		sessions: make(map[string]*radSession), sessionsMux: new(sync.RWMutex), dynAuthMux: new(sync.Mutex)}
+	eventStart := map[string]interface{}{utils.OriginID: "e4921177ab0e3586c37f6a185864b71a@0:0:0:0:0:0:0:0",
+		utils.OriginHost: "127.0.0.1"}
+	var reply string
+	if err := ra.Call("SMGClientV1.DisconnectSession",
+		utils.AttrDisconnectSession{EventStart: eventStart}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := ra.Call("SMGClientV1.ReAuthorizeSession",
+		utils.AttrReAuthorizeSession{EventStart: eventStart}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestRadSetRequestAuthenticator(t *testing.T) {
+	encPkt := []byte{40, 1, 0, 20, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	eAuth := md5.Sum(append([]byte{40, 1, 0, 20, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, "CGRateS.org"...))
+	radSetRequestAuthenticator(encPkt, "CGRateS.org")
+	if !bytes.Equal(eAuth[:], encPkt[4:20]) {
+		t.Errorf("Expecting: %v, received: %v", eAuth, encPkt[4:20])
+	}
+}
+
+// radTestDAServer answers the Dynamic Authorization requests with rplCode, not answering at all on 0
+// the reply is signed with rplSecret and carries the request Identifier increased with rplIDOffset
+func radTestDAServer(t *testing.T, rplCode byte, rplSecret string, rplIDOffset byte) (addr string, reqCodes chan byte) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqCodes = make(chan byte, 1)
+	go func() {
+		defer conn.Close()
+		var buf [4096]byte
+		n, rAddr, err := conn.ReadFrom(buf[:])
+		if err != nil {
+			return
+		}
+		reqAuth := append([]byte{}, buf[4:20]...)
+		radSetRequestAuthenticator(buf[:n], "CGRateS.org")
+		if !bytes.Equal(reqAuth, buf[4:20]) {
+			t.Error("Invalid Request Authenticator")
+		}
+		reqCodes <- buf[0]
+		if rplCode != 0 {
+			rpl := append([]byte{rplCode, buf[1] + rplIDOffset, 0, 20}, reqAuth...)
+			rplAuth := md5.Sum(append(append([]byte{}, rpl...), rplSecret...))
+			copy(rpl[4:20], rplAuth[:])
+			conn.WriteTo(rpl, rAddr)
+		}
+	}()
+	return conn.LocalAddr().String(), reqCodes
+}
+
+func TestRadiusAgentSendDynAuthRequest(t *testing.T) {
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	cgrCfg.RadiusAgentCfg().ReplyTimeout = time.Duration(100 * time.Millisecond)
+	ra := &RadiusAgent{cgrCfg: cgrCfg, coder: coder,
+		dicts:    radigo.NewDictionaries(map[string]*radigo.Dictionary{utils.META_DEFAULT: dictRad}),
+		secrets:  radigo.NewSecrets(map[string]string{utils.META_DEFAULT: "CGRateS.org"}),
+		sessions: make(map[string]*radSession), sessionsMux: new(sync.RWMutex), dynAuthMux: new(sync.Mutex)}
+	acctReq := radigo.NewPacket(radigo.AccountingRequest, 1, dictRad, coder, "CGRateS.org")
+	if err := acctReq.AddAVPWithName("User-Name", "1001", ""); err != nil {
+		t.Fatal(err)
+	}
+	acctReq.SetAVPValues()
+	eventStart := map[string]interface{}{utils.OriginID: "dynauth1", utils.OriginHost: "127.0.0.1"}
+	cgrID := sessionmanager.SMGenericEvent(eventStart).GetCGRID(utils.META_DEFAULT)
+	ra.sessions[cgrID] = &radSession{clientIP: "127.0.0.1", acctReq: acctReq, updated: time.Now()}
+	// CoA rejected, session kept
+	addr, reqCodes := radTestDAServer(t, byte(radCoANAK), "CGRateS.org", 0)
+	cgrCfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{"127.0.0.1": addr}
+	if err := ra.sendDynAuthRequest(eventStart, MetaRadCoA); err == nil {
+		t.Error("Expecting NAK error")
+	}
+	if reqCode := <-reqCodes; reqCode != byte(radCoARequest) {
+		t.Errorf("Received request code: %d", reqCode)
+	}
+	if _, hasIt := ra.getSession(cgrID); !hasIt {
+		t.Error("Session should be kept on CoA-NAK")
+	}
+	// no reply within reply_timeout
+	addr, reqCodes = radTestDAServer(t, 0, "CGRateS.org", 0)
+	cgrCfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{"127.0.0.1": addr}
+	if err := ra.sendDynAuthRequest(eventStart, MetaRadDisconnect); err != utils.ErrTimedOut {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrTimedOut, err)
+	}
+	<-reqCodes
+	// forged reply, discarded
+	addr, reqCodes = radTestDAServer(t, byte(radDisconnectACK), "NotTheSecret", 0)
+	cgrCfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{"127.0.0.1": addr}
+	if err := ra.sendDynAuthRequest(eventStart, MetaRadDisconnect); err != utils.ErrTimedOut {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrTimedOut, err)
+	}
+	<-reqCodes
+	// reply to another request, discarded
+	addr, reqCodes = radTestDAServer(t, byte(radDisconnectACK), "CGRateS.org", 1)
+	cgrCfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{"127.0.0.1": addr}
+	if err := ra.sendDynAuthRequest(eventStart, MetaRadDisconnect); err != utils.ErrTimedOut {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrTimedOut, err)
+	}
+	<-reqCodes
+	if _, hasIt := ra.getSession(cgrID); !hasIt {
+		t.Error("Session should be kept on invalid replies")
+	}
+	// disconnect acknowledged, session removed
+	addr, reqCodes = radTestDAServer(t, byte(radDisconnectACK), "CGRateS.org", 0)
+	cgrCfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{"127.0.0.1": addr}
+	if err := ra.sendDynAuthRequest(eventStart, MetaRadDisconnect); err != nil {
+		t.Error(err)
+	}
+	if reqCode := <-reqCodes; reqCode != byte(radDisconnectRequest) {
+		t.Errorf("Received request code: %d", reqCode)
+	}
+	if _, hasIt := ra.getSession(cgrID); hasIt {
+		t.Error("Session should be removed on Disconnect-ACK")
+	}
+}
+
+func TestRadiusAgentSessionTTL(t *testing.T) {
+	cgrCfg, _ := config.NewDefaultCGRConfig()
+	cgrCfg.RadiusAgentCfg().SessionTTL = time.Duration(time.Minute)
+	ra := &RadiusAgent{cgrCfg: cgrCfg, sessions: make(map[string]*radSession), sessionsMux: new(sync.RWMutex)}
+	ra.sessions["cgrid1"] = &radSession{clientIP: "127.0.0.1", updated: time.Now().Add(-2 * time.Minute)}
+	ra.sessions["cgrid2"] = &radSession{clientIP: "127.0.0.1", updated: time.Now()}
+	if _, hasIt := ra.getSession("cgrid1"); hasIt {
+		t.Error("Expired session returned")
+	}
+	if _, hasIt := ra.getSession("cgrid2"); !hasIt {
+		t.Error("Active session not returned")
+	}
+	cgrCfg.RadiusAgentCfg().SessionTTL = 0
+	if _, hasIt := ra.getSession("cgrid1"); !hasIt {
+		t.Error("Sessions should not expire with session_ttl disabled")
+	}
+}
@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package v1
 
 import (
+	"fmt"
+
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 )
@@ -45,6 +47,9 @@ func (apierV1 *ApierV1) SetAttributeProfile(extAls *engine.ExternalAttributeProf
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
 	alsPrf := extAls.AsAttributeProfile()
+	if err := alsPrf.Compile(); err != nil { // validate the dynamic substitutes before storing
+		return fmt.Errorf("%s:%s", utils.ErrParserError.Error(), err.Error())
+	}
 	if err := apierV1.DataManager.SetAttributeProfile(alsPrf, true); err != nil {
 		return utils.APIErrorHandler(err)
 	}
@@ -220,8 +220,8 @@ func testAttributeSProcessEvent(t *testing.T) {
 		},
 	}
 	eRply := &engine.AttrSProcessEventReply{
-		MatchedProfile: "ATTR_1",
-		AlteredFields:  []string{"Subject", "Account"},
+		MatchedProfiles: []string{"ATTR_1"},
+		AlteredFields:   []string{"Subject", "Account"},
 		CGREvent: &utils.CGREvent{
 			Tenant:  "cgrates.org",
 			ID:      "testAttributeSProcessEvent",
@@ -234,8 +234,8 @@ func testAttributeSProcessEvent(t *testing.T) {
 		},
 	}
 	eRply2 := &engine.AttrSProcessEventReply{
-		MatchedProfile: "ATTR_1",
-		AlteredFields:  []string{"Account", "Subject"},
+		MatchedProfiles: []string{"ATTR_1"},
+		AlteredFields:   []string{"Account", "Subject"},
 		CGREvent: &utils.CGREvent{
 			Tenant:  "cgrates.org",
 			ID:      "testAttributeSProcessEvent",
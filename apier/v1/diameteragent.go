@@ -0,0 +1,43 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/agents"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewDiameterAgentV1(da *agents.DiameterAgent) *DiameterAgentV1 {
+	return &DiameterAgentV1{da: da}
+}
+
+// Exports RPC from DiameterAgent
+type DiameterAgentV1 struct {
+	da *agents.DiameterAgent
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (dav1 *DiameterAgentV1) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(dav1, serviceMethod, args, reply)
+}
+
+// GetPeers returns the state of the peers connected to DiameterAgent
+func (dav1 *DiameterAgentV1) GetPeers(ignored string, reply *[]*utils.DiameterPeer) error {
+	return dav1.da.V1GetPeers(ignored, reply)
+}
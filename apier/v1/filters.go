@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package v1
 
 import (
+	"fmt"
+
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 )
@@ -28,6 +30,29 @@ func (self *ApierV1) SetFilter(attrs *engine.Filter, reply *string) error {
 	if missing := utils.MissingStructFields(attrs, []string{"Tenant", "ID"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
+	for _, fltrID := range attrs.OrFilterIDs() { // referenced filters need to be already there
+		if fltrID == attrs.ID {
+			continue
+		}
+		if _, err := self.DataManager.GetFilter(attrs.Tenant, fltrID, false, utils.NonTransactional); err != nil {
+			if err.Error() == utils.ErrNotFound.Error() {
+				return fmt.Errorf("broken reference to filter: %s", fltrID)
+			}
+			return utils.NewErrServerError(err)
+		}
+	}
+	if err := engine.CheckOrFilterCycles(attrs.ID, func(fltrID string) ([]string, error) {
+		if fltrID == attrs.ID {
+			return attrs.OrFilterIDs(), nil
+		}
+		fltr, err := self.DataManager.GetFilter(attrs.Tenant, fltrID, false, utils.NonTransactional)
+		if err != nil {
+			return nil, utils.NewErrServerError(err)
+		}
+		return fltr.OrFilterIDs(), nil
+	}); err != nil {
+		return err
+	}
 	if err := self.DataManager.SetFilter(attrs); err != nil {
 		return utils.APIErrorHandler(err)
 	}
@@ -42,6 +42,21 @@ func (rsv1 *ResourceSv1) GetResourcesForEvent(args utils.ArgRSv1ResourceUsage, r
 	return rsv1.rls.V1ResourcesForEvent(args, reply)
 }
 
+// GetResource returns a resource with it's active usages
+func (rsv1 *ResourceSv1) GetResource(args *utils.TenantID, reply *engine.Resource) error {
+	return rsv1.rls.V1GetResource(args, reply)
+}
+
+// GetResourceIDs returns the list of resource IDs of a tenant
+func (rsv1 *ResourceSv1) GetResourceIDs(tenant string, rIDs *[]string) error {
+	return rsv1.rls.V1GetResourceIDs(tenant, rIDs)
+}
+
+// GetResourcesDetails returns the active usages of the resources
+func (rsv1 *ResourceSv1) GetResourcesDetails(args *engine.ArgGetResourcesDetails, reply *[]*engine.ResourceDetails) error {
+	return rsv1.rls.V1GetResourcesDetails(args, reply)
+}
+
 // AuthorizeResources checks if there are limits imposed for event
 func (rsv1 *ResourceSv1) AuthorizeResources(args utils.ArgRSv1ResourceUsage, reply *string) error {
 	return rsv1.rls.V1AuthorizeResources(args, reply)
@@ -52,6 +67,16 @@ func (rsv1 *ResourceSv1) AllocateResources(args utils.ArgRSv1ResourceUsage, repl
 	return rsv1.rls.V1AllocateResource(args, reply)
 }
 
+// ReserveResources holds the units until confirmed or the reservation_ttl expires
+func (rsv1 *ResourceSv1) ReserveResources(args utils.ArgRSv1ResourceUsage, reply *string) error {
+	return rsv1.rls.V1ReserveResource(args, reply)
+}
+
+// ConfirmResources converts a reservation into a normal allocation
+func (rsv1 *ResourceSv1) ConfirmResources(args utils.ArgRSv1ResourceUsage, reply *string) error {
+	return rsv1.rls.V1ConfirmResource(args, reply)
+}
+
 // V1TerminateResourceUsage releases usage for an event
 func (rsv1 *ResourceSv1) ReleaseResources(args utils.ArgRSv1ResourceUsage, reply *string) error {
 	return rsv1.rls.V1ReleaseResource(args, reply)
@@ -45,6 +45,8 @@ func (self *SMGenericBiRpcV1) Handlers() map[string]interface{} {
 		"SMGenericV1.GetṔassiveSessions":      self.GetṔassiveSessions,
 		"SMGenericV1.GetPassiveSessionsCount": self.GetPassiveSessionsCount,
 		"SMGenericV1.ReplicateActiveSessions": self.ReplicateActiveSessions,
+		"SMGenericV1.ForceDisconnect":         self.ForceDisconnect,
+		"SMGenericV1.ReAuthorize":             self.ReAuthorize,
 	}
 }
 
@@ -101,3 +103,13 @@ func (self *SMGenericBiRpcV1) ReplicateActiveSessions(clnt *rpc2.Client, args se
 func (self *SMGenericBiRpcV1) ReplicatePassiveSessions(clnt *rpc2.Client, args sessionmanager.ArgsReplicateSessions, reply *string) error {
 	return self.sm.BiRPCV1ReplicateActiveSessions(clnt, args, reply)
 }
+
+// ForceDisconnect sends a disconnect request to the agents owning the matched sessions
+func (self *SMGenericBiRpcV1) ForceDisconnect(clnt *rpc2.Client, args sessionmanager.ArgsForceDisconnect, reply *string) error {
+	return self.sm.BiRPCV1ForceDisconnect(clnt, args, reply)
+}
+
+// ReAuthorize sends a re-authorization request to the agents owning the matched sessions
+func (self *SMGenericBiRpcV1) ReAuthorize(clnt *rpc2.Client, args sessionmanager.ArgsReAuthorize, reply *string) error {
+	return self.sm.BiRPCV1ReAuthorize(clnt, args, reply)
+}
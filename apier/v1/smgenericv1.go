@@ -94,6 +94,16 @@ func (self *SMGenericV1) ReplicatePassiveSessions(args sessionmanager.ArgsReplic
 	return self.SMG.BiRPCV1ReplicatePassiveSessions(nil, args, reply)
 }
 
+// ForceDisconnect sends a disconnect request to the agents owning the matched sessions
+func (self *SMGenericV1) ForceDisconnect(args sessionmanager.ArgsForceDisconnect, reply *string) error {
+	return self.SMG.BiRPCV1ForceDisconnect(nil, args, reply)
+}
+
+// ReAuthorize sends a re-authorization request to the agents owning the matched sessions
+func (self *SMGenericV1) ReAuthorize(args sessionmanager.ArgsReAuthorize, reply *string) error {
+	return self.SMG.BiRPCV1ReAuthorize(nil, args, reply)
+}
+
 // rpcclient.RpcClientConnection interface
 func (self *SMGenericV1) Call(serviceMethod string, args interface{}, reply interface{}) error {
 	methodSplit := strings.Split(serviceMethod, ".")
@@ -53,6 +53,11 @@ func (tSv1 *ThresholdSv1) GetThreshold(tntID *utils.TenantID, t *engine.Threshol
 	return tSv1.tS.V1GetThreshold(tntID, t)
 }
 
+// ResetThreshold resets the hits counter of a Threshold
+func (tSv1 *ThresholdSv1) ResetThreshold(tntID *utils.TenantID, reply *string) error {
+	return tSv1.tS.V1ResetThreshold(tntID, reply)
+}
+
 // ProcessEvent will process an Event
 func (tSv1 *ThresholdSv1) ProcessEvent(args *engine.ArgsProcessEvent, hits *int) error {
 	return tSv1.tS.V1ProcessEvent(args, hits)
@@ -131,7 +131,8 @@ func startCdrc(internalCdrSChan, internalRaterChan chan rpcclient.RpcClientConne
 }
 
 func startSessionS(internalSMGChan, internalRaterChan, internalResourceSChan, internalSupplierSChan,
-	internalAttrSChan, internalCDRSChan chan rpcclient.RpcClientConnection, server *utils.Server, exitChan chan bool) {
+	internalAttrSChan, internalCDRSChan chan rpcclient.RpcClientConnection, dm *engine.DataManager,
+	server *utils.Server, exitChan chan bool) {
 	utils.Logger.Info("Starting CGRateS Session service.")
 	var err error
 	var ralsConns, resSConns, suplSConns, attrSConns, cdrsConn *rpcclient.RpcClientPool
@@ -186,11 +187,18 @@ func startSessionS(internalSMGChan, internalRaterChan, internalResourceSChan, in
 		exitChan <- true
 		return
 	}
-	sm := sessionmanager.NewSMGeneric(cfg, ralsConns, resSConns, suplSConns,
+	sm := sessionmanager.NewSMGeneric(cfg, dm, ralsConns, resSConns, suplSConns,
 		attrSConns, cdrsConn, smgReplConns, cfg.DefaultTimezone)
 	if err = sm.Connect(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> error: %s!", utils.SessionS, err))
 	}
+	if cfg.SessionSCfg().StoreInterval != 0 { // store active sessions on shutdown
+		go func() {
+			e := <-exitChan
+			exitChan <- e // put back for the others listening for shutdown request
+			sm.Shutdown()
+		}()
+	}
 	// Pass internal connection via BiRPCClient
 	internalSMGChan <- sm
 	// Register RPC handler
@@ -232,17 +240,26 @@ func startAsteriskAgent(internalSMGChan chan rpcclient.RpcClientConnection, exit
 	exitChan <- true
 }
 
-func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcClientConnection, exitChan chan bool) {
+func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcClientConnection,
+	server *utils.Server, exitChan chan bool) {
 	var err error
 	utils.Logger.Info("Starting CGRateS DiameterAgent service")
-	var smgConn, pubsubConn *rpcclient.RpcClientPool
+	var smgConn rpcclient.RpcClientConnection
+	var birpcClnt *utils.BiRPCInternalClient
+	var pubsubConn *rpcclient.RpcClientPool
 	if len(cfg.DiameterAgentCfg().SessionSConns) != 0 {
-		smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts, cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
-			cfg.DiameterAgentCfg().SessionSConns, internalSMGChan, cfg.InternalTtl)
-		if err != nil {
+		if cfg.DiameterAgentCfg().SessionSConns[0].Address == utils.MetaInternal { // bidirectional so SMG can reach us with RAR/ASR
+			smgRpcConn := <-internalSMGChan
+			internalSMGChan <- smgRpcConn
+			birpcClnt = utils.NewBiRPCInternalClient(smgRpcConn.(*sessionmanager.SMGeneric))
+			smgConn = birpcClnt
+		} else if smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts, cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
+			cfg.DiameterAgentCfg().SessionSConns, internalSMGChan, cfg.InternalTtl); err != nil {
 			utils.Logger.Crit(fmt.Sprintf("<DiameterAgent> Could not connect to SMG: %s", err.Error()))
 			exitChan <- true
 			return
+		} else {
+			utils.Logger.Warning("<DiameterAgent> SMG connection is not *internal, RAR/ASR will not be sent")
 		}
 	}
 	if len(cfg.DiameterAgentCfg().PubSubConns) != 0 {
@@ -260,6 +277,10 @@ func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcC
 		exitChan <- true
 		return
 	}
+	if birpcClnt != nil {
+		birpcClnt.SetClientConn(da)
+	}
+	server.RpcRegister(v1.NewDiameterAgentV1(da))
 	if err = da.ListenAndServe(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> error: %s!", err))
 	}
@@ -269,15 +290,22 @@ func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcC
 func startRadiusAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitChan chan bool) {
 	var err error
 	utils.Logger.Info("Starting CGRateS RadiusAgent service")
-	var smgConn *rpcclient.RpcClientPool
+	var smgConn rpcclient.RpcClientConnection
+	var birpcClnt *utils.BiRPCInternalClient
 	if len(cfg.RadiusAgentCfg().SessionSConns) != 0 {
-		smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts,
+		if cfg.RadiusAgentCfg().SessionSConns[0].Address == utils.MetaInternal { // bidirectional so SMG can reach us with Disconnect/CoA
+			smgRpcConn := <-internalSMGChan
+			internalSMGChan <- smgRpcConn
+			birpcClnt = utils.NewBiRPCInternalClient(smgRpcConn.(*sessionmanager.SMGeneric))
+			smgConn = birpcClnt
+		} else if smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts,
 			cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
-			cfg.RadiusAgentCfg().SessionSConns, internalSMGChan, cfg.InternalTtl)
-		if err != nil {
+			cfg.RadiusAgentCfg().SessionSConns, internalSMGChan, cfg.InternalTtl); err != nil {
 			utils.Logger.Crit(fmt.Sprintf("<RadiusAgent> Could not connect to SMG: %s", err.Error()))
 			exitChan <- true
 			return
+		} else {
+			utils.Logger.Warning("<RadiusAgent> SMG connection is not *internal, Disconnect/CoA will not be sent")
 		}
 	}
 	ra, err := agents.NewRadiusAgent(cfg, smgConn)
@@ -286,6 +314,9 @@ func startRadiusAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitCh
 		exitChan <- true
 		return
 	}
+	if birpcClnt != nil {
+		birpcClnt.SetClientConn(ra)
+	}
 	if err = ra.ListenAndServe(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<RadiusAgent> error: <%s>", err.Error()))
 	}
@@ -530,7 +561,8 @@ func startAttributeService(internalAttributeSChan chan rpcclient.RpcClientConnec
 	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
 	filterS := <-filterSChan
 	filterSChan <- filterS
-	aS, err := engine.NewAttributeService(dm, filterS, cfg.AttributeSCfg().IndexedFields)
+	aS, err := engine.NewAttributeService(dm, filterS,
+		cfg.AttributeSCfg().IndexedFields, cfg.AttributeSCfg().ProcessRuns)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<%s> Could not init, error: %s", utils.AttributeS, err.Error()))
 		exitChan <- true
@@ -564,7 +596,8 @@ func startResourceService(internalRsChan, internalThresholdSChan chan rpcclient.
 			return
 		}
 	}
-	rS, err := engine.NewResourceService(dm, cfg.ResourceSCfg().StoreInterval, thdSConn, filterS, cfg.ResourceSCfg().IndexedFields)
+	rS, err := engine.NewResourceService(dm, cfg.ResourceSCfg().StoreInterval, thdSConn, filterS,
+		cfg.ResourceSCfg().IndexedFields, cfg.ResourceSCfg().ReservationTTL)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<ResourceS> Could not init, error: %s", err.Error()))
 		exitChan <- true
@@ -627,7 +660,7 @@ func startThresholdService(internalThresholdSChan chan rpcclient.RpcClientConnec
 	filterS := <-filterSChan
 	filterSChan <- filterS
 	tS, err := engine.NewThresholdService(dm, cfg.ThresholdSCfg().IndexedFields,
-		cfg.ThresholdSCfg().StoreInterval, filterS)
+		cfg.ThresholdSCfg().StoreInterval, filterS, cfg.ThresholdSCfg().Exports)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<ThresholdS> Could not init, error: %s", err.Error()))
 		exitChan <- true
@@ -702,10 +735,10 @@ func startSupplierService(internalSupplierSChan, internalRsChan, internalStatSCh
 
 // startFilterService fires up the FilterS
 func startFilterService(filterSChan chan *engine.FilterS,
-	internalStatSChan chan rpcclient.RpcClientConnection, cfg *config.CGRConfig,
+	internalStatSChan, internalRsChan chan rpcclient.RpcClientConnection, cfg *config.CGRConfig,
 	dm *engine.DataManager, exitChan chan bool) {
 
-	filterSChan <- engine.NewFilterS(cfg, internalStatSChan, dm)
+	filterSChan <- engine.NewFilterS(cfg, internalStatSChan, internalRsChan, dm)
 
 }
 
@@ -826,7 +859,8 @@ func main() {
 	var dm *engine.DataManager
 
 	if cfg.RALsEnabled || cfg.CDRStatsEnabled || cfg.PubSubServerEnabled ||
-		cfg.AliasesServerEnabled || cfg.UserServerEnabled || cfg.SchedulerEnabled {
+		cfg.AliasesServerEnabled || cfg.UserServerEnabled || cfg.SchedulerEnabled ||
+		(cfg.SessionSCfg().Enabled && cfg.SessionSCfg().StoreInterval != 0) {
 		dm, err = engine.ConfigureDataStorage(cfg.DataDbType, cfg.DataDbHost, cfg.DataDbPort,
 			cfg.DataDbName, cfg.DataDbUser, cfg.DataDbPass, cfg.DBDataEncoding, cfg.CacheCfg(), cfg.LoadHistorySize)
 		if err != nil { // Cannot configure getter database, show stopper
@@ -922,7 +956,7 @@ func main() {
 	// Start SM-Generic
 	if cfg.SessionSCfg().Enabled {
 		go startSessionS(internalSMGChan, internalRaterChan, internalRsChan,
-			internalSupplierSChan, internalAttributeSChan, internalCdrSChan, server, exitChan)
+			internalSupplierSChan, internalAttributeSChan, internalCdrSChan, dm, server, exitChan)
 	}
 	// Start SM-FreeSWITCH
 	if cfg.FsAgentCfg().Enabled {
@@ -946,7 +980,7 @@ func main() {
 	}
 
 	if cfg.DiameterAgentCfg().Enabled {
-		go startDiameterAgent(internalSMGChan, internalPubSubSChan, exitChan)
+		go startDiameterAgent(internalSMGChan, internalPubSubSChan, server, exitChan)
 	}
 
 	if cfg.RadiusAgentCfg().Enabled {
@@ -968,7 +1002,7 @@ func main() {
 		go startUsersServer(internalUserSChan, dm, server, exitChan)
 	}
 	// Start FilterS
-	go startFilterService(filterSChan, internalStatSChan, cfg, dm, exitChan)
+	go startFilterService(filterSChan, internalStatSChan, internalRsChan, cfg, dm, exitChan)
 
 	if cfg.AttributeSCfg().Enabled {
 		go startAttributeService(internalAttributeSChan, cfg, dm, server, exitChan, filterSChan)
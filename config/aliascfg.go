@@ -22,6 +22,7 @@ package config
 type AttributeSCfg struct {
 	Enabled       bool
 	IndexedFields []string
+	ProcessRuns   int
 }
 
 func (alS *AttributeSCfg) loadFromJsonCfg(jsnCfg *AttributeSJsonCfg) (err error) {
@@ -37,5 +38,8 @@ func (alS *AttributeSCfg) loadFromJsonCfg(jsnCfg *AttributeSJsonCfg) (err error)
 			alS.IndexedFields[i] = fID
 		}
 	}
+	if jsnCfg.Process_runs != nil {
+		alS.ProcessRuns = *jsnCfg.Process_runs
+	}
 	return
 }
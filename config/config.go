@@ -502,6 +502,9 @@ func (self *CGRConfig) checkConfigSanity() error {
 				return errors.New("<SMGeneric> CDRS not enabled but referenced by SMGeneric component")
 			}
 		}
+		if self.sessionSCfg.StoreInterval != 0 && self.sessionSCfg.RestoreTimeout <= 0 {
+			return errors.New("<SMGeneric> restore_timeout is mandatory when storing sessions")
+		}
 	}
 	// SMFreeSWITCH checks
 	if self.fsAgentCfg.Enabled {
@@ -576,6 +579,14 @@ func (self *CGRConfig) checkConfigSanity() error {
 	}
 	// DAgent checks
 	if self.diameterAgentCfg.Enabled {
+		if self.diameterAgentCfg.ListenNet != "" && self.diameterAgentCfg.ListenNet != "tcp" { // only tcp listeners are implemented
+			return fmt.Errorf("<DiameterAgent> unsupported listen_net: %s", self.diameterAgentCfg.ListenNet)
+		}
+		for _, lstn := range self.diameterAgentCfg.Listeners {
+			if lstn.ListenNet != "" && lstn.ListenNet != "tcp" { // empty inherits the main listen_net
+				return fmt.Errorf("<DiameterAgent> listener: %s, unsupported listen_net: %s", lstn.Listen, lstn.ListenNet)
+			}
+		}
 		for _, daSMGConn := range self.diameterAgentCfg.SessionSConns {
 			if daSMGConn.Address == utils.MetaInternal && !self.sessionSCfg.Enabled {
 				return errors.New("SMGeneric not enabled but referenced by DiameterAgent component")
@@ -602,6 +613,17 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	// ThresholdS checks
+	if self.thresholdSCfg != nil && self.thresholdSCfg.Enabled {
+		for _, expCfg := range self.thresholdSCfg.Exports {
+			if !utils.IsSliceMember([]string{utils.MetaHTTPjson, utils.MetaAMQPjsonMap}, expCfg.ExportFormat) {
+				return fmt.Errorf("<ThresholdS> unsupported export_format: %s", expCfg.ExportFormat)
+			}
+			if expCfg.ExportPath == "" {
+				return errors.New("<ThresholdS> empty export_path")
+			}
+		}
+	}
 	// Stat checks
 	if self.statsCfg != nil && self.statsCfg.Enabled {
 		for _, connCfg := range self.statsCfg.ThresholdSConns {
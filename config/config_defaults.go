@@ -137,6 +137,7 @@ const CGRATES_CFG_JSON = `
 
 "filters": {								// Filters configuration (*new)
 	"stats_conns": [],						// address where to reach the stat service, empty to disable stats functionality: <""|*internal|x.y.z.y:1234>
+	"resources_conns": [],					// address where to reach the resource service, empty to disable resources functionality: <""|*internal|x.y.z.y:1234>
 },
 
 
@@ -299,6 +300,8 @@ const CGRATES_CFG_JSON = `
 	//"session_ttl_usage": "",				// tweak Usage for sessions timing-out, not defined by default
 	"session_indexes": [],					// index sessions based on these fields for GetActiveSessions API
 	"client_protocol": 1.0,					// version of protocol to use when acting as JSON-PRC client <"0","1.0">
+	"store_interval": "",					// store active sessions into dataDB so they survive restarts, -1 on each change: <""|-1|$dur>
+	"restore_timeout": "1m",				// terminate the restored sessions not claimed back by their agents within this interval
 },
 
 
@@ -374,19 +377,24 @@ const CGRATES_CFG_JSON = `
 "diameter_agent": {
 	"enabled": false,											// enables the diameter agent: <true|false>
 	"listen": "127.0.0.1:3868",									// address where to listen for diameter requests <x.y.z.y:1234>
+	"listen_net": "tcp",										// transport type for diameter <tcp>
 	"dictionaries_dir": "/usr/share/cgrates/diameter/dict/",	// path towards directory holding additional dictionaries to load
 	"sessions_conns": [
-		{"address": "*internal"}								// connection towards SessionService
+		{"address": "*internal"}								// connection towards SessionService, only *internal allows SessionS to send RAR/ASR
 	],
 	"pubsubs_conns": [],										// address where to reach the pubusb service, empty to disable pubsub functionality: <""|*internal|x.y.z.y:1234>
 	"create_cdr": true,											// create CDR out of CCR terminate and send it to SessionS
 	"cdr_requires_session": true,								// only create CDR if there is an active session at terminate
 	"debit_interval": "5m",										// interval for CCR updates
+	"reply_timeout": "2s",										// timeout waiting for answers to the requests sent towards clients (RAR/ASR)
 	"timezone": "",												// timezone for timestamps where not specified, empty for general defaults <""|UTC|Local|$IANA_TZ_DB>
 	"origin_host": "CGR-DA",									// diameter Origin-Host AVP used in replies
 	"origin_realm": "cgrates.org",								// diameter Origin-Realm AVP used in replies
 	"vendor_id": 0,												// diameter Vendor-Id AVP used in replies
 	"product_name": "CGRateS",									// diameter Product-Name AVP used in replies
+	"applications": [],											// Auth-Application-Ids advertised in CEA, empty to accept the ones offered by peer
+	"watchdog_interval": "30s",								// send Device-Watchdog-Request towards idle peers, 0 to disable <$dur>
+	"listeners": [],											// extra listeners with own identity, unset fields inherited from above: [{"listen", "listen_net", "origin_host", "origin_realm", "vendor_id", "product_name", "applications"}]
 	"request_processors": [],
 },
 
@@ -402,12 +410,26 @@ const CGRATES_CFG_JSON = `
 	"client_dictionaries": {									// per client path towards directory holding additional dictionaries to load (extra to RFC)
 		"*default": "/usr/share/cgrates/radius/dict/",			// key represents the client IP or catch-all <*default|$client_ip>
 	},
+	"client_da_addresses": {},									// per client address of the Dynamic Authorization server, defaults to client IP on port 3799 <$client_ip: $da_address>
 	"sessions_conns": [
-		{"address": "*internal"}								// connection towards SessionService
+		{"address": "*internal"}								// connection towards SessionService, only *internal allows SessionS to send Disconnect/CoA
 	],
 	"create_cdr": true,											// create CDR out of Accounting-Stop and send it to SessionS
 	"cdr_requires_session": false,								// only create CDR if there is an active session at terminate
 	"timezone": "",												// timezone for timestamps where not specified, empty for general defaults <""|UTC|Local|$IANA_TZ_DB>
+	"reply_timeout": "2s",										// timeout waiting for ACK/NAK of the Disconnect and CoA requests sent towards clients
+	"session_ttl": "24h",										// forget sessions not refreshed by accounting within this interval (ie: lost Accounting-Stop), 0 to disable <$dur>
+	"disconnect_fields": [										// attributes of Disconnect-Request, extracted out of the accounting request of the session
+		{"tag": "UserName", "field_id": "User-Name", "field_filter": "User-Name", "type": "*composed", "value": "User-Name"},
+		{"tag": "AcctSessionId", "field_id": "Acct-Session-Id", "field_filter": "Acct-Session-Id", "type": "*composed", "value": "Acct-Session-Id"},
+		{"tag": "NASIPAddress", "field_id": "NAS-IP-Address", "field_filter": "NAS-IP-Address", "type": "*composed", "value": "NAS-IP-Address"},
+	],
+	"coa_fields": [												// attributes of CoA-Request, extracted out of the accounting request of the session
+		{"tag": "UserName", "field_id": "User-Name", "field_filter": "User-Name", "type": "*composed", "value": "User-Name"},
+		{"tag": "AcctSessionId", "field_id": "Acct-Session-Id", "field_filter": "Acct-Session-Id", "type": "*composed", "value": "Acct-Session-Id"},
+		{"tag": "NASIPAddress", "field_id": "NAS-IP-Address", "field_filter": "NAS-IP-Address", "type": "*composed", "value": "NAS-IP-Address"},
+		{"tag": "SessionTimeout", "field_id": "Session-Timeout", "field_filter": "*cgrMaxUsage", "type": "*composed", "value": "~*cgrMaxUsage:s/(\\d*)\\d{9}$/$1/"},	// new max usage of the session, in seconds
+	],
 	"request_processors": [],
 },
 
@@ -431,6 +453,7 @@ const CGRATES_CFG_JSON = `
 "attributes": {							// Attribute service
 	"enabled": false,				// starts attribute service: <true|false>.
 	"indexed_fields": [],			// query indexes based on these fields for faster processing
+	"process_runs": 1,				// number of passes matching the altered event against the profiles not yet applied
 },
 
 
@@ -439,6 +462,7 @@ const CGRATES_CFG_JSON = `
 	"store_interval": "",			// dump cache regularly to dataDB, 0 - dump at start/shutdown: <""|$dur>
 	"thresholds_conns": [],			// address where to reach the thresholds service, empty to disable thresholds functionality: <""|*internal|x.y.z.y:1234>
 	"indexed_fields": [],			// query indexes based on these fields for faster processing
+	"reservation_ttl": "5s",		// hold the units reserved via ResourceSv1.ReserveResources unless confirmed: <$dur>
 },
 
 
@@ -454,6 +478,7 @@ const CGRATES_CFG_JSON = `
 	"enabled": false,				// starts ThresholdS service: <true|false>.
 	"store_interval": "",			// dump cache regularly to dataDB, 0 - dump at start/shutdown: <""|$dur>
 	"indexed_fields": [],			// query indexes based on these fields for faster processing
+	"exports": [],					// post threshold hits to these destinations: [{"export_format": "<*http_json|*amqp_json_map>", "export_path": "$address"}]
 },
 
 
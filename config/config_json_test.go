@@ -505,6 +505,8 @@ func TestSmgJsonCfg(t *testing.T) {
 		Session_ttl:               utils.StringPointer("0s"),
 		Session_indexes:           utils.StringSlicePointer([]string{}),
 		Client_protocol:           utils.Float64Pointer(1.0),
+		Store_interval:            utils.StringPointer(""),
+		Restore_timeout:           utils.StringPointer("1m"),
 	}
 	if cfg, err := dfCgrJsonCfg.SessionSJsonCfg(); err != nil {
 		t.Error(err)
@@ -627,6 +629,7 @@ func TestDiameterAgentJsonCfg(t *testing.T) {
 	eCfg := &DiameterAgentJsonCfg{
 		Enabled:          utils.BoolPointer(false),
 		Listen:           utils.StringPointer("127.0.0.1:3868"),
+		Listen_net:       utils.StringPointer("tcp"),
 		Dictionaries_dir: utils.StringPointer("/usr/share/cgrates/diameter/dict/"),
 		Sessions_conns: &[]*HaPoolJsonCfg{
 			&HaPoolJsonCfg{
@@ -636,11 +639,15 @@ func TestDiameterAgentJsonCfg(t *testing.T) {
 		Create_cdr:           utils.BoolPointer(true),
 		Cdr_requires_session: utils.BoolPointer(true),
 		Debit_interval:       utils.StringPointer("5m"),
+		Reply_timeout:        utils.StringPointer("2s"),
 		Timezone:             utils.StringPointer(""),
 		Origin_host:          utils.StringPointer("CGR-DA"),
 		Origin_realm:         utils.StringPointer("cgrates.org"),
 		Vendor_id:            utils.IntPointer(0),
 		Product_name:         utils.StringPointer("CGRateS"),
+		Applications:         &[]int{},
+		Watchdog_interval:    utils.StringPointer("30s"),
+		Listeners:            &[]*DiameterListenerJsonCfg{},
 		Request_processors:   &[]*DARequestProcessorJsnCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.DiameterAgentJsonCfg(); err != nil {
@@ -663,6 +670,7 @@ func TestRadiusAgentJsonCfg(t *testing.T) {
 		Client_dictionaries: utils.MapStringStringPointer(map[string]string{
 			utils.META_DEFAULT: "/usr/share/cgrates/radius/dict/",
 		}),
+		Client_da_addresses: utils.MapStringStringPointer(map[string]string{}),
 		Sessions_conns: &[]*HaPoolJsonCfg{
 			&HaPoolJsonCfg{
 				Address: utils.StringPointer(utils.MetaInternal),
@@ -670,7 +678,27 @@ func TestRadiusAgentJsonCfg(t *testing.T) {
 		Create_cdr:           utils.BoolPointer(true),
 		Cdr_requires_session: utils.BoolPointer(false),
 		Timezone:             utils.StringPointer(""),
-		Request_processors:   &[]*RAReqProcessorJsnCfg{},
+		Reply_timeout:        utils.StringPointer("2s"),
+		Session_ttl:          utils.StringPointer("24h"),
+		Disconnect_fields: &[]*CdrFieldJsonCfg{
+			&CdrFieldJsonCfg{Tag: utils.StringPointer("UserName"), Field_id: utils.StringPointer("User-Name"),
+				Field_filter: utils.StringPointer("User-Name"), Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("User-Name")},
+			&CdrFieldJsonCfg{Tag: utils.StringPointer("AcctSessionId"), Field_id: utils.StringPointer("Acct-Session-Id"),
+				Field_filter: utils.StringPointer("Acct-Session-Id"), Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("Acct-Session-Id")},
+			&CdrFieldJsonCfg{Tag: utils.StringPointer("NASIPAddress"), Field_id: utils.StringPointer("NAS-IP-Address"),
+				Field_filter: utils.StringPointer("NAS-IP-Address"), Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("NAS-IP-Address")},
+		},
+		Coa_fields: &[]*CdrFieldJsonCfg{
+			&CdrFieldJsonCfg{Tag: utils.StringPointer("UserName"), Field_id: utils.StringPointer("User-Name"),
+				Field_filter: utils.StringPointer("User-Name"), Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("User-Name")},
+			&CdrFieldJsonCfg{Tag: utils.StringPointer("AcctSessionId"), Field_id: utils.StringPointer("Acct-Session-Id"),
+				Field_filter: utils.StringPointer("Acct-Session-Id"), Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("Acct-Session-Id")},
+			&CdrFieldJsonCfg{Tag: utils.StringPointer("NASIPAddress"), Field_id: utils.StringPointer("NAS-IP-Address"),
+				Field_filter: utils.StringPointer("NAS-IP-Address"), Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("NAS-IP-Address")},
+			&CdrFieldJsonCfg{Tag: utils.StringPointer("SessionTimeout"), Field_id: utils.StringPointer("Session-Timeout"),
+				Field_filter: utils.StringPointer("*cgrMaxUsage"), Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("~*cgrMaxUsage:s/(\\d*)\\d{9}$/$1/")},
+		},
+		Request_processors: &[]*RAReqProcessorJsnCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.RadiusAgentJsonCfg(); err != nil {
 		t.Error(err)
@@ -718,6 +746,7 @@ func TestDfAttributeServJsonCfg(t *testing.T) {
 	eCfg := &AttributeSJsonCfg{
 		Enabled:        utils.BoolPointer(false),
 		Indexed_fields: utils.StringSlicePointer([]string{}),
+		Process_runs:   utils.IntPointer(1),
 	}
 	if cfg, err := dfCgrJsonCfg.AttributeServJsonCfg(); err != nil {
 		t.Error(err)
@@ -728,7 +757,8 @@ func TestDfAttributeServJsonCfg(t *testing.T) {
 
 func TestDfFilterSJsonCfg(t *testing.T) {
 	eCfg := &FilterSJsonCfg{
-		Stats_conns: &[]*HaPoolJsonCfg{},
+		Stats_conns:     &[]*HaPoolJsonCfg{},
+		Resources_conns: &[]*HaPoolJsonCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.FilterSJsonCfg(); err != nil {
 		t.Error(err)
@@ -743,6 +773,7 @@ func TestDfResourceLimiterSJsonCfg(t *testing.T) {
 		Thresholds_conns: &[]*HaPoolJsonCfg{},
 		Store_interval:   utils.StringPointer(""),
 		Indexed_fields:   utils.StringSlicePointer([]string{}),
+		Reservation_ttl:  utils.StringPointer("5s"),
 	}
 	if cfg, err := dfCgrJsonCfg.ResourceSJsonCfg(); err != nil {
 		t.Error(err)
@@ -770,6 +801,7 @@ func TestDfThresholdSJsonCfg(t *testing.T) {
 		Enabled:        utils.BoolPointer(false),
 		Store_interval: utils.StringPointer(""),
 		Indexed_fields: utils.StringSlicePointer([]string{}),
+		Exports:        &[]*ThresholdSExportJsonCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.ThresholdSJsonCfg(); err != nil {
 		t.Error(err)
@@ -463,6 +463,8 @@ func TestCgrCfgJSONDefaultsSMGenericCfg(t *testing.T) {
 		SessionTTL:              0 * time.Second,
 		SessionIndexes:          utils.StringMap{},
 		ClientProtocol:          1.0,
+		StoreInterval:           0,
+		RestoreTimeout:          time.Minute,
 	}
 	if !reflect.DeepEqual(eSessionSCfg, cgrCfg.sessionSCfg) {
 		t.Errorf("expecting: %s, received: %s",
@@ -649,7 +651,8 @@ func TestCgrCfgJSONDefaultsUserS(t *testing.T) {
 
 func TestCgrCfgJSONDefaultFiltersCfg(t *testing.T) {
 	eFiltersCfg := &FilterSCfg{
-		StatSConns: []*HaPoolConfig{},
+		StatSConns:     []*HaPoolConfig{},
+		ResourceSConns: []*HaPoolConfig{},
 	}
 	if !reflect.DeepEqual(cgrCfg.filterSCfg, eFiltersCfg) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.filterSCfg, eFiltersCfg)
@@ -660,6 +663,7 @@ func TestCgrCfgJSONDefaultSAttributeSCfg(t *testing.T) {
 	eAliasSCfg := &AttributeSCfg{
 		Enabled:       false,
 		IndexedFields: []string{},
+		ProcessRuns:   1,
 	}
 	if !reflect.DeepEqual(eAliasSCfg, cgrCfg.attributeSCfg) {
 		t.Errorf("received: %+v, expecting: %+v", eAliasSCfg, cgrCfg.attributeSCfg)
@@ -672,6 +676,7 @@ func TestCgrCfgJSONDefaultsResLimCfg(t *testing.T) {
 		ThresholdSConns: []*HaPoolConfig{},
 		StoreInterval:   0,
 		IndexedFields:   []string{},
+		ReservationTTL:  5 * time.Second,
 	}
 	if !reflect.DeepEqual(cgrCfg.resourceSCfg, eResLiCfg) {
 		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eResLiCfg), utils.ToJSON(cgrCfg.resourceSCfg))
@@ -696,6 +701,7 @@ func TestCgrCfgJSONDefaultThresholdSCfg(t *testing.T) {
 		Enabled:       false,
 		StoreInterval: 0,
 		IndexedFields: []string{},
+		Exports:       []*ThresholdSExportCfg{},
 	}
 	if !reflect.DeepEqual(eThresholdSCfg, cgrCfg.thresholdSCfg) {
 		t.Errorf("received: %+v, expecting: %+v", eThresholdSCfg, cgrCfg.thresholdSCfg)
@@ -721,17 +727,22 @@ func TestCgrCfgJSONDefaultsDiameterAgentCfg(t *testing.T) {
 	testDA := &DiameterAgentCfg{
 		Enabled:         false,
 		Listen:          "127.0.0.1:3868",
+		ListenNet:       "tcp",
 		DictionariesDir: "/usr/share/cgrates/diameter/dict/",
 		SessionSConns: []*HaPoolConfig{
 			&HaPoolConfig{Address: "*internal"}},
 		PubSubConns:       []*HaPoolConfig{},
 		CreateCDR:         true,
 		DebitInterval:     5 * time.Minute,
+		ReplyTimeout:      2 * time.Second,
 		Timezone:          "",
 		OriginHost:        "CGR-DA",
 		OriginRealm:       "cgrates.org",
 		VendorId:          0,
 		ProductName:       "CGRateS",
+		Applications:      []int{},
+		WatchdogInterval:  30 * time.Second,
+		Listeners:         []*DiameterListenerCfg{},
 		RequestProcessors: nil,
 	}
 
@@ -741,6 +752,9 @@ func TestCgrCfgJSONDefaultsDiameterAgentCfg(t *testing.T) {
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.Listen, testDA.Listen) {
 		t.Errorf("expecting: %+v, received: %+v", cgrCfg.diameterAgentCfg.Listen, testDA.Listen)
 	}
+	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.ListenNet, testDA.ListenNet) {
+		t.Errorf("expecting: %+v, received: %+v", cgrCfg.diameterAgentCfg.ListenNet, testDA.ListenNet)
+	}
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.DictionariesDir, testDA.DictionariesDir) {
 		t.Errorf("expecting: %+v, received: %+v", cgrCfg.diameterAgentCfg.DictionariesDir, testDA.DictionariesDir)
 	}
@@ -756,6 +770,9 @@ func TestCgrCfgJSONDefaultsDiameterAgentCfg(t *testing.T) {
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.DebitInterval, testDA.DebitInterval) {
 		t.Errorf("expecting: %+v, received: %+v", cgrCfg.diameterAgentCfg.DebitInterval, testDA.DebitInterval)
 	}
+	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.ReplyTimeout, testDA.ReplyTimeout) {
+		t.Errorf("expecting: %+v, received: %+v", cgrCfg.diameterAgentCfg.ReplyTimeout, testDA.ReplyTimeout)
+	}
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.Timezone, testDA.Timezone) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.diameterAgentCfg.Timezone, testDA.Timezone)
 	}
@@ -771,11 +788,94 @@ func TestCgrCfgJSONDefaultsDiameterAgentCfg(t *testing.T) {
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.ProductName, testDA.ProductName) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.diameterAgentCfg.ProductName, testDA.ProductName)
 	}
+	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.Applications, testDA.Applications) {
+		t.Errorf("expecting: %+v, received: %+v", testDA.Applications, cgrCfg.diameterAgentCfg.Applications)
+	}
+	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.WatchdogInterval, testDA.WatchdogInterval) {
+		t.Errorf("expecting: %+v, received: %+v", testDA.WatchdogInterval, cgrCfg.diameterAgentCfg.WatchdogInterval)
+	}
+	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.Listeners, testDA.Listeners) {
+		t.Errorf("expecting: %+v, received: %+v", testDA.Listeners, cgrCfg.diameterAgentCfg.Listeners)
+	}
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.RequestProcessors, testDA.RequestProcessors) {
 		t.Errorf("expecting: %+v, received: %+v", testDA.RequestProcessors, cgrCfg.diameterAgentCfg.RequestProcessors)
 	}
 }
 
+func TestCgrCfgDiameterAgentListeners(t *testing.T) {
+	JSN_CFG := `
+{
+"diameter_agent": {
+	"listen_net": "tcp",
+	"applications": [4],
+	"listeners": [
+		{"listen": "127.0.0.1:3869", "origin_host": "CGR-DA2", "applications": [4, 16777238]},
+	],
+},
+}`
+	eListeners := []*DiameterListenerCfg{
+		&DiameterListenerCfg{Listen: "127.0.0.1:3869", OriginHost: "CGR-DA2",
+			Applications: []int{4, 16777238}},
+	}
+	if cgrCfg, err := NewCGRConfigFromJsonStringWithDefaults(JSN_CFG); err != nil {
+		t.Error(err)
+	} else if cgrCfg.diameterAgentCfg.ListenNet != "tcp" {
+		t.Errorf("Received: %s", cgrCfg.diameterAgentCfg.ListenNet)
+	} else if !reflect.DeepEqual([]int{4}, cgrCfg.diameterAgentCfg.Applications) {
+		t.Errorf("Received: %+v", cgrCfg.diameterAgentCfg.Applications)
+	} else if !reflect.DeepEqual(eListeners, cgrCfg.diameterAgentCfg.Listeners) {
+		t.Errorf("Expected: %+v, received: %+v", eListeners, cgrCfg.diameterAgentCfg.Listeners)
+	}
+}
+
+func TestCgrCfgDiameterAgentListenNet(t *testing.T) {
+	JSN_CFG := `
+{
+"diameter_agent": {
+	"enabled": true,
+	"listen_net": "sctp",
+},
+}`
+	if _, err := NewCGRConfigFromJsonStringWithDefaults(JSN_CFG); err == nil ||
+		err.Error() != "<DiameterAgent> unsupported listen_net: sctp" {
+		t.Errorf("Received: %v", err)
+	}
+	JSN_CFG = `
+{
+"diameter_agent": {
+	"enabled": true,
+	"listeners": [
+		{"listen": "127.0.0.1:3869", "listen_net": "sctp"},
+	],
+},
+}`
+	if _, err := NewCGRConfigFromJsonStringWithDefaults(JSN_CFG); err == nil ||
+		err.Error() != "<DiameterAgent> listener: 127.0.0.1:3869, unsupported listen_net: sctp" {
+		t.Errorf("Received: %v", err)
+	}
+}
+
+func TestCgrCfgDiameterAgentRequestProcessors(t *testing.T) {
+	JSN_CFG := `
+{
+"diameter_agent": {
+	"request_processors": [
+		{"id": "CCR"},
+		{"id": "SH_UDR", "command_code": 306, "application_id": 16777217},
+	],
+},
+}`
+	eProcessors := []*DARequestProcessor{
+		&DARequestProcessor{Id: "CCR", CommandCode: 272},
+		&DARequestProcessor{Id: "SH_UDR", CommandCode: 306, ApplicationID: 16777217},
+	}
+	if cgrCfg, err := NewCGRConfigFromJsonStringWithDefaults(JSN_CFG); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eProcessors, cgrCfg.diameterAgentCfg.RequestProcessors) {
+		t.Errorf("Expected: %+v, received: %+v", eProcessors, cgrCfg.diameterAgentCfg.RequestProcessors)
+	}
+}
+
 func TestCgrCfgJSONDefaultsMailer(t *testing.T) {
 	if cgrCfg.MailerServer != "localhost" {
 		t.Error(cgrCfg.MailerServer)
@@ -852,10 +952,13 @@ func TestRadiusAgentCfg(t *testing.T) {
 		ListenAcct:         "127.0.0.1:1813",
 		ClientSecrets:      map[string]string{utils.META_DEFAULT: "CGRateS.org"},
 		ClientDictionaries: map[string]string{utils.META_DEFAULT: "/usr/share/cgrates/radius/dict/"},
+		ClientDaAddresses:  map[string]string{},
 		SessionSConns:      []*HaPoolConfig{&HaPoolConfig{Address: utils.MetaInternal}},
 		CreateCDR:          true,
 		CDRRequiresSession: false,
 		Timezone:           "",
+		ReplyTimeout:       time.Duration(2 * time.Second),
+		SessionTTL:         time.Duration(24 * time.Hour),
 		RequestProcessors:  nil,
 	}
 	if !reflect.DeepEqual(cgrCfg.radiusAgentCfg.Enabled, testRA.Enabled) {
@@ -888,6 +991,21 @@ func TestRadiusAgentCfg(t *testing.T) {
 	if !reflect.DeepEqual(cgrCfg.radiusAgentCfg.Timezone, testRA.Timezone) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.radiusAgentCfg.Timezone, testRA.Timezone)
 	}
+	if !reflect.DeepEqual(cgrCfg.radiusAgentCfg.ClientDaAddresses, testRA.ClientDaAddresses) {
+		t.Errorf("received: %+v, expecting: %+v", cgrCfg.radiusAgentCfg.ClientDaAddresses, testRA.ClientDaAddresses)
+	}
+	if cgrCfg.radiusAgentCfg.ReplyTimeout != testRA.ReplyTimeout {
+		t.Errorf("received: %+v, expecting: %+v", cgrCfg.radiusAgentCfg.ReplyTimeout, testRA.ReplyTimeout)
+	}
+	if cgrCfg.radiusAgentCfg.SessionTTL != testRA.SessionTTL {
+		t.Errorf("received: %+v, expecting: %+v", cgrCfg.radiusAgentCfg.SessionTTL, testRA.SessionTTL)
+	}
+	if len(cgrCfg.radiusAgentCfg.DisconnectFields) != 3 {
+		t.Errorf("received: %+v", cgrCfg.radiusAgentCfg.DisconnectFields)
+	}
+	if len(cgrCfg.radiusAgentCfg.CoAFields) != 4 {
+		t.Errorf("received: %+v", cgrCfg.radiusAgentCfg.CoAFields)
+	}
 	if !reflect.DeepEqual(cgrCfg.radiusAgentCfg.RequestProcessors, testRA.RequestProcessors) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.radiusAgentCfg.RequestProcessors, testRA.RequestProcessors)
 	}
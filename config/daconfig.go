@@ -27,17 +27,22 @@ import (
 type DiameterAgentCfg struct {
 	Enabled            bool   // enables the diameter agent: <true|false>
 	Listen             string // address where to listen for diameter requests <x.y.z.y:1234>
+	ListenNet          string // transport type for diameter <tcp>
 	DictionariesDir    string
 	SessionSConns      []*HaPoolConfig // connections towards SMG component
 	PubSubConns        []*HaPoolConfig // connection towards pubsubs
 	CreateCDR          bool
 	CDRRequiresSession bool
 	DebitInterval      time.Duration
-	Timezone           string // timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
+	ReplyTimeout       time.Duration // timeout waiting for answers to server initiated requests
+	Timezone           string        // timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
 	OriginHost         string
 	OriginRealm        string
 	VendorId           int
 	ProductName        string
+	Applications       []int         // Auth-Application-Ids advertised in CEA, empty to accept the ones offered by peer
+	WatchdogInterval   time.Duration // interval to send Device-Watchdog-Request towards idle peers
+	Listeners          []*DiameterListenerCfg
 	RequestProcessors  []*DARequestProcessor
 }
 
@@ -51,6 +56,9 @@ func (self *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg) erro
 	if jsnCfg.Listen != nil {
 		self.Listen = *jsnCfg.Listen
 	}
+	if jsnCfg.Listen_net != nil {
+		self.ListenNet = *jsnCfg.Listen_net
+	}
 	if jsnCfg.Dictionaries_dir != nil {
 		self.DictionariesDir = *jsnCfg.Dictionaries_dir
 	}
@@ -80,6 +88,12 @@ func (self *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg) erro
 			return err
 		}
 	}
+	if jsnCfg.Reply_timeout != nil {
+		var err error
+		if self.ReplyTimeout, err = utils.ParseDurationWithNanosecs(*jsnCfg.Reply_timeout); err != nil {
+			return err
+		}
+	}
 	if jsnCfg.Timezone != nil {
 		self.Timezone = *jsnCfg.Timezone
 	}
@@ -95,9 +109,25 @@ func (self *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg) erro
 	if jsnCfg.Product_name != nil {
 		self.ProductName = *jsnCfg.Product_name
 	}
+	if jsnCfg.Applications != nil {
+		self.Applications = *jsnCfg.Applications
+	}
+	if jsnCfg.Watchdog_interval != nil {
+		var err error
+		if self.WatchdogInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Watchdog_interval); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Listeners != nil {
+		self.Listeners = make([]*DiameterListenerCfg, len(*jsnCfg.Listeners))
+		for idx, jsnLstnCfg := range *jsnCfg.Listeners {
+			self.Listeners[idx] = new(DiameterListenerCfg)
+			self.Listeners[idx].loadFromJsonCfg(jsnLstnCfg)
+		}
+	}
 	if jsnCfg.Request_processors != nil {
 		for _, reqProcJsn := range *jsnCfg.Request_processors {
-			rp := new(DARequestProcessor)
+			rp := &DARequestProcessor{CommandCode: 272} // Credit-Control unless configured otherwise
 			var haveID bool
 			for _, rpSet := range self.RequestProcessors {
 				if reqProcJsn.Id != nil && rpSet.Id == *reqProcJsn.Id {
@@ -117,15 +147,57 @@ func (self *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg) erro
 	return nil
 }
 
+// DiameterListenerCfg is one listener with it's own Diameter identity
+// unset fields are inherited from the DiameterAgentCfg ones
+type DiameterListenerCfg struct {
+	Listen       string
+	ListenNet    string
+	OriginHost   string
+	OriginRealm  string
+	VendorId     int
+	ProductName  string
+	Applications []int
+}
+
+func (self *DiameterListenerCfg) loadFromJsonCfg(jsnCfg *DiameterListenerJsonCfg) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Listen != nil {
+		self.Listen = *jsnCfg.Listen
+	}
+	if jsnCfg.Listen_net != nil {
+		self.ListenNet = *jsnCfg.Listen_net
+	}
+	if jsnCfg.Origin_host != nil {
+		self.OriginHost = *jsnCfg.Origin_host
+	}
+	if jsnCfg.Origin_realm != nil {
+		self.OriginRealm = *jsnCfg.Origin_realm
+	}
+	if jsnCfg.Vendor_id != nil {
+		self.VendorId = *jsnCfg.Vendor_id
+	}
+	if jsnCfg.Product_name != nil {
+		self.ProductName = *jsnCfg.Product_name
+	}
+	if jsnCfg.Applications != nil {
+		self.Applications = *jsnCfg.Applications
+	}
+}
+
 // One Diameter request processor configuration
 type DARequestProcessor struct {
 	Id                string
+	CommandCode       int // command code of the requests processed, 272 for Credit-Control
+	ApplicationID     int // application of the requests processed, 0 for any
 	DryRun            bool
 	PublishEvent      bool
 	RequestFilter     utils.RSRFields
 	Flags             utils.StringMap // Various flags to influence behavior
 	ContinueOnSuccess bool
 	AppendCCA         bool
+	MultipleServices  bool // process each Multiple-Services-Credit-Control as own session
 	CCRFields         []*CfgCdrField
 	CCAFields         []*CfgCdrField
 }
@@ -137,6 +209,12 @@ func (self *DARequestProcessor) loadFromJsonCfg(jsnCfg *DARequestProcessorJsnCfg
 	if jsnCfg.Id != nil {
 		self.Id = *jsnCfg.Id
 	}
+	if jsnCfg.Command_code != nil {
+		self.CommandCode = *jsnCfg.Command_code
+	}
+	if jsnCfg.Application_id != nil {
+		self.ApplicationID = *jsnCfg.Application_id
+	}
 	if jsnCfg.Dry_run != nil {
 		self.DryRun = *jsnCfg.Dry_run
 	}
@@ -158,6 +236,9 @@ func (self *DARequestProcessor) loadFromJsonCfg(jsnCfg *DARequestProcessorJsnCfg
 	if jsnCfg.Append_cca != nil {
 		self.AppendCCA = *jsnCfg.Append_cca
 	}
+	if jsnCfg.Multiple_services != nil {
+		self.MultipleServices = *jsnCfg.Multiple_services
+	}
 	if jsnCfg.CCR_fields != nil {
 		if self.CCRFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.CCR_fields); err != nil {
 			return err
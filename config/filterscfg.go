@@ -19,7 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package config
 
 type FilterSCfg struct {
-	StatSConns []*HaPoolConfig
+	StatSConns     []*HaPoolConfig
+	ResourceSConns []*HaPoolConfig
 }
 
 func (fSCfg *FilterSCfg) loadFromJsonCfg(jsnCfg *FilterSJsonCfg) (err error) {
@@ -33,5 +34,12 @@ func (fSCfg *FilterSCfg) loadFromJsonCfg(jsnCfg *FilterSJsonCfg) (err error) {
 			fSCfg.StatSConns[idx].loadFromJsonCfg(jsnHaCfg)
 		}
 	}
+	if jsnCfg.Resources_conns != nil {
+		fSCfg.ResourceSConns = make([]*HaPoolConfig, len(*jsnCfg.Resources_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Resources_conns {
+			fSCfg.ResourceSConns[idx] = NewDfltHaPoolConfig()
+			fSCfg.ResourceSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
 	return
 }
@@ -74,7 +74,8 @@ type DbJsonCfg struct {
 
 // Filters config
 type FilterSJsonCfg struct {
-	Stats_conns *[]*HaPoolJsonCfg
+	Stats_conns     *[]*HaPoolJsonCfg
+	Resources_conns *[]*HaPoolJsonCfg
 }
 
 // Rater config section
@@ -212,6 +213,8 @@ type SessionSJsonCfg struct {
 	Session_ttl_usage         *string
 	Session_indexes           *[]string
 	Client_protocol           *float64
+	Store_interval            *string
+	Restore_timeout           *string
 }
 
 // SM-FreeSWITCH config section
@@ -311,29 +314,48 @@ type OsipsConnJsonCfg struct {
 type DiameterAgentJsonCfg struct {
 	Enabled              *bool             // enables the diameter agent: <true|false>
 	Listen               *string           // address where to listen for diameter requests <x.y.z.y:1234>
+	Listen_net           *string           // transport type for diameter <tcp>
 	Dictionaries_dir     *string           // path towards additional dictionaries
 	Sessions_conns       *[]*HaPoolJsonCfg // Connections towards generic SM
 	Pubsubs_conns        *[]*HaPoolJsonCfg // connection towards pubsubs
 	Create_cdr           *bool
 	Cdr_requires_session *bool
 	Debit_interval       *string
+	Reply_timeout        *string
 	Timezone             *string // timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
 	Origin_host          *string
 	Origin_realm         *string
 	Vendor_id            *int
 	Product_name         *string
+	Applications         *[]int
+	Watchdog_interval    *string
+	Listeners            *[]*DiameterListenerJsonCfg
 	Request_processors   *[]*DARequestProcessorJsnCfg
 }
 
+// One Diameter listener configuration
+type DiameterListenerJsonCfg struct {
+	Listen       *string
+	Listen_net   *string
+	Origin_host  *string
+	Origin_realm *string
+	Vendor_id    *int
+	Product_name *string
+	Applications *[]int
+}
+
 // One Diameter request processor configuration
 type DARequestProcessorJsnCfg struct {
 	Id                  *string
+	Command_code        *int
+	Application_id      *int
 	Dry_run             *bool
 	Publish_event       *bool
 	Request_filter      *string
 	Flags               *[]string
 	Continue_on_success *bool
 	Append_cca          *bool
+	Multiple_services   *bool
 	CCR_fields          *[]*CdrFieldJsonCfg
 	CCA_fields          *[]*CdrFieldJsonCfg
 }
@@ -346,10 +368,15 @@ type RadiusAgentJsonCfg struct {
 	Listen_acct          *string
 	Client_secrets       *map[string]string
 	Client_dictionaries  *map[string]string
+	Client_da_addresses  *map[string]string
 	Sessions_conns       *[]*HaPoolJsonCfg
 	Create_cdr           *bool
 	Cdr_requires_session *bool
 	Timezone             *string
+	Reply_timeout        *string
+	Session_ttl          *string
+	Disconnect_fields    *[]*CdrFieldJsonCfg
+	Coa_fields           *[]*CdrFieldJsonCfg
 	Request_processors   *[]*RAReqProcessorJsnCfg
 }
 
@@ -391,6 +418,7 @@ type UserServJsonCfg struct {
 type AttributeSJsonCfg struct {
 	Enabled        *bool
 	Indexed_fields *[]string
+	Process_runs   *int
 }
 
 // ResourceLimiter service config section
@@ -399,6 +427,7 @@ type ResourceSJsonCfg struct {
 	Thresholds_conns *[]*HaPoolJsonCfg
 	Store_interval   *string
 	Indexed_fields   *[]string
+	Reservation_ttl  *string
 }
 
 // Stat service config section
@@ -414,6 +443,13 @@ type ThresholdSJsonCfg struct {
 	Enabled        *bool
 	Store_interval *string
 	Indexed_fields *[]string
+	Exports        *[]*ThresholdSExportJsonCfg
+}
+
+// Destination of the threshold hits
+type ThresholdSExportJsonCfg struct {
+	Export_format *string
+	Export_path   *string
 }
 
 // Supplier service config section
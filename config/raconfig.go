@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package config
 
 import (
+	"time"
+
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -29,10 +31,15 @@ type RadiusAgentCfg struct {
 	ListenAcct         string
 	ClientSecrets      map[string]string
 	ClientDictionaries map[string]string
+	ClientDaAddresses  map[string]string // per client address of the Dynamic Authorization server (RFC 5176)
 	SessionSConns      []*HaPoolConfig
 	CreateCDR          bool
 	CDRRequiresSession bool
 	Timezone           string
+	ReplyTimeout       time.Duration  // timeout waiting for ACK/NAK of Disconnect and CoA requests
+	SessionTTL         time.Duration  // forget sessions not refreshed by accounting within this interval
+	DisconnectFields   []*CfgCdrField // template for the attributes of Disconnect-Request
+	CoAFields          []*CfgCdrField // template for the attributes of CoA-Request
 	RequestProcessors  []*RARequestProcessor
 }
 
@@ -68,6 +75,14 @@ func (self *RadiusAgentCfg) loadFromJsonCfg(jsnCfg *RadiusAgentJsonCfg) error {
 			self.ClientDictionaries[k] = v
 		}
 	}
+	if jsnCfg.Client_da_addresses != nil {
+		if self.ClientDaAddresses == nil {
+			self.ClientDaAddresses = make(map[string]string)
+		}
+		for k, v := range *jsnCfg.Client_da_addresses {
+			self.ClientDaAddresses[k] = v
+		}
+	}
 	if jsnCfg.Sessions_conns != nil {
 		self.SessionSConns = make([]*HaPoolConfig, len(*jsnCfg.Sessions_conns))
 		for idx, jsnHaCfg := range *jsnCfg.Sessions_conns {
@@ -84,6 +99,27 @@ func (self *RadiusAgentCfg) loadFromJsonCfg(jsnCfg *RadiusAgentJsonCfg) error {
 	if jsnCfg.Timezone != nil {
 		self.Timezone = *jsnCfg.Timezone
 	}
+	var err error
+	if jsnCfg.Reply_timeout != nil {
+		if self.ReplyTimeout, err = utils.ParseDurationWithNanosecs(*jsnCfg.Reply_timeout); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Session_ttl != nil {
+		if self.SessionTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Session_ttl); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Disconnect_fields != nil {
+		if self.DisconnectFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Disconnect_fields); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Coa_fields != nil {
+		if self.CoAFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Coa_fields); err != nil {
+			return err
+		}
+	}
 	if jsnCfg.Request_processors != nil {
 		for _, reqProcJsn := range *jsnCfg.Request_processors {
 			rp := new(RARequestProcessor)
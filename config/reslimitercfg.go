@@ -29,6 +29,7 @@ type ResourceSConfig struct {
 	ThresholdSConns []*HaPoolConfig // Connections towards StatS
 	StoreInterval   time.Duration   // Dump regularly from cache into dataDB
 	IndexedFields   []string
+	ReservationTTL  time.Duration // Hold reserved units for this long unless confirmed
 }
 
 func (rlcfg *ResourceSConfig) loadFromJsonCfg(jsnCfg *ResourceSJsonCfg) (err error) {
@@ -56,5 +57,10 @@ func (rlcfg *ResourceSConfig) loadFromJsonCfg(jsnCfg *ResourceSJsonCfg) (err err
 			rlcfg.IndexedFields[i] = fID
 		}
 	}
+	if jsnCfg.Reservation_ttl != nil {
+		if rlcfg.ReservationTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Reservation_ttl); err != nil {
+			return
+		}
+	}
 	return nil
 }
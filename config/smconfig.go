@@ -106,6 +106,8 @@ type SessionSCfg struct {
 	SessionTTLUsage         *time.Duration
 	SessionIndexes          utils.StringMap
 	ClientProtocol          float64
+	StoreInterval           time.Duration // store active sessions into dataDB, -1 on each change
+	RestoreTimeout          time.Duration // terminate the restored sessions not claimed back by their agents within this interval
 }
 
 func (self *SessionSCfg) loadFromJsonCfg(jsnCfg *SessionSJsonCfg) error {
@@ -201,6 +203,16 @@ func (self *SessionSCfg) loadFromJsonCfg(jsnCfg *SessionSJsonCfg) error {
 	if jsnCfg.Client_protocol != nil {
 		self.ClientProtocol = *jsnCfg.Client_protocol
 	}
+	if jsnCfg.Store_interval != nil {
+		if self.StoreInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Store_interval); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Restore_timeout != nil {
+		if self.RestoreTimeout, err = utils.ParseDurationWithNanosecs(*jsnCfg.Restore_timeout); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -28,6 +28,26 @@ type ThresholdSCfg struct {
 	Enabled       bool
 	StoreInterval time.Duration // Dump regularly from cache into dataDB
 	IndexedFields []string
+	Exports       []*ThresholdSExportCfg // post threshold hits to these destinations
+}
+
+// ThresholdSExportCfg is one destination of the threshold hits
+type ThresholdSExportCfg struct {
+	ExportFormat string // <*http_json|*amqp_json_map>
+	ExportPath   string // HTTP URL or AMQP dial URL
+}
+
+func (te *ThresholdSExportCfg) loadFromJsonCfg(jsnCfg *ThresholdSExportJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return nil
+	}
+	if jsnCfg.Export_format != nil {
+		te.ExportFormat = *jsnCfg.Export_format
+	}
+	if jsnCfg.Export_path != nil {
+		te.ExportPath = *jsnCfg.Export_path
+	}
+	return nil
 }
 
 func (t *ThresholdSCfg) loadFromJsonCfg(jsnCfg *ThresholdSJsonCfg) (err error) {
@@ -48,5 +68,14 @@ func (t *ThresholdSCfg) loadFromJsonCfg(jsnCfg *ThresholdSJsonCfg) (err error) {
 			t.IndexedFields[i] = fID
 		}
 	}
+	if jsnCfg.Exports != nil {
+		t.Exports = make([]*ThresholdSExportCfg, len(*jsnCfg.Exports))
+		for i, jsnExp := range *jsnCfg.Exports {
+			t.Exports[i] = new(ThresholdSExportCfg)
+			if err = t.Exports[i].loadFromJsonCfg(jsnExp); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
@@ -84,6 +84,11 @@ type StringWrapper struct {
 	Item string
 }
 
+// TenantWrapper is used by commands sending the tenant as the only RPC parameter
+type TenantWrapper struct {
+	Tenant string
+}
+
 type StringSliceWrapper struct {
 	Items []string
 }
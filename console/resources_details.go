@@ -0,0 +1,68 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package console
+
+import (
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+)
+
+func init() {
+	c := &CmdGetResourcesDetails{
+		name:      "resources_details",
+		rpcMethod: "ResourceSv1.GetResourcesDetails",
+		rpcParams: &engine.ArgGetResourcesDetails{},
+	}
+	commands[c.Name()] = c
+	c.CommandExecuter = &CommandExecuter{c}
+}
+
+// CmdGetResourcesDetails shows the active usages of the resources
+type CmdGetResourcesDetails struct {
+	name      string
+	rpcMethod string
+	rpcParams *engine.ArgGetResourcesDetails
+	*CommandExecuter
+}
+
+func (self *CmdGetResourcesDetails) Name() string {
+	return self.name
+}
+
+func (self *CmdGetResourcesDetails) RpcMethod() string {
+	return self.rpcMethod
+}
+
+func (self *CmdGetResourcesDetails) RpcParams(reset bool) interface{} {
+	if reset || self.rpcParams == nil {
+		self.rpcParams = &engine.ArgGetResourcesDetails{}
+	}
+	return self.rpcParams
+}
+
+func (self *CmdGetResourcesDetails) PostprocessRpcParams() error {
+	if self.rpcParams.Tenant == "" {
+		self.rpcParams.Tenant = config.CgrConfig().DefaultTenant
+	}
+	return nil
+}
+
+func (self *CmdGetResourcesDetails) RpcResult() interface{} {
+	var rDtls []*engine.ResourceDetails
+	return &rDtls
+}
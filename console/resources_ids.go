@@ -0,0 +1,68 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package console
+
+import (
+	"github.com/cgrates/cgrates/config"
+)
+
+func init() {
+	c := &CmdGetResourceIDs{
+		name:      "resources_ids",
+		rpcMethod: "ResourceSv1.GetResourceIDs",
+	}
+	commands[c.Name()] = c
+	c.CommandExecuter = &CommandExecuter{c}
+}
+
+// CmdGetResourceIDs lists the Resource IDs of a tenant
+type CmdGetResourceIDs struct {
+	name      string
+	rpcMethod string
+	rpcParams interface{}
+	*CommandExecuter
+}
+
+func (self *CmdGetResourceIDs) Name() string {
+	return self.name
+}
+
+func (self *CmdGetResourceIDs) RpcMethod() string {
+	return self.rpcMethod
+}
+
+func (self *CmdGetResourceIDs) RpcParams(reset bool) interface{} {
+	if reset || self.rpcParams == nil {
+		self.rpcParams = &TenantWrapper{}
+	}
+	return self.rpcParams
+}
+
+func (self *CmdGetResourceIDs) PostprocessRpcParams() error { // the API expects the tenant as string
+	tenant := self.rpcParams.(*TenantWrapper).Tenant
+	if tenant == "" {
+		tenant = config.CgrConfig().DefaultTenant
+	}
+	self.rpcParams = tenant
+	return nil
+}
+
+func (self *CmdGetResourceIDs) RpcResult() interface{} {
+	var s []string
+	return &s
+}
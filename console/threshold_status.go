@@ -0,0 +1,69 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package console
+
+import (
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func init() {
+	c := &CmdGetThresholdStatus{
+		name:      "threshold_status",
+		rpcMethod: utils.ThresholdSv1GetThreshold,
+		rpcParams: &utils.TenantID{},
+	}
+	commands[c.Name()] = c
+	c.CommandExecuter = &CommandExecuter{c}
+}
+
+// CmdGetThresholdStatus queries the Hits and Snooze of a Threshold
+type CmdGetThresholdStatus struct {
+	name      string
+	rpcMethod string
+	rpcParams *utils.TenantID
+	*CommandExecuter
+}
+
+func (self *CmdGetThresholdStatus) Name() string {
+	return self.name
+}
+
+func (self *CmdGetThresholdStatus) RpcMethod() string {
+	return self.rpcMethod
+}
+
+func (self *CmdGetThresholdStatus) RpcParams(reset bool) interface{} {
+	if reset || self.rpcParams == nil {
+		self.rpcParams = &utils.TenantID{}
+	}
+	return self.rpcParams
+}
+
+func (self *CmdGetThresholdStatus) PostprocessRpcParams() error {
+	if self.rpcParams.Tenant == "" {
+		self.rpcParams.Tenant = config.CgrConfig().DefaultTenant
+	}
+	return nil
+}
+
+func (self *CmdGetThresholdStatus) RpcResult() interface{} {
+	th := engine.Threshold{}
+	return &th
+}
@@ -410,6 +410,8 @@ CREATE TABLE tp_resources (
   `stored` BOOLEAN NOT NULL,
   `weight` decimal(8,2) NOT NULL,
   `thresholds` varchar(64) NOT NULL,
+  `rate_interval` varchar(32) NOT NULL,
+  `timed_limits` varchar(64) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`pk`),
   KEY `tpid` (`tpid`),
@@ -437,6 +439,7 @@ CREATE TABLE tp_stats (
   `weight` decimal(8,2) NOT NULL,
   `min_items` int(11) NOT NULL,
   `thresholds` varchar(64) NOT NULL,
+  `bucket_interval` varchar(32) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`pk`),
   KEY `tpid` (`tpid`),
@@ -540,6 +543,7 @@ CREATE TABLE tp_attributes (
   `substitute` varchar(64) NOT NULL,
   `append` BOOLEAN NOT NULL,
   `weight` decimal(8,2) NOT NULL,
+  `type` varchar(64) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`pk`),
   KEY `tpid` (`tpid`),
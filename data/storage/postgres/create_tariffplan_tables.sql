@@ -406,6 +406,8 @@ CREATE TABLE tp_resources (
   "stored" BOOLEAN NOT NULL,
   "weight" NUMERIC(8,2) NOT NULL,
   "thresholds" varchar(64) NOT NULL,
+  "rate_interval" varchar(32) NOT NULL,
+  "timed_limits" varchar(64) NOT NULL,
   "created_at" TIMESTAMP WITH TIME ZONE
 );
 CREATE INDEX tp_resources_idx ON tp_resources (tpid);
@@ -433,6 +435,7 @@ CREATE TABLE tp_stats (
   "weight" decimal(8,2) NOT NULL,
   "min_items" INTEGER NOT NULL,
   "thresholds" varchar(64) NOT NULL,
+  "bucket_interval" varchar(32) NOT NULL,
   "created_at" TIMESTAMP WITH TIME ZONE
 );
 CREATE INDEX tp_stats_idx ON tp_stats (tpid);
@@ -530,6 +533,7 @@ CREATE INDEX tp_suppliers_unique ON tp_suppliers  ("tpid",  "tenant", "id",
     "substitute" varchar(64) NOT NULL,
     "append" BOOLEAN NOT NULL,
     "weight" decimal(8,2) NOT NULL,
+    "type" varchar(64) NOT NULL,
     "created_at" TIMESTAMP WITH TIME ZONE
   );
   CREATE INDEX tp_attributes_ids ON tp_attributes (tpid);
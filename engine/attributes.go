@@ -27,14 +27,18 @@ import (
 	"github.com/cgrates/cgrates/utils"
 )
 
-func NewAttributeService(dm *DataManager, filterS *FilterS, indexedFields []string) (*AttributeService, error) {
-	return &AttributeService{dm: dm, filterS: filterS, indexedFields: indexedFields}, nil
+func NewAttributeService(dm *DataManager, filterS *FilterS, indexedFields []string, processRuns int) (*AttributeService, error) {
+	if processRuns < 1 {
+		processRuns = 1
+	}
+	return &AttributeService{dm: dm, filterS: filterS, indexedFields: indexedFields, processRuns: processRuns}, nil
 }
 
 type AttributeService struct {
 	dm            *DataManager
 	filterS       *FilterS
 	indexedFields []string
+	processRuns   int // number of passes matching the altered event against the profiles not yet applied
 }
 
 // ListenAndServe will initialize the service
@@ -104,35 +108,60 @@ func (alS *AttributeService) matchingAttributeProfilesForEvent(ev *utils.CGREven
 	return
 }
 
-func (alS *AttributeService) attributeProfileForEvent(ev *utils.CGREvent) (attrPrfl *AttributeProfile, err error) {
+// attributeProfileForEvent returns the highest weight profile matching the event, ignoring the ones in ignorePrfls
+func (alS *AttributeService) attributeProfileForEvent(ev *utils.CGREvent, ignorePrfls utils.StringMap) (attrPrfl *AttributeProfile, err error) {
 	var attrPrfls AttributeProfiles
 	if attrPrfls, err = alS.matchingAttributeProfilesForEvent(ev); err != nil {
 		return
-	} else if len(attrPrfls) == 0 {
-		return nil, utils.ErrNotFound
 	}
-	return attrPrfls[0], nil
+	for _, attrPrfl = range attrPrfls {
+		if !ignorePrfls[attrPrfl.ID] {
+			return attrPrfl, nil
+		}
+	}
+	return nil, utils.ErrNotFound
 }
 
 type AttrSProcessEventReply struct {
-	MatchedProfile string
-	AlteredFields  []string
-	CGREvent       *utils.CGREvent
+	MatchedProfiles []string // in the order they were applied
+	AlteredFields   []string
+	CGREvent        *utils.CGREvent
 }
 
-// processEvent will match event with attribute profile and do the necessary replacements
+// processEvent will match event with attribute profiles and do the necessary replacements
+// each of the processRuns passes matches the altered event against the profiles not yet applied
 func (alS *AttributeService) processEvent(ev *utils.CGREvent) (rply *AttrSProcessEventReply, err error) {
-	attrPrf, err := alS.attributeProfileForEvent(ev)
-	if err != nil {
-		return nil, err
+	rply = &AttrSProcessEventReply{CGREvent: ev.Clone()}
+	appliedPrfls := make(utils.StringMap)
+	for i := 0; i < alS.processRuns; i++ {
+		attrPrf, err := alS.attributeProfileForEvent(rply.CGREvent, appliedPrfls)
+		if err != nil {
+			if err == utils.ErrNotFound && i != 0 { // no more profiles matching
+				break
+			}
+			return nil, err
+		}
+		appliedPrfls[attrPrf.ID] = true
+		rply.MatchedProfiles = append(rply.MatchedProfiles, attrPrf.ID)
+		if err = alS.applyAttributeProfile(attrPrf, rply); err != nil {
+			return nil, err
+		}
 	}
-	rply = &AttrSProcessEventReply{MatchedProfile: attrPrf.ID, CGREvent: ev.Clone()}
+	return
+}
+
+// applyAttributeProfile does the replacements of attrPrf within rply.CGREvent
+func (alS *AttributeService) applyAttributeProfile(attrPrf *AttributeProfile, rply *AttrSProcessEventReply) (err error) {
 	for fldName, intialMp := range attrPrf.Attributes {
-		initEvValIf, has := ev.Event[fldName]
+		initEvValIf, has := rply.CGREvent.Event[fldName]
 		if !has { // we don't have initial in event, try append
 			if anyInitial, has := intialMp[utils.ANY]; has && anyInitial.Append {
-				rply.CGREvent.Event[fldName] = anyInitial.Substitute
-				rply.AlteredFields = append(rply.AlteredFields, fldName)
+				var sbstVal string
+				if sbstVal, err = anyInitial.SubstituteValue(rply.CGREvent.Event); err != nil {
+					return
+				}
+				rply.CGREvent.Event[fldName] = sbstVal
+				rply.addAlteredField(fldName)
 			}
 			continue
 		}
@@ -140,7 +169,7 @@ func (alS *AttributeService) processEvent(ev *utils.CGREvent) (rply *AttrSProces
 		if !cast {
 			utils.Logger.Warning(
 				fmt.Sprintf("<%s> ev: %s, cannot cast field: %+v to string",
-					utils.AttributeS, ev, fldName))
+					utils.AttributeS, rply.CGREvent, fldName))
 			continue
 		}
 		attrVal, has := intialMp[initEvVal]
@@ -148,12 +177,16 @@ func (alS *AttributeService) processEvent(ev *utils.CGREvent) (rply *AttrSProces
 			attrVal, has = intialMp[utils.ANY]
 		}
 		if has {
-			rply.CGREvent.Event[fldName] = attrVal.Substitute
-			rply.AlteredFields = append(rply.AlteredFields, fldName)
+			var sbstVal string
+			if sbstVal, err = attrVal.SubstituteValue(rply.CGREvent.Event); err != nil {
+				return
+			}
+			rply.CGREvent.Event[fldName] = sbstVal
+			rply.addAlteredField(fldName)
 		}
 		for _, valIface := range rply.CGREvent.Event {
 			if valIface == interface{}(utils.MetaAttributes) {
-				return nil, utils.NewCGRError(utils.AttributeSv1ProcessEvent,
+				return utils.NewCGRError(utils.AttributeSv1ProcessEvent,
 					utils.AttributesNotFoundCaps,
 					utils.AttributesNotFound,
 					utils.AttributesNotFound)
@@ -163,9 +196,19 @@ func (alS *AttributeService) processEvent(ev *utils.CGREvent) (rply *AttrSProces
 	return
 }
 
+// addAlteredField records fldName once within AlteredFields
+func (rply *AttrSProcessEventReply) addAlteredField(fldName string) {
+	for _, altered := range rply.AlteredFields {
+		if altered == fldName {
+			return
+		}
+	}
+	rply.AlteredFields = append(rply.AlteredFields, fldName)
+}
+
 func (alS *AttributeService) V1GetAttributeForEvent(ev *utils.CGREvent,
 	extattrPrf *ExternalAttributeProfile) (err error) {
-	attrPrf, err := alS.attributeProfileForEvent(ev)
+	attrPrf, err := alS.attributeProfileForEvent(ev, nil)
 	if err != nil {
 		if err != utils.ErrNotFound {
 			err = utils.NewErrServerError(err)
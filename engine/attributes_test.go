@@ -38,6 +38,7 @@ var sTestsAttributes = []func(t *testing.T){
 	testAttributeMatchingAttributeProfilesForEvent,
 	testAttributeProfileForEvent,
 	testAttributeProcessEvent,
+	testAttributeProcessEventWithRuns,
 }
 
 func TestAttributes(t *testing.T) {
@@ -116,6 +117,7 @@ func testPopulateAttrService(t *testing.T) {
 		dm:            dmAtr,
 		filterS:       &FilterS{dm: dmAtr},
 		indexedFields: []string{"attributeprofile1", "attributeprofile2"},
+		processRuns:   1,
 	}
 	sev = &utils.CGREvent{
 		Tenant:  "cgrates.org",
@@ -169,7 +171,7 @@ func testAttributeProfileForEvent(t *testing.T) {
 			"Weight":            "9.0",
 		},
 	}
-	atrpl, err := srv.attributeProfileForEvent(sev)
+	atrpl, err := srv.attributeProfileForEvent(sev, nil)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
@@ -191,18 +193,101 @@ func testAttributeProcessEvent(t *testing.T) {
 		},
 	}
 	eRply := &AttrSProcessEventReply{
-		MatchedProfile: "attributeprofile1",
-		CGREvent:       sev,
+		MatchedProfiles: []string{"attributeprofile1"},
+		CGREvent:        sev,
 	}
 	atrpl, err := srv.processEvent(sev)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
-	if !reflect.DeepEqual(eRply.MatchedProfile, atrpl.MatchedProfile) {
-		t.Errorf("Expecting: %+v, received: %+v", eRply.MatchedProfile, atrpl.MatchedProfile)
+	if !reflect.DeepEqual(eRply.MatchedProfiles, atrpl.MatchedProfiles) {
+		t.Errorf("Expecting: %+v, received: %+v", eRply.MatchedProfiles, atrpl.MatchedProfiles)
 	} else if !reflect.DeepEqual(eRply.AlteredFields, atrpl.AlteredFields) {
 		t.Errorf("Expecting: %+v, received: %+v", eRply.AlteredFields, atrpl.AlteredFields)
 	} else if !reflect.DeepEqual(eRply.CGREvent, atrpl.CGREvent) {
 		t.Errorf("Expecting: %+v, received: %+v", eRply.CGREvent, atrpl.CGREvent)
 	}
 }
+
+func testAttributeProcessEventWithRuns(t *testing.T) {
+	context := utils.MetaRating
+	sev = &utils.CGREvent{
+		Tenant:  "cgrates.org",
+		ID:      "attribute_event",
+		Context: &context,
+		Event: map[string]interface{}{
+			"attributeprofile1": "Attribute",
+			"attributeprofile2": "Attribute",
+			"UsageInterval":     "1s",
+			"Weight":            "9.0",
+			"FL1":               "In1",
+		},
+	}
+	srv.processRuns = 3
+	defer func() { srv.processRuns = 1 }()
+	atrpl, err := srv.processEvent(sev)
+	if err != nil {
+		t.Fatalf("Error: %+v", err)
+	}
+	if len(atrpl.MatchedProfiles) != 2 ||
+		atrpl.MatchedProfiles[0] == atrpl.MatchedProfiles[1] {
+		t.Errorf("Unexpected matched profiles: %+v", atrpl.MatchedProfiles)
+	}
+	if !reflect.DeepEqual([]string{"FL1"}, atrpl.AlteredFields) {
+		t.Errorf("Expecting: %+v, received: %+v", []string{"FL1"}, atrpl.AlteredFields)
+	} else if atrpl.CGREvent.Event["FL1"] != "Al1" {
+		t.Errorf("Expecting: Al1, received: %+v", atrpl.CGREvent.Event["FL1"])
+	} else if sev.Event["FL1"] != "In1" {
+		t.Errorf("Original event modified: %+v", sev.Event)
+	}
+}
+
+func TestAttributeSubstituteValue(t *testing.T) {
+	ev := map[string]interface{}{
+		utils.Account:    "1001",
+		utils.Subject:    "1002",
+		utils.SetupTime:  "2018-01-07T17:00:00Z",
+		utils.AnswerTime: time.Date(2018, 1, 7, 17, 0, 10, 0, time.UTC),
+		utils.COST:       "1.2",
+	}
+	for _, tc := range []struct {
+		sbstType, sbstStr, eVal string
+	}{
+		{"", "Al1", "Al1"},
+		{"", "~Account", "~Account"}, // no type means static value
+		{"", "*sum:~Cost;^0.3", "*sum:~Cost;^0.3"},
+		{utils.META_CONSTANT, "~Account", "~Account"},
+		{utils.MetaVariable, `~Account:s/^(\d+)$/+49${1}/`, "+491001"},
+		{utils.MetaVariable, `^sip:;~Account;^@;~Subject`, "sip:1001@1002"},
+		{utils.MetaUsageDifference, "AnswerTime;SetupTime", "10s"},
+		{utils.MetaSum, "~Cost;^0.3", "1.5"},
+	} {
+		attr := &Attribute{FieldName: "Field", Initial: utils.ANY,
+			Type: tc.sbstType, Substitute: tc.sbstStr}
+		if err := attr.Compile(); err != nil {
+			t.Errorf("substitute: %s <%s>, error: %s", tc.sbstType, tc.sbstStr, err)
+		} else if val, err := attr.SubstituteValue(ev); err != nil {
+			t.Errorf("substitute: %s <%s>, error: %s", tc.sbstType, tc.sbstStr, err)
+		} else if val != tc.eVal {
+			t.Errorf("substitute: %s <%s>, expecting: <%s>, received: <%s>",
+				tc.sbstType, tc.sbstStr, tc.eVal, val)
+		}
+	}
+	attr := &Attribute{FieldName: "Field", Initial: utils.ANY,
+		Type: utils.MetaVariable, Substitute: "~Destination"}
+	if err := attr.Compile(); err != nil {
+		t.Error(err)
+	} else if _, err := attr.SubstituteValue(ev); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing(utils.Destination).Error() {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	attr.Type = utils.MetaUsageDifference
+	attr.Substitute = "AnswerTime"
+	if err := attr.Compile(); err == nil {
+		t.Error("Expecting error for wrong number of arguments")
+	}
+	attr.Type = "*unsupported"
+	if err := attr.Compile(); err == nil {
+		t.Error("Expecting error for unsupported type")
+	}
+}
@@ -431,10 +431,7 @@ func (dm *DataManager) SetThresholdProfile(th *ThresholdProfile, withIndex bool)
 				}
 			}
 			for _, flt := range fltr.RequestFilters {
-				if flt.Type != MetaString {
-					continue
-				}
-				for _, fldVal := range flt.Values {
+				for _, fldVal := range indexableFilterValues(flt.Type, flt.Values) {
 					if err = indexer.loadFldNameFldValIndex(flt.FieldName, fldVal); err != nil && err != utils.ErrNotFound {
 						return err
 					}
@@ -517,10 +514,7 @@ func (dm *DataManager) SetStatQueueProfile(sqp *StatQueueProfile, withIndex bool
 				}
 			}
 			for _, flt := range fltr.RequestFilters {
-				if flt.Type != MetaString {
-					continue
-				}
-				for _, fldVal := range flt.Values {
+				for _, fldVal := range indexableFilterValues(flt.Type, flt.Values) {
 					if err = indexer.loadFldNameFldValIndex(flt.FieldName, fldVal); err != nil && err != utils.ErrNotFound {
 						return err
 					}
@@ -681,10 +675,7 @@ func (dm *DataManager) SetResourceProfile(rp *ResourceProfile, withIndex bool) (
 				}
 			}
 			for _, flt := range fltr.RequestFilters {
-				if flt.Type != MetaString {
-					continue
-				}
-				for _, fldVal := range flt.Values {
+				for _, fldVal := range indexableFilterValues(flt.Type, flt.Values) {
 					if err = indexer.loadFldNameFldValIndex(flt.FieldName, fldVal); err != nil && err != utils.ErrNotFound {
 						return err
 					}
@@ -1118,10 +1109,7 @@ func (dm *DataManager) SetSupplierProfile(supp *SupplierProfile, withIndex bool)
 				}
 			}
 			for _, flt := range fltr.RequestFilters {
-				if flt.Type != MetaString {
-					continue
-				}
-				for _, fldVal := range flt.Values {
+				for _, fldVal := range indexableFilterValues(flt.Type, flt.Values) {
 					if err = indexer.loadFldNameFldValIndex(flt.FieldName, fldVal); err != nil && err != utils.ErrNotFound {
 						return err
 					}
@@ -1165,6 +1153,9 @@ func (dm *DataManager) GetAttributeProfile(tenant, id string, skipCache bool, tr
 		}
 		return nil, err
 	}
+	if err = alsPrf.Compile(); err != nil {
+		return nil, err
+	}
 	cache.Set(key, alsPrf, cacheCommit(transactionID), transactionID)
 	return
 }
@@ -1225,10 +1216,7 @@ func (dm *DataManager) SetAttributeProfile(ap *AttributeProfile, withIndex bool)
 					}
 				}
 				for _, flt := range fltr.RequestFilters {
-					if flt.Type != MetaString {
-						continue
-					}
-					for _, fldVal := range flt.Values {
+					for _, fldVal := range indexableFilterValues(flt.Type, flt.Values) {
 						if err = indexer.loadFldNameFldValIndex(flt.FieldName, fldVal); err != nil && err != utils.ErrNotFound {
 							return err
 						}
@@ -1261,3 +1249,18 @@ func (dm *DataManager) RemoveAttributeProfile(tenant, id string, contexts []stri
 	}
 	return
 }
+
+// GetStoredSession retrieves the state of an active session from dataDB, not cached since it changes with each debit
+func (dm *DataManager) GetStoredSession(cgrID string) (ss *StoredSession, err error) {
+	return dm.dataDB.GetStoredSessionDrv(cgrID)
+}
+
+// SetStoredSession stores the state of an active session into dataDB
+func (dm *DataManager) SetStoredSession(ss *StoredSession) (err error) {
+	return dm.dataDB.SetStoredSessionDrv(ss)
+}
+
+// RemoveStoredSession removes the state of a session from dataDB
+func (dm *DataManager) RemoveStoredSession(cgrID string) (err error) {
+	return dm.dataDB.RemoveStoredSessionDrv(cgrID)
+}
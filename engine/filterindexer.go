@@ -20,6 +20,7 @@ package engine
 
 import (
 	"fmt"
+	"regexp/syntax"
 	"strings"
 
 	"github.com/cgrates/cgrates/cache"
@@ -62,11 +63,12 @@ func (rfi *ReqFilterIndexer) IndexTPFilter(tpFltr *utils.TPFilterProfile, itemID
 		rfi.reveseIndex[itemID] = make(utils.StringMap)
 	}
 	for _, fltr := range tpFltr.Filters {
-		if fltr.Type != MetaString {
+		fldVals := indexableFilterValues(fltr.Type, fltr.Values)
+		if fldVals == nil { // negated (*not), *exists/*empty and non literal filters are checked at match time
 			continue
 		}
 		hasMetaString = true // Mark that we found at least one metatring so we don't index globally
-		for _, fldVal := range fltr.Values {
+		for _, fldVal := range fldVals {
 			concatKey := utils.ConcatenatedKey(fltr.FieldName, fldVal)
 			if _, hasIt := rfi.indexes[concatKey]; !hasIt {
 				rfi.indexes[concatKey] = make(utils.StringMap)
@@ -88,6 +90,104 @@ func (rfi *ReqFilterIndexer) IndexTPFilter(tpFltr *utils.TPFilterProfile, itemID
 	return
 }
 
+// indexableFilterValues returns the field values which can be indexed for a filter
+// *string values are indexed as they are, *regexp ones only if they match exclusively literal values (ie: ^(1001|1002)$)
+// nil is returned for filters which cannot be indexed
+func indexableFilterValues(fltrType string, vals []string) (fldVals []string) {
+	switch fltrType {
+	case MetaString:
+		return vals
+	case MetaRegexp:
+		for _, val := range vals {
+			lits, canIndex := regexpLiterals(val)
+			if !canIndex {
+				return nil
+			}
+			fldVals = append(fldVals, lits...)
+		}
+	}
+	return
+}
+
+// regexpMaxLiterals limits the number of values a *regexp filter can expand to when indexed
+const regexpMaxLiterals = 100
+
+// regexpLiterals returns the literal values matched by an anchored regular expression
+// canIndex is false if the expression can match other values than plain literals
+func regexpLiterals(expr string) (lits []string, canIndex bool) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return
+	}
+	if re.Op != syntax.OpConcat || len(re.Sub) < 3 ||
+		re.Sub[0].Op != syntax.OpBeginText || re.Sub[len(re.Sub)-1].Op != syntax.OpEndText {
+		return
+	}
+	return regexpConcatLiterals(re.Sub[1 : len(re.Sub)-1])
+}
+
+// regexpConcatLiterals expands a list of concatenated expressions into the literal values matched
+func regexpConcatLiterals(subs []*syntax.Regexp) (lits []string, canIndex bool) {
+	lits = []string{""}
+	for _, sub := range subs {
+		subLits, canIndex := regexpNodeLiterals(sub)
+		if !canIndex || len(lits)*len(subLits) > regexpMaxLiterals {
+			return nil, false
+		}
+		concatLits := make([]string, 0, len(lits)*len(subLits))
+		for _, lit := range lits {
+			for _, subLit := range subLits {
+				concatLits = append(concatLits, lit+subLit)
+			}
+		}
+		lits = concatLits
+	}
+	return lits, true
+}
+
+// regexpNodeLiterals expands one node of the parsed expression, the parser factoring common prefixes
+// so ^(1001|1002)$ comes as 100[1-2] and ^(dan|danb)$ as dan(?:|b)
+func regexpNodeLiterals(re *syntax.Regexp) (lits []string, canIndex bool) {
+	if re.Flags&syntax.FoldCase != 0 {
+		return
+	}
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}, true
+	case syntax.OpCapture:
+		return regexpNodeLiterals(re.Sub[0])
+	case syntax.OpConcat:
+		return regexpConcatLiterals(re.Sub)
+	case syntax.OpQuest:
+		if lits, canIndex = regexpNodeLiterals(re.Sub[0]); !canIndex {
+			return
+		}
+		return append([]string{""}, lits...), true
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			subLits, canIndex := regexpNodeLiterals(sub)
+			if !canIndex || len(lits)+len(subLits) > regexpMaxLiterals {
+				return nil, false
+			}
+			lits = append(lits, subLits...)
+		}
+		return lits, true
+	case syntax.OpCharClass:
+		for i := 0; i < len(re.Rune); i += 2 {
+			if len(lits)+int(re.Rune[i+1]-re.Rune[i])+1 > regexpMaxLiterals {
+				return nil, false
+			}
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				lits = append(lits, string(r))
+			}
+		}
+		return lits, true
+	}
+	return
+}
+
 func (rfi *ReqFilterIndexer) cacheRemItemType() {
 	switch rfi.itemType {
 	case utils.ThresholdProfilePrefix:
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,9 +33,13 @@ import (
 const (
 	MetaString         = "*string"
 	MetaStringPrefix   = "*string_prefix"
+	MetaStringSuffix   = "*string_suffix"
+	MetaRegexp         = "*regexp"
 	MetaTimings        = "*timings"
 	MetaRSR            = "*rsr"
 	MetaStatS          = "*stats"
+	MetaResources      = "*resources"
+	MetaAccounts       = "*accounts"
 	MetaDestinations   = "*destinations"
 	MetaMinCapPrefix   = "*min_"
 	MetaMaxCapPrefix   = "*max_"
@@ -42,10 +47,15 @@ const (
 	MetaLessOrEqual    = "*lte"
 	MetaGreaterThan    = "*gt"
 	MetaGreaterOrEqual = "*gte"
+	MetaExists         = "*exists"
+	MetaEmpty          = "*empty"
+	MetaNot            = "*not" // prefix negating the filter type, ie: *notstring
+	MetaOr             = "*or"  // passes if at least one of the referenced filters passes
+	MetaOrSep          = "|"    // separates the filters within an inline *or, ie: *or:FLTR_1|*string:Account:1001
 )
 
-func NewFilterS(cfg *config.CGRConfig, statSChan chan rpcclient.RpcClientConnection, dm *DataManager) *FilterS {
-	return &FilterS{statSChan: statSChan, dm: dm}
+func NewFilterS(cfg *config.CGRConfig, statSChan, resSChan chan rpcclient.RpcClientConnection, dm *DataManager) *FilterS {
+	return &FilterS{cfg: cfg, statSChan: statSChan, resSChan: resSChan, dm: dm}
 }
 
 // FilterS is a service used to take decisions in case of filters
@@ -54,7 +64,10 @@ type FilterS struct {
 	cfg        *config.CGRConfig
 	statSChan  chan rpcclient.RpcClientConnection // reference towards internal statS connection, used for lazy connect
 	statSConns *rpcclient.RpcClientPool
-	sSConnMux  sync.RWMutex // make sure only one goroutine attempts connecting
+	sSConnMux  sync.RWMutex                       // make sure only one goroutine attempts connecting
+	resSChan   chan rpcclient.RpcClientConnection // reference towards internal resourceS connection, used for lazy connect
+	resSConns  *rpcclient.RpcClientPool
+	rSConnMux  sync.RWMutex // make sure only one goroutine attempts connecting
 	dm         *DataManager
 }
 
@@ -70,11 +83,44 @@ func (fS *FilterS) connStatS() (err error) {
 	return
 }
 
-func NewInlineFilter(content string) (f *InlineFilter, err error) {
-	if len(strings.Split(content, utils.InInFieldSep)) != 3 {
-		return nil, fmt.Errorf("parse error for string: <%s>")
+// connResourceS returns will connect towards ResourceS
+func (fS *FilterS) connResourceS() (err error) {
+	fS.rSConnMux.Lock()
+	defer fS.rSConnMux.Unlock()
+	if fS.resSConns != nil { // connection was populated between locks
+		return
 	}
+	fS.resSConns, err = NewRPCPool(rpcclient.POOL_FIRST, fS.cfg.ConnectAttempts, fS.cfg.Reconnects, fS.cfg.ConnectTimeout, fS.cfg.ReplyTimeout,
+		fS.cfg.FilterSCfg().ResourceSConns, fS.resSChan, fS.cfg.InternalTtl)
+	return
+}
+
+func NewInlineFilter(content string) (f *InlineFilter, err error) {
 	contentSplit := strings.Split(content, utils.InInFieldSep)
+	switch baseType, _ := filterBaseType(contentSplit[0]); baseType {
+	case MetaRegexp, MetaAccounts: // value can contain the separator
+		contentSplit = strings.SplitN(content, utils.InInFieldSep, 3)
+	case MetaResources: // *resources:ResGroup1:*lt:10
+		contentSplit = strings.SplitN(content, utils.InInFieldSep, 2)
+		if len(contentSplit) != 2 {
+			return nil, fmt.Errorf("parse error for string: <%s>", content)
+		}
+		return &InlineFilter{Type: contentSplit[0], FieldVal: contentSplit[1]}, nil
+	case MetaOr: // *or:FLTR_1|FLTR_2
+		contentSplit = strings.SplitN(content, utils.InInFieldSep, 2)
+		if len(contentSplit) != 2 {
+			return nil, fmt.Errorf("parse error for string: <%s>", content)
+		}
+		return &InlineFilter{Type: contentSplit[0], FieldVal: contentSplit[1]}, nil
+	}
+	if len(contentSplit) == 2 { // *exists:FieldName or *empty:FieldName
+		if baseType, _ := filterBaseType(contentSplit[0]); baseType == MetaExists || baseType == MetaEmpty {
+			return &InlineFilter{Type: contentSplit[0], FieldName: contentSplit[1]}, nil
+		}
+	}
+	if len(contentSplit) != 3 {
+		return nil, fmt.Errorf("parse error for string: <%s>", content)
+	}
 	return &InlineFilter{Type: contentSplit[0], FieldName: contentSplit[1], FieldVal: contentSplit[2]}, nil
 }
 
@@ -91,7 +137,12 @@ func (inFtr *InlineFilter) AsFilter(tenant string) (f *Filter, err error) {
 		ID:             utils.MetaInline,
 		RequestFilters: make([]*RequestFilter, 1),
 	}
-	rf := &RequestFilter{Type: inFtr.Type, FieldName: inFtr.FieldName, Values: []string{inFtr.FieldVal}}
+	rf := &RequestFilter{Type: inFtr.Type, FieldName: inFtr.FieldName}
+	if baseType, _ := filterBaseType(inFtr.Type); baseType == MetaOr {
+		rf.Values = strings.Split(inFtr.FieldVal, MetaOrSep)
+	} else if inFtr.FieldVal != "" {
+		rf.Values = []string{inFtr.FieldVal}
+	}
 	if err := rf.CompileValues(); err != nil {
 		return nil, err
 	}
@@ -103,6 +154,12 @@ func (inFtr *InlineFilter) AsFilter(tenant string) (f *Filter, err error) {
 // PassFiltersForEvent will check all filters wihin filterIDs and require them passing for event
 // there should be at least one filter passing, ie: if filters are not active event will fail to pass
 func (fS *FilterS) PassFiltersForEvent(tenant string, ev map[string]interface{}, filterIDs []string) (pass bool, err error) {
+	return fS.passFiltersForEvent(tenant, ev, filterIDs, nil)
+}
+
+// passFiltersForEvent is the implementation of PassFiltersForEvent
+// orPath contains the *or filters we are coming from so we can break reference cycles
+func (fS *FilterS) passFiltersForEvent(tenant string, ev map[string]interface{}, filterIDs, orPath []string) (pass bool, err error) {
 	var atLeastOneFilterPassing bool
 	for _, fltrID := range filterIDs {
 		var f *Filter
@@ -111,7 +168,9 @@ func (fS *FilterS) PassFiltersForEvent(tenant string, ev map[string]interface{},
 			if err != nil {
 				return false, err
 			}
-			f, err = inFtr.AsFilter(tenant)
+			if f, err = inFtr.AsFilter(tenant); err != nil {
+				return false, err
+			}
 		} else {
 			f, err = fS.dm.GetFilter(tenant, fltrID, false, utils.NonTransactional)
 			if err != nil {
@@ -123,28 +182,42 @@ func (fS *FilterS) PassFiltersForEvent(tenant string, ev map[string]interface{},
 			continue
 		}
 		for _, fltr := range f.RequestFilters {
-			switch fltr.Type {
-			case MetaString:
-				pass, err = fltr.passString(ev, "")
-			case MetaStringPrefix:
-				pass, err = fltr.passStringPrefix(ev, "")
-			case MetaTimings:
-				pass, err = fltr.passTimings(ev, "")
-			case MetaDestinations:
-				pass, err = fltr.passDestinations(ev, "")
-			case MetaRSR:
-				pass, err = fltr.passRSR(ev, "")
+			var rpcClnt rpcclient.RpcClientConnection
+			baseType, negative := filterBaseType(fltr.Type)
+			switch baseType {
+			case MetaString, MetaStringPrefix, MetaStringSuffix, MetaRegexp, MetaTimings, MetaDestinations, MetaRSR,
+				MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual,
+				MetaExists, MetaEmpty:
 			case MetaStatS:
 				if err = fS.connStatS(); err != nil {
 					return false, err
 				}
-				pass, err = fltr.passStatS(ev, "", fS.statSConns)
-			case MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual:
-				pass, err = fltr.passGreaterThan(ev, "")
+				rpcClnt = fS.statSConns
+			case MetaResources:
+				if err = fS.connResourceS(); err != nil {
+					return false, err
+				}
+				rpcClnt = fS.resSConns
+			case MetaAccounts: // checked against the accounts in our own DataManager
+				if pass, err = fltr.passAccounts(ev, "", tenant, fS.dm); err != nil {
+					return false, err
+				}
+				if pass == negative {
+					return false, nil
+				}
+				continue
+			case MetaOr: // composed out of other filters
+				if pass, err = fS.passOrFilters(tenant, ev, fltr.Values, extendFilterPath(orPath, fltrID)); err != nil {
+					return false, err
+				}
+				if pass == negative {
+					return false, nil
+				}
+				continue
 			default:
 				return false, fmt.Errorf("tenant: %s filter: %s unsupported filter type: <%s>", tenant, fltrID, fltr.Type)
 			}
-			if !pass || err != nil {
+			if pass, err = fltr.Pass(ev, "", rpcClnt, tenant); !pass || err != nil {
 				return pass, err
 			}
 		}
@@ -153,6 +226,74 @@ func (fS *FilterS) PassFiltersForEvent(tenant string, ev map[string]interface{},
 	return atLeastOneFilterPassing, nil
 }
 
+// passOrFilters checks the filters referenced by a *or filter, at least one of them needs to pass
+func (fS *FilterS) passOrFilters(tenant string, ev map[string]interface{}, filterIDs, orPath []string) (pass bool, err error) {
+	for _, fltrID := range filterIDs {
+		if inFilterPath(orPath, fltrID) {
+			return false, fmt.Errorf("tenant: %s filter: %s referenced in cycle: %s",
+				tenant, fltrID, strings.Join(extendFilterPath(orPath, fltrID), utils.HIERARCHY_SEP))
+		}
+		if pass, err = fS.passFiltersForEvent(tenant, ev, []string{fltrID}, orPath); err != nil || pass {
+			return
+		}
+	}
+	return
+}
+
+// CheckOrFilterCycles makes sure fltrID is not part of a reference cycle built by *or filters
+// orFilterIDs returns the IDs referenced by one filter, nil if the filter is not found
+func CheckOrFilterCycles(fltrID string, orFilterIDs func(fltrID string) ([]string, error)) error {
+	return checkOrFilterCycles(fltrID, []string{fltrID}, orFilterIDs)
+}
+
+func checkOrFilterCycles(fltrID string, path []string, orFilterIDs func(fltrID string) ([]string, error)) error {
+	refIDs, err := orFilterIDs(fltrID)
+	if err != nil {
+		return err
+	}
+	for _, refID := range refIDs {
+		if inFilterPath(path, refID) {
+			return fmt.Errorf("filter: %s references itself over: %s",
+				refID, strings.Join(extendFilterPath(path, refID), utils.HIERARCHY_SEP))
+		}
+		if err = checkOrFilterCycles(refID, extendFilterPath(path, refID), orFilterIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inFilterPath checks for fltrID in path without reordering it like IsSliceMember does
+func inFilterPath(path []string, fltrID string) bool {
+	for _, pathID := range path {
+		if pathID == fltrID {
+			return true
+		}
+	}
+	return false
+}
+
+// extendFilterPath returns a copy of path with fltrID appended, so sibling branches never share the backing array
+func extendFilterPath(path []string, fltrID string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), fltrID)
+}
+
+// OrFilterIDs returns the filter IDs referenced by the *or filters
+// inline filters are not returned since they do not need to be stored
+func (flt *Filter) OrFilterIDs() (fltrIDs []string) {
+	for _, rf := range flt.RequestFilters {
+		if baseType, _ := filterBaseType(rf.Type); baseType != MetaOr {
+			continue
+		}
+		for _, fltrID := range rf.Values {
+			if !strings.HasPrefix(fltrID, utils.MetaPrefix) {
+				fltrIDs = append(fltrIDs, fltrID)
+			}
+		}
+	}
+	return
+}
+
 type Filter struct {
 	Tenant             string
 	ID                 string
@@ -174,17 +315,28 @@ func (f *Filter) Compile() (err error) {
 	return
 }
 
+// filterBaseType returns the filter type without negation
+// negative is true for types prefixed with *not, ie: *notstring
+func filterBaseType(rfType string) (baseType string, negative bool) {
+	if strings.HasPrefix(rfType, MetaNot) {
+		return utils.MetaPrefix + rfType[len(MetaNot):], true
+	}
+	return rfType, false
+}
+
 func NewRequestFilter(rfType, fieldName string, vals []string) (*RequestFilter, error) {
-	if !utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaTimings, MetaRSR, MetaStatS, MetaDestinations,
-		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+	baseType, _ := filterBaseType(rfType)
+	if !utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaStringSuffix, MetaRegexp, MetaTimings, MetaRSR, MetaStatS, MetaDestinations,
+		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual, MetaExists, MetaEmpty, MetaResources, MetaAccounts, MetaOr}, baseType) {
 		return nil, fmt.Errorf("Unsupported filter Type: %s", rfType)
 	}
-	if fieldName == "" && utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaTimings, MetaDestinations,
-		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+	if fieldName == "" && utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaStringSuffix, MetaRegexp, MetaTimings, MetaDestinations,
+		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual, MetaExists, MetaEmpty, MetaAccounts}, baseType) {
 		return nil, fmt.Errorf("FieldName is mandatory for Type: %s", rfType)
 	}
-	if len(vals) == 0 && utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaTimings, MetaRSR,
-		MetaDestinations, MetaDestinations, MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+	if len(vals) == 0 && utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaStringSuffix, MetaRegexp, MetaTimings, MetaRSR,
+		MetaDestinations, MetaDestinations, MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual,
+		MetaResources, MetaAccounts, MetaOr}, baseType) {
 		return nil, fmt.Errorf("Values is mandatory for Type: %s", rfType)
 	}
 	rf := &RequestFilter{Type: rfType, FieldName: fieldName, Values: vals}
@@ -200,23 +352,41 @@ type RFStatSThreshold struct {
 	ThresholdValue float64
 }
 
+// RFItemThreshold is a threshold checked against the live value of an item
+// ItemID is the ResourceID for *resources and the BalanceType for *accounts
+type RFItemThreshold struct {
+	ItemID         string
+	ThresholdType  string // *lt, *lte, *gt, *gte
+	ThresholdValue float64
+}
+
 // RequestFilter filters requests coming into various places
 // Pass rule: default negative, one mathing rule should pass the filter
 type RequestFilter struct {
-	Type            string              // Filter type (*string, *timing, *rsr_filters, *stats, *lt, *lte, *gt, *gte)
+	Type            string              // Filter type (*string, *string_prefix, *string_suffix, *regexp, *timing, *rsr_filters, *stats, *resources, *accounts, *lt, *lte, *gt, *gte, *exists, *empty, *or), *not prefix negates it
 	FieldName       string              // Name of the field providing us the Values to check (used in case of some )
 	Values          []string            // Filter definition
 	rsrFields       utils.RSRFields     // Cache here the RSRFilter Values
 	statSThresholds []*RFStatSThreshold // Cached compiled RFStatsThreshold out of Values
+	regexps         []*regexp.Regexp    // Cached compiled regular expressions out of Values
+	itemThresholds  []*RFItemThreshold  // Cached compiled RFItemThreshold out of Values
 }
 
 // Separate method to compile RSR fields
 func (rf *RequestFilter) CompileValues() (err error) {
-	if rf.Type == MetaRSR {
+	baseType, _ := filterBaseType(rf.Type)
+	if baseType == MetaRSR {
 		if rf.rsrFields, err = utils.ParseRSRFieldsFromSlice(rf.Values); err != nil {
 			return
 		}
-	} else if rf.Type == MetaStatS {
+	} else if baseType == MetaRegexp {
+		rf.regexps = make([]*regexp.Regexp, len(rf.Values))
+		for i, val := range rf.Values {
+			if rf.regexps[i], err = regexp.Compile(val); err != nil {
+				return fmt.Errorf("Value %s is not a valid regular expression: %s", val, err.Error())
+			}
+		}
+	} else if baseType == MetaStatS {
 		rf.statSThresholds = make([]*RFStatSThreshold, len(rf.Values))
 		for i, val := range rf.Values {
 			valSplt := strings.Split(val, utils.InInFieldSep)
@@ -236,30 +406,64 @@ func (rf *RequestFilter) CompileValues() (err error) {
 			}
 			rf.statSThresholds[i] = st
 		}
+	} else if baseType == MetaResources || baseType == MetaAccounts {
+		rf.itemThresholds = make([]*RFItemThreshold, len(rf.Values))
+		for i, val := range rf.Values {
+			valSplt := strings.Split(val, utils.InInFieldSep)
+			if len(valSplt) != 3 {
+				return fmt.Errorf("Value %s needs to contain at least 3 items", val)
+			}
+			if !utils.IsSliceMember([]string{MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, valSplt[1]) {
+				return fmt.Errorf("Value %s contains unsupported ThresholdType", val)
+			}
+			tv, err := strconv.ParseFloat(valSplt[2], 64)
+			if err != nil {
+				return err
+			}
+			rf.itemThresholds[i] = &RFItemThreshold{ItemID: valSplt[0], ThresholdType: valSplt[1], ThresholdValue: tv}
+		}
 	}
 	return
 }
 
 // Pass is the method which should be used from outside.
-func (fltr *RequestFilter) Pass(req interface{}, extraFieldsLabel string, rpcClnt rpcclient.RpcClientConnection) (bool, error) {
-	switch fltr.Type {
+// rpcClnt is the connection towards StatS/ResourceS for the filter types querying them
+func (fltr *RequestFilter) Pass(req interface{}, extraFieldsLabel string, rpcClnt rpcclient.RpcClientConnection, tenant string) (pass bool, err error) {
+	baseType, negative := filterBaseType(fltr.Type)
+	switch baseType {
 	case MetaString:
-		return fltr.passString(req, extraFieldsLabel)
+		pass, err = fltr.passString(req, extraFieldsLabel)
 	case MetaStringPrefix:
-		return fltr.passStringPrefix(req, extraFieldsLabel)
+		pass, err = fltr.passStringPrefix(req, extraFieldsLabel)
+	case MetaStringSuffix:
+		pass, err = fltr.passStringSuffix(req, extraFieldsLabel)
+	case MetaRegexp:
+		pass, err = fltr.passRegexp(req, extraFieldsLabel)
 	case MetaTimings:
-		return fltr.passTimings(req, extraFieldsLabel)
+		pass, err = fltr.passTimings(req, extraFieldsLabel)
 	case MetaDestinations:
-		return fltr.passDestinations(req, extraFieldsLabel)
+		pass, err = fltr.passDestinations(req, extraFieldsLabel)
 	case MetaRSR:
-		return fltr.passRSR(req, extraFieldsLabel)
+		pass, err = fltr.passRSR(req, extraFieldsLabel)
 	case MetaStatS:
-		return fltr.passStatS(req, extraFieldsLabel, rpcClnt)
+		pass, err = fltr.passStatS(req, extraFieldsLabel, rpcClnt)
+	case MetaResources:
+		pass, err = fltr.passResources(tenant, rpcClnt)
+	case MetaAccounts: // needs the DataManager, only evaluated inside FilterS
+		return false, fmt.Errorf("%s filter requires FilterS", MetaAccounts)
 	case MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual:
-		return fltr.passGreaterThan(req, extraFieldsLabel)
+		pass, err = fltr.passGreaterThan(req, extraFieldsLabel)
+	case MetaExists:
+		pass, err = fltr.passExists(req, extraFieldsLabel)
+	case MetaEmpty:
+		pass, err = fltr.passEmpty(req, extraFieldsLabel)
 	default:
 		return false, utils.ErrNotImplemented
 	}
+	if err != nil {
+		return false, err
+	}
+	return pass != negative, nil
 }
 
 func (fltr *RequestFilter) passString(req interface{}, extraFieldsLabel string) (bool, error) {
@@ -294,6 +498,39 @@ func (fltr *RequestFilter) passStringPrefix(req interface{}, extraFieldsLabel st
 	return false, nil
 }
 
+func (fltr *RequestFilter) passStringSuffix(req interface{}, extraFieldsLabel string) (bool, error) {
+	strVal, err := utils.ReflectFieldAsString(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, sfx := range fltr.Values {
+		if strings.HasSuffix(strVal, sfx) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// passRegexp matches the field value against the regular expressions compiled out of Values
+func (fltr *RequestFilter) passRegexp(req interface{}, extraFieldsLabel string) (bool, error) {
+	strVal, err := utils.ReflectFieldAsString(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, re := range fltr.regexps {
+		if re.MatchString(strVal) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ToDo when Timings will be available in DataDb
 func (fltr *RequestFilter) passTimings(req interface{}, extraFieldsLabel string) (bool, error) {
 	return false, utils.ErrNotImplemented
@@ -359,6 +596,105 @@ func (fltr *RequestFilter) passStatS(req interface{}, extraFieldsLabel string, s
 	return false, nil
 }
 
+// passResources checks the usage of the resources queried from ResourceS
+func (fltr *RequestFilter) passResources(tenant string, resources rpcclient.RpcClientConnection) (bool, error) {
+	if resources == nil || reflect.ValueOf(resources).IsNil() {
+		return false, errors.New("Missing ResourceS information")
+	}
+	for _, threshold := range fltr.itemThresholds {
+		var res Resource
+		if err := resources.Call(utils.ResourceSv1GetResource,
+			&utils.TenantID{Tenant: tenant, ID: threshold.ItemID}, &res); err != nil {
+			if err.Error() == utils.ErrNotFound.Error() {
+				continue
+			}
+			return false, err
+		}
+		if pass, err := passItemThreshold(res.totalUsage(), threshold); err != nil {
+			return false, err
+		} else if pass {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// passAccounts checks the balance values of the account found in req
+func (fltr *RequestFilter) passAccounts(req interface{}, extraFieldsLabel, tenant string, dm *DataManager) (bool, error) {
+	if dm == nil {
+		return false, errors.New("Missing DataManager information")
+	}
+	acntID, err := utils.ReflectFieldAsString(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	acnt, err := dm.DataDB().GetAccount(utils.ConcatenatedKey(tenant, acntID))
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, threshold := range fltr.itemThresholds {
+		if pass, err := passItemThreshold(acnt.BalanceMap[threshold.ItemID].GetTotalValue(), threshold); err != nil {
+			return false, err
+		} else if pass {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// passItemThreshold compares the live value of an item with the threshold
+func passItemThreshold(val float64, threshold *RFItemThreshold) (bool, error) {
+	switch threshold.ThresholdType {
+	case MetaLessThan:
+		return val < threshold.ThresholdValue, nil
+	case MetaLessOrEqual:
+		return val <= threshold.ThresholdValue, nil
+	case MetaGreaterThan:
+		return val > threshold.ThresholdValue, nil
+	case MetaGreaterOrEqual:
+		return val >= threshold.ThresholdValue, nil
+	}
+	return false, fmt.Errorf("unsupported ThresholdType: <%s>", threshold.ThresholdType)
+}
+
+// passExists checks the presence of the field in req
+func (fltr *RequestFilter) passExists(req interface{}, extraFieldsLabel string) (bool, error) {
+	if _, err := utils.ReflectFieldInterface(req, fltr.FieldName, extraFieldsLabel); err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// passEmpty checks that the field is present in req and it's value is empty
+func (fltr *RequestFilter) passEmpty(req interface{}, extraFieldsLabel string) (bool, error) {
+	fldIf, err := utils.ReflectFieldInterface(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	if fldIf == nil {
+		return true, nil
+	}
+	switch rv := reflect.ValueOf(fldIf); rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0, nil
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil(), nil
+	}
+	return false, nil
+}
+
 func (fltr *RequestFilter) passGreaterThan(req interface{}, extraFieldsLabel string) (bool, error) {
 	fldIf, err := utils.ReflectFieldInterface(req, fltr.FieldName, extraFieldsLabel)
 	if err != nil {
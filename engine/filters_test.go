@@ -320,3 +320,468 @@ func TestInlineFilterPassFiltersForEvent(t *testing.T) {
 	}
 
 }
+
+func TestReqFilterPassExistsEmpty(t *testing.T) {
+	ev := map[string]interface{}{
+		"Account":     "1001",
+		"Subject":     "",
+		"Destination": nil,
+	}
+	rf := &RequestFilter{Type: MetaExists, FieldName: "Account"}
+	if passes, err := rf.passExists(ev, ""); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	rf = &RequestFilter{Type: MetaExists, FieldName: "Category"}
+	if passes, err := rf.passExists(ev, ""); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+	rf = &RequestFilter{Type: MetaEmpty, FieldName: "Subject"}
+	if passes, err := rf.passEmpty(ev, ""); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	rf = &RequestFilter{Type: MetaEmpty, FieldName: "Destination"}
+	if passes, err := rf.passEmpty(ev, ""); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	rf = &RequestFilter{Type: MetaEmpty, FieldName: "Account"}
+	if passes, err := rf.passEmpty(ev, ""); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+	rf = &RequestFilter{Type: MetaEmpty, FieldName: "Category"}
+	if passes, err := rf.passEmpty(ev, ""); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+}
+
+func TestReqFilterPassNegative(t *testing.T) {
+	ev := map[string]interface{}{
+		"Account": "1001",
+	}
+	rf, err := NewRequestFilter("*notstring", "Account", []string{"1002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	rf = &RequestFilter{Type: "*notstring", FieldName: "Account", Values: []string{"1001"}}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+	rf = &RequestFilter{Type: "*notexists", FieldName: "Category"}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	if _, err := NewRequestFilter("*notsupported", "Account", []string{"1001"}); err == nil {
+		t.Error("Expecting error")
+	}
+	if _, err := NewRequestFilter(MetaExists, "", nil); err == nil {
+		t.Error("Expecting error")
+	}
+}
+
+func TestInlineFilterPassFiltersForEventNegative(t *testing.T) {
+	data, _ := NewMapStorage()
+	dmFilterPass := NewDataManager(data)
+	cfg, _ := config.NewDefaultCGRConfig()
+	filterS := FilterS{
+		cfg: cfg,
+		dm:  dmFilterPass,
+	}
+	ev := map[string]interface{}{
+		"Account": "1007",
+		"Subject": "",
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{"*notstring:Account:1007"}); err != nil {
+		t.Errorf(err.Error())
+	} else if pass {
+		t.Errorf("Expecting: %+v, received: %+v", false, pass)
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{"*notstring_prefix:Account:20"}); err != nil {
+		t.Errorf(err.Error())
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{"*exists:Account", "*empty:Subject:"}); err != nil {
+		t.Errorf(err.Error())
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{"*notexists:Account"}); err != nil {
+		t.Errorf(err.Error())
+	} else if pass {
+		t.Errorf("Expecting: %+v, received: %+v", false, pass)
+	}
+	if _, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{"*string:Account"}); err == nil {
+		t.Error("Expecting error")
+	}
+}
+
+func TestReqFilterPassStringSuffix(t *testing.T) {
+	ev := map[string]interface{}{
+		"Account":     "1001@cgrates.org",
+		"Destination": "+4986517174963",
+	}
+	rf := &RequestFilter{Type: MetaStringSuffix, FieldName: "Account", Values: []string{"@itsyscom.com", "@cgrates.org"}}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	rf = &RequestFilter{Type: MetaStringSuffix, FieldName: "Destination", Values: []string{"4964"}}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+	rf = &RequestFilter{Type: MetaStringSuffix, FieldName: "nonexisting", Values: []string{"63"}}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+}
+
+func TestReqFilterPassRegexp(t *testing.T) {
+	ev := map[string]interface{}{
+		"Account":     "1001",
+		"Destination": "+4986517174963",
+	}
+	rf, err := NewRequestFilter(MetaRegexp, "Destination", []string{`^\+49\d+$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	if rf, err = NewRequestFilter("*notregexp", "Account", []string{"^10[0-9]{2}$"}); err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil, "cgrates.org"); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+	if _, err := NewRequestFilter(MetaRegexp, "Account", []string{"^10(01"}); err == nil {
+		t.Error("Expecting error")
+	}
+	if _, err := NewRequestFilter(MetaRegexp, "Account", nil); err == nil {
+		t.Error("Expecting error")
+	}
+}
+
+func TestInlineFilterPassFiltersForEventRegexp(t *testing.T) {
+	data, _ := NewMapStorage()
+	dmFilterPass := NewDataManager(data)
+	cfg, _ := config.NewDefaultCGRConfig()
+	filterS := FilterS{
+		cfg: cfg,
+		dm:  dmFilterPass,
+	}
+	ev := map[string]interface{}{
+		"Account":   "1001",
+		"SetupTime": "2018-01-07T17:00:10Z",
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{`*regexp:SetupTime:^2018-01-07T17:00:\d{2}Z$`}); err != nil {
+		t.Errorf(err.Error())
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{"*string_suffix:Account:01"}); err != nil {
+		t.Errorf(err.Error())
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	if _, err := filterS.PassFiltersForEvent("cgrates.org",
+		ev, []string{"*regexp:Account:^10(01"}); err == nil {
+		t.Error("Expecting error")
+	}
+}
+
+func TestIndexableFilterValues(t *testing.T) {
+	if rcv := indexableFilterValues(MetaString, []string{"1001", "1002"}); !reflect.DeepEqual([]string{"1001", "1002"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues(MetaRegexp, []string{"^1001$", "^(1002|dan)$"}); !reflect.DeepEqual([]string{"1001", "1002", "dan"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues(MetaRegexp, []string{"^1001$", "^10.*$"}); rcv != nil {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues(MetaRegexp, []string{"1001"}); rcv != nil {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues(MetaRegexp, []string{"^(1001|1002)$", "^(dan|danb)$"}); !reflect.DeepEqual([]string{"1001", "1002", "dan", "danb"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues(MetaRegexp, []string{"^49(1|20)$"}); !reflect.DeepEqual([]string{"491", "4920"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues(MetaRegexp, []string{"^100[0-9]+$"}); rcv != nil {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues(MetaRegexp, []string{"^[a-z][a-z]$"}); rcv != nil { // over regexpMaxLiterals
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv := indexableFilterValues("*notstring", []string{"1001"}); rcv != nil {
+		t.Errorf("Received: %+v", rcv)
+	}
+}
+
+func TestReqFilterPassAccounts(t *testing.T) {
+	acnt := &Account{ID: "cgrates.org:fltrAcnt1",
+		BalanceMap: map[string]Balances{utils.MONETARY: Balances{&Balance{Value: 7}, &Balance{Value: 5}}}}
+	if err := dm.DataDB().SetAccount(acnt); err != nil {
+		t.Fatal(err)
+	}
+	ev := map[string]interface{}{
+		utils.Account: "fltrAcnt1",
+	}
+	rf, err := NewRequestFilter(MetaAccounts, utils.Account, []string{"*monetary:*gte:12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.passAccounts(ev, "", "cgrates.org", dm); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passes filter")
+	}
+	if rf, err = NewRequestFilter(MetaAccounts, utils.Account, []string{"*monetary:*lt:10", "*voice:*gt:0"}); err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.passAccounts(ev, "", "cgrates.org", dm); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+	ev[utils.Account] = "nonexistent"
+	if passes, err := rf.passAccounts(ev, "", "cgrates.org", dm); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passes filter")
+	}
+	if _, err := rf.Pass(ev, "", nil, "cgrates.org"); err == nil ||
+		err.Error() != "*accounts filter requires FilterS" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	filterS := FilterS{dm: dm}
+	ev[utils.Account] = "fltrAcnt1"
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org", ev,
+		[]string{"*accounts:Account:*monetary:*gte:12"}); err != nil {
+		t.Error(err)
+	} else if !pass {
+		t.Error("Not passing")
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org", ev,
+		[]string{"*notaccounts:Account:*monetary:*gte:12"}); err != nil {
+		t.Error(err)
+	} else if pass {
+		t.Error("Passing")
+	}
+	if _, err := NewRequestFilter(MetaAccounts, utils.Account, []string{"*monetary:*min:10"}); err == nil {
+		t.Error("Expecting error")
+	}
+	if _, err := NewRequestFilter(MetaAccounts, "", []string{"*monetary:*lt:10"}); err == nil {
+		t.Error("Expecting error")
+	}
+}
+
+func TestReqFilterPassResources(t *testing.T) {
+	rf, err := NewRequestFilter(MetaResources, "", []string{"ResGroup1:*lt:10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eThds := []*RFItemThreshold{&RFItemThreshold{ItemID: "ResGroup1", ThresholdType: MetaLessThan, ThresholdValue: 10}}
+	if !reflect.DeepEqual(eThds, rf.itemThresholds) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eThds), utils.ToJSON(rf.itemThresholds))
+	}
+	if _, err := rf.Pass(map[string]interface{}{}, "", nil, "cgrates.org"); err == nil {
+		t.Error("Expecting error for missing ResourceS")
+	}
+	if _, err := NewRequestFilter(MetaResources, "", []string{"ResGroup1:*lt"}); err == nil {
+		t.Error("Expecting error")
+	}
+	inFltr, err := NewInlineFilter("*resources:ResGroup1:*lt:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eInFltr := &InlineFilter{Type: MetaResources, FieldVal: "ResGroup1:*lt:10"}
+	if !reflect.DeepEqual(eInFltr, inFltr) {
+		t.Errorf("Expecting: %+v, received: %+v", eInFltr, inFltr)
+	}
+	if inFltr, err = NewInlineFilter("*accounts:Account:*monetary:*gte:10"); err != nil {
+		t.Fatal(err)
+	}
+	eInFltr = &InlineFilter{Type: MetaAccounts, FieldName: "Account", FieldVal: "*monetary:*gte:10"}
+	if !reflect.DeepEqual(eInFltr, inFltr) {
+		t.Errorf("Expecting: %+v, received: %+v", eInFltr, inFltr)
+	}
+}
+
+func TestPassItemThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		thType string
+		val    float64
+		pass   bool
+	}{
+		{MetaLessThan, 10, false},
+		{MetaLessOrEqual, 10, true},
+		{MetaGreaterThan, 10, false},
+		{MetaGreaterOrEqual, 10, true},
+		{MetaLessThan, 9, true},
+		{MetaGreaterThan, 11, true},
+	} {
+		if pass, err := passItemThreshold(tc.val,
+			&RFItemThreshold{ThresholdType: tc.thType, ThresholdValue: 10}); err != nil {
+			t.Error(err)
+		} else if pass != tc.pass {
+			t.Errorf("%s %v expecting: %v, received: %v", tc.thType, tc.val, tc.pass, pass)
+		}
+	}
+}
+
+func TestPassFiltersForEventOr(t *testing.T) {
+	data, _ := NewMapStorage()
+	dmFilterPass := NewDataManager(data)
+	cfg, _ := config.NewDefaultCGRConfig()
+	filterS := FilterS{
+		cfg: cfg,
+		dm:  dmFilterPass,
+	}
+	fltrEU := &Filter{Tenant: "cgrates.org", ID: "FLTR_DST_EU",
+		RequestFilters: []*RequestFilter{
+			&RequestFilter{Type: MetaStringPrefix, FieldName: utils.Destination, Values: []string{"+49", "+40"}}}}
+	fltrPremium := &Filter{Tenant: "cgrates.org", ID: "FLTR_ACNT_PREMIUM",
+		RequestFilters: []*RequestFilter{
+			&RequestFilter{Type: MetaString, FieldName: utils.Account, Values: []string{"1001"}}}}
+	fltrOr := &Filter{Tenant: "cgrates.org", ID: "FLTR_EU_OR_PREMIUM",
+		RequestFilters: []*RequestFilter{
+			&RequestFilter{Type: MetaOr, Values: []string{"FLTR_DST_EU", "FLTR_ACNT_PREMIUM"}}}}
+	for _, fltr := range []*Filter{fltrEU, fltrPremium, fltrOr} {
+		if err := dmFilterPass.SetFilter(fltr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rcv := fltrOr.OrFilterIDs(); !reflect.DeepEqual([]string{"FLTR_DST_EU", "FLTR_ACNT_PREMIUM"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	ev := map[string]interface{}{
+		utils.Account:     "1002",
+		utils.Destination: "+4986517174963",
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org", ev, []string{"FLTR_EU_OR_PREMIUM"}); err != nil {
+		t.Error(err)
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	ev[utils.Destination] = "+3312345"
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org", ev, []string{"FLTR_EU_OR_PREMIUM"}); err != nil {
+		t.Error(err)
+	} else if pass {
+		t.Errorf("Expecting: %+v, received: %+v", false, pass)
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org", ev, []string{"*or:FLTR_DST_EU|*string:Account:1002"}); err != nil {
+		t.Error(err)
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	if pass, err := filterS.PassFiltersForEvent("cgrates.org", ev, []string{"*notor:FLTR_DST_EU|FLTR_ACNT_PREMIUM"}); err != nil {
+		t.Error(err)
+	} else if !pass {
+		t.Errorf("Expecting: %+v, received: %+v", true, pass)
+	}
+	if _, err := filterS.PassFiltersForEvent("cgrates.org", ev, []string{"*or:FLTR_NOT_EXISTING"}); err == nil {
+		t.Error("Expecting error")
+	}
+	fltrCycleA := &Filter{Tenant: "cgrates.org", ID: "FLTR_CYCLE_A",
+		RequestFilters: []*RequestFilter{
+			&RequestFilter{Type: MetaOr, Values: []string{"FLTR_DST_EU", "FLTR_CYCLE_B"}}}}
+	fltrCycleB := &Filter{Tenant: "cgrates.org", ID: "FLTR_CYCLE_B",
+		RequestFilters: []*RequestFilter{
+			&RequestFilter{Type: MetaOr, Values: []string{"FLTR_CYCLE_A"}}}}
+	for _, fltr := range []*Filter{fltrCycleA, fltrCycleB} {
+		if err := dmFilterPass.SetFilter(fltr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := filterS.PassFiltersForEvent("cgrates.org", ev, []string{"FLTR_CYCLE_A"}); err == nil ||
+		err.Error() != "tenant: cgrates.org filter: FLTR_CYCLE_A referenced in cycle: FLTR_CYCLE_A>FLTR_CYCLE_B>FLTR_CYCLE_A" {
+		t.Errorf("Received: %v", err)
+	}
+}
+
+func TestCheckOrFilterCycles(t *testing.T) {
+	graph := map[string][]string{
+		"FLTR_1": []string{"FLTR_2", "FLTR_3"},
+		"FLTR_2": []string{"FLTR_3"},
+		"FLTR_3": nil,
+	}
+	orFilterIDs := func(fltrID string) ([]string, error) {
+		return graph[fltrID], nil
+	}
+	if err := CheckOrFilterCycles("FLTR_1", orFilterIDs); err != nil {
+		t.Error(err)
+	}
+	graph["FLTR_3"] = []string{"FLTR_1"}
+	if err := CheckOrFilterCycles("FLTR_1", orFilterIDs); err == nil ||
+		err.Error() != "filter: FLTR_1 references itself over: FLTR_1>FLTR_2>FLTR_3>FLTR_1" {
+		t.Errorf("Received: %v", err)
+	}
+	graph["FLTR_3"] = []string{"FLTR_3"}
+	if err := CheckOrFilterCycles("FLTR_1", orFilterIDs); err == nil {
+		t.Error("Expecting error")
+	}
+	// path is reported in reference order, not sorted
+	graph = map[string][]string{
+		"FLTR_C": []string{"FLTR_B"},
+		"FLTR_B": []string{"FLTR_A"},
+		"FLTR_A": []string{"FLTR_C"},
+	}
+	if err := CheckOrFilterCycles("FLTR_C", orFilterIDs); err == nil ||
+		err.Error() != "filter: FLTR_C references itself over: FLTR_C>FLTR_B>FLTR_A>FLTR_C" {
+		t.Errorf("Received: %v", err)
+	}
+}
+
+func TestExtendFilterPath(t *testing.T) {
+	path := make([]string, 1, 4)
+	path[0] = "FLTR_1"
+	pathA := extendFilterPath(path, "FLTR_A")
+	pathB := extendFilterPath(path, "FLTR_B")
+	if !reflect.DeepEqual([]string{"FLTR_1", "FLTR_A"}, pathA) {
+		t.Errorf("Received: %+v", pathA)
+	}
+	if !reflect.DeepEqual([]string{"FLTR_1", "FLTR_B"}, pathB) {
+		t.Errorf("Received: %+v", pathB)
+	}
+	if !inFilterPath(pathB, "FLTR_1") || inFilterPath(pathB, "FLTR_A") {
+		t.Errorf("Wrong membership for: %+v", pathB)
+	}
+}
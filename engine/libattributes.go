@@ -19,16 +19,134 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
+	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
 type Attribute struct {
 	FieldName  string
 	Initial    string
-	Substitute string
+	Type       string // how Substitute is interpreted: empty or *constant for static values, *variable, *usage_difference or *sum for dynamic ones
+	Substitute string // static value, RSR template (ie: ~Account:s/^(\d+)$/+49$1/) or formula arguments (ie: ~Cost;^0.1)
 	Append     bool
+	substitute *attrSubstitute // compiled out of Substitute in case of dynamic values
+}
+
+// Compile parses the Substitute based on Type, populating the dynamic substitute if needed
+func (attr *Attribute) Compile() (err error) {
+	attr.substitute, err = newAttrSubstitute(attr.Type, attr.Substitute)
+	return
+}
+
+// SubstituteValue returns the value to be set in the event, processing dynamic substitutes out of ev
+func (attr *Attribute) SubstituteValue(ev map[string]interface{}) (string, error) {
+	if attr.substitute == nil { // static value
+		return attr.Substitute, nil
+	}
+	return attr.substitute.valueForEvent(ev)
+}
+
+// newAttrSubstitute parses a Substitute string of the given type,
+// returns nil for static values so they can be used as they are
+func newAttrSubstitute(sbstType, sbstStr string) (sbst *attrSubstitute, err error) {
+	switch sbstType {
+	case "", utils.META_CONSTANT:
+		return
+	case utils.MetaVariable, utils.MetaUsageDifference, utils.MetaSum:
+	default:
+		return nil, fmt.Errorf("unsupported substitute type: <%s>", sbstType)
+	}
+	sbst = &attrSubstitute{formula: sbstType}
+	if sbst.rsrFlds, err = utils.ParseRSRFields(sbstStr, utils.INFIELD_SEP); err != nil {
+		return nil, err
+	}
+	if sbstType == utils.MetaUsageDifference && len(sbst.rsrFlds) != 2 {
+		return nil, fmt.Errorf("%s expects 2 arguments, received: <%s>", sbstType, sbstStr)
+	} else if len(sbst.rsrFlds) == 0 {
+		return nil, fmt.Errorf("%s expects at least one argument", sbstType)
+	}
+	return
+}
+
+// attrSubstitute is the compiled form of a dynamic Substitute
+type attrSubstitute struct {
+	formula string          // *variable for concatenating rsrFlds, *usage_difference or *sum
+	rsrFlds utils.RSRFields // template parts or formula arguments
+}
+
+// valueForEvent computes the substitute value based on the fields in ev
+func (sbst *attrSubstitute) valueForEvent(ev map[string]interface{}) (val string, err error) {
+	switch sbst.formula {
+	case utils.MetaUsageDifference:
+		var tEnd, tStart time.Time
+		if tEnd, err = sbst.timeForEvent(sbst.rsrFlds[0], ev); err != nil {
+			return
+		}
+		if tStart, err = sbst.timeForEvent(sbst.rsrFlds[1], ev); err != nil {
+			return
+		}
+		return tEnd.Sub(tStart).String(), nil
+	case utils.MetaSum:
+		var sum float64
+		for _, rsrFld := range sbst.rsrFlds {
+			var strVal string
+			if strVal, err = rsrFieldValueForEvent(rsrFld, ev); err != nil {
+				return
+			}
+			fltVal, err := strconv.ParseFloat(strVal, 64)
+			if err != nil {
+				return "", fmt.Errorf("cannot convert <%s> to float64 for %s", strVal, utils.MetaSum)
+			}
+			sum += fltVal
+		}
+		return strconv.FormatFloat(sum, 'f', -1, 64), nil
+	}
+	for _, rsrFld := range sbst.rsrFlds { // concatenate the template parts
+		var strVal string
+		if strVal, err = rsrFieldValueForEvent(rsrFld, ev); err != nil {
+			return
+		}
+		val += strVal
+	}
+	return
+}
+
+// timeForEvent parses the value of rsrFld within ev as time
+func (sbst *attrSubstitute) timeForEvent(rsrFld *utils.RSRField, ev map[string]interface{}) (time.Time, error) {
+	if !rsrFld.IsStatic() && len(rsrFld.RSRules) == 0 {
+		if t, canCast := ev[rsrFld.Id].(time.Time); canCast { // no need of string conversion
+			return t, nil
+		}
+	}
+	strVal, err := rsrFieldValueForEvent(rsrFld, ev)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return utils.ParseTimeDetectLayout(strVal, config.CgrConfig().DefaultTimezone)
+}
+
+// rsrFieldValueForEvent returns the value of rsrFld parsed out of ev
+func rsrFieldValueForEvent(rsrFld *utils.RSRField, ev map[string]interface{}) (string, error) {
+	if rsrFld.IsStatic() {
+		return rsrFld.ParseValue(""), nil
+	}
+	valIf, has := ev[rsrFld.Id]
+	if !has {
+		return "", utils.NewErrMandatoryIeMissing(rsrFld.Id)
+	}
+	strVal, canCast := utils.CastFieldIfToString(valIf)
+	if !canCast {
+		return "", fmt.Errorf("cannot cast field: %s to string", rsrFld.Id)
+	}
+	if !rsrFld.FilterPasses(strVal) {
+		return "", fmt.Errorf("filters not passing for field: %s", rsrFld.Id)
+	}
+	return rsrFld.ParseValue(strVal), nil
 }
 
 type AttributeProfile struct {
@@ -45,6 +163,18 @@ func (als *AttributeProfile) TenantID() string {
 	return utils.ConcatenatedKey(als.Tenant, als.ID)
 }
 
+// Compile compiles the dynamic substitutes of the attributes
+func (als *AttributeProfile) Compile() (err error) {
+	for _, initialMp := range als.Attributes {
+		for _, attr := range initialMp {
+			if err = attr.Compile(); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
 // AttributeProfiles is a sortable list of Attribute profiles
 type AttributeProfiles []*AttributeProfile
 
@@ -95,6 +225,7 @@ func NewExternalAttributeProfileFromAttributeProfile(alsPrf *AttributeProfile) *
 			extals.Attributes = append(extals.Attributes, &Attribute{
 				FieldName:  key,
 				Initial:    key2,
+				Type:       val2.Type,
 				Substitute: val2.Substitute,
 				Append:     val2.Append,
 			})
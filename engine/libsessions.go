@@ -0,0 +1,42 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+)
+
+// StoredSession is the dataDB representation of an active session, grouping all its runs
+type StoredSession struct {
+	CGRID string
+	Runs  []*StoredSessionRun
+}
+
+// StoredSessionRun keeps the charging state of one run out of an active session
+type StoredSessionRun struct {
+	RunID         string
+	Timezone      string
+	EventStart    map[string]interface{} // Event which started the session
+	CD            *CallDescriptor        // CallDescriptor used for debits, updated on each debit
+	EventCost     *EventCost
+	ExtraDuration time.Duration // Duration debited on top of what has been asked
+	LastUsage     time.Duration // Last requested Duration
+	LastDebit     time.Duration // Last real debited duration
+	TotalUsage    time.Duration // Sum of LastUsage
+}
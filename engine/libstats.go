@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/cgrates/cgrates/utils"
 	"sort"
+	"strconv"
 	"time"
 )
 
@@ -39,6 +40,9 @@ type StatQueueProfile struct {
 	Stored             bool
 	Weight             float64
 	MinItems           int
+	// BucketInterval groups the events in time buckets of this length, dropped together once out of TTL, 0 to track each event
+	// percentile metrics keep the raw values of up to statBucketMaxValues events per bucket (8 bytes each), sampled above that
+	BucketInterval time.Duration
 }
 
 func (sqp *StatQueueProfile) TenantID() string {
@@ -55,6 +59,7 @@ func NewStoredStatQueue(sq *StatQueue, ms Marshaler) (sSQ *StoredStatQueue, err
 			ExpiryTime *time.Time
 		}, len(sq.SQItems)),
 		SQMetrics: make(map[string][]byte, len(sq.SQMetrics)),
+		SQBuckets: sq.SQBuckets,
 		MinItems:  sq.MinItems,
 	}
 	for i, sqItm := range sq.SQItems {
@@ -79,6 +84,7 @@ type StoredStatQueue struct {
 		ExpiryTime *time.Time // Used to auto-expire events
 	}
 	SQMetrics map[string][]byte
+	SQBuckets []*StatBucket
 	MinItems  int
 }
 
@@ -97,13 +103,14 @@ func (ssq *StoredStatQueue) AsStatQueue(ms Marshaler) (sq *StatQueue, err error)
 			ExpiryTime *time.Time
 		}, len(ssq.SQItems)),
 		SQMetrics: make(map[string]StatMetric, len(ssq.SQMetrics)),
+		SQBuckets: ssq.SQBuckets,
 		MinItems:  ssq.MinItems,
 	}
 	for i, sqItm := range ssq.SQItems {
 		sq.SQItems[i] = sqItm
 	}
 	for metricID, marshaled := range ssq.SQMetrics {
-		if metric, err := NewStatMetric(metricID, ssq.MinItems, ""); err != nil {
+		if metric, err := newStoredStatMetric(metricID, ssq.MinItems); err != nil {
 			return nil, err
 		} else if err := metric.LoadMarshaled(ms, marshaled); err != nil {
 			return nil, err
@@ -114,6 +121,17 @@ func (ssq *StoredStatQueue) AsStatQueue(ms Marshaler) (sq *StatQueue, err error)
 	return
 }
 
+// StatBucket groups the events received within one BucketInterval
+// the events are aggregated by the metrics, only the bucket is tracked here
+type StatBucket struct {
+	StartTime time.Time
+}
+
+// ID identifies the bucket within metrics
+func (bkt *StatBucket) ID() string {
+	return strconv.FormatInt(bkt.StartTime.UnixNano(), 10)
+}
+
 // StatQueue represents an individual stats instance
 type StatQueue struct {
 	Tenant  string
@@ -123,6 +141,7 @@ type StatQueue struct {
 		ExpiryTime *time.Time // Used to auto-expire events
 	}
 	SQMetrics map[string]StatMetric
+	SQBuckets []*StatBucket // used instead of SQItems when the profile has BucketInterval
 	MinItems  int
 	sqPrfl    *StatQueueProfile
 	dirty     *bool          // needs save
@@ -136,12 +155,81 @@ func (sq *StatQueue) TenantID() string {
 
 // ProcessEvent processes a utils.CGREvent, returns true if processed
 func (sq *StatQueue) ProcessEvent(ev *utils.CGREvent) (err error) {
+	if sq.sqPrfl != nil && sq.sqPrfl.BucketInterval > 0 {
+		now := time.Now()
+		sq.remExpiredBuckets(now)
+		sq.remOnBucketsLength(now)
+		sq.addBucketEvent(ev, now)
+		return
+	}
 	sq.remExpired()
 	sq.remOnQueueLength()
 	sq.addStatEvent(ev)
 	return
 }
 
+// remExpiredBuckets drops the buckets ended before the TTL window, returns the number of buckets removed
+func (sq *StatQueue) remExpiredBuckets(now time.Time) (expIdx int) {
+	if sq.ttl == nil || *sq.ttl <= 0 {
+		return
+	}
+	windowStart := now.Add(-*sq.ttl)
+	for _, bkt := range sq.SQBuckets {
+		if bkt.StartTime.Add(sq.sqPrfl.BucketInterval).After(windowStart) {
+			break
+		}
+		expIdx++
+	}
+	sq.remBuckets(expIdx)
+	return
+}
+
+// remOnBucketsLength drops the oldest buckets exceeding QueueLength, which limits the number of buckets
+// makes room for the bucket opened by an event received at now
+func (sq *StatQueue) remOnBucketsLength(now time.Time) {
+	if sq.sqPrfl.QueueLength <= 0 {
+		return
+	}
+	nrBkts := len(sq.SQBuckets)
+	if nrBkts == 0 ||
+		!sq.SQBuckets[nrBkts-1].StartTime.Equal(now.Truncate(sq.sqPrfl.BucketInterval)) {
+		nrBkts++ // the new event will open a bucket
+	}
+	if nrBkts > sq.sqPrfl.QueueLength {
+		sq.remBuckets(nrBkts - sq.sqPrfl.QueueLength)
+	}
+}
+
+// remBuckets removes the first nrBkts buckets out of queue and metrics
+func (sq *StatQueue) remBuckets(nrBkts int) {
+	for _, bkt := range sq.SQBuckets[:nrBkts] {
+		for metricID, metric := range sq.SQMetrics {
+			if err := metric.RemBucket(bkt.ID()); err != nil &&
+				err != utils.ErrNotFound { // metrics not interested in any event of the bucket do not know it
+				utils.Logger.Warning(fmt.Sprintf("<StatQueue> metricID: %s, remove bucket: %s, error: %s",
+					metricID, bkt.ID(), err.Error()))
+			}
+		}
+	}
+	sq.SQBuckets = sq.SQBuckets[nrBkts:]
+}
+
+// addBucketEvent adds the event to the metrics within the bucket containing now, opening a new bucket if needed
+func (sq *StatQueue) addBucketEvent(ev *utils.CGREvent, now time.Time) {
+	bktStart := now.Truncate(sq.sqPrfl.BucketInterval)
+	if len(sq.SQBuckets) == 0 ||
+		!sq.SQBuckets[len(sq.SQBuckets)-1].StartTime.Equal(bktStart) {
+		sq.SQBuckets = append(sq.SQBuckets, &StatBucket{StartTime: bktStart})
+	}
+	bktID := sq.SQBuckets[len(sq.SQBuckets)-1].ID()
+	for metricID, metric := range sq.SQMetrics {
+		if err := metric.AddBucketEvent(ev, bktID); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<StatQueue> metricID: %s, add eventID: %s to bucket: %s, error: %s",
+				metricID, ev.TenantID(), bktID, err.Error()))
+		}
+	}
+}
+
 // remStatEvent removes an event from metrics
 func (sq *StatQueue) remEventWithID(evTenantID string) {
 	for metricID, metric := range sq.SQMetrics {
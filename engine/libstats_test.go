@@ -19,6 +19,7 @@ package engine
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
@@ -205,3 +206,70 @@ func TestStatAddStatEvent(t *testing.T) {
 		t.Errorf("ASR: %v", asrMetric)
 	}
 }
+
+func TestStatBuckets(t *testing.T) {
+	asrMetric, _ := NewASR(0, "")
+	maxMetric, _ := NewStatMax(0, "")
+	bsq := &StatQueue{
+		sqPrfl: &StatQueueProfile{
+			BucketInterval: time.Minute,
+			QueueLength:    3,
+		},
+		ttl:       utils.DurationPointer(5 * time.Minute),
+		SQMetrics: map[string]StatMetric{utils.MetaASR: asrMetric, utils.MetaMax: maxMetric},
+	}
+	now := time.Date(2018, 1, 7, 16, 0, 10, 0, time.UTC)
+	for i, evTime := range []time.Time{now, now.Add(20 * time.Second), now.Add(time.Minute)} {
+		bsq.addBucketEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "TestStatBuckets_" + strconv.Itoa(i+1),
+			Event: map[string]interface{}{
+				utils.AnswerTime: evTime,
+				utils.Usage:      time.Duration(i+1) * time.Second}}, evTime)
+	}
+	if len(bsq.SQBuckets) != 2 {
+		t.Fatalf("unexpected buckets: %s", utils.ToJSON(bsq.SQBuckets))
+	} else if !bsq.SQBuckets[1].StartTime.Equal(time.Date(2018, 1, 7, 16, 1, 0, 0, time.UTC)) {
+		t.Errorf("unexpected bucket: %s", utils.ToJSON(bsq.SQBuckets[1]))
+	}
+	eBkts := map[string]*StatBucketValue{
+		bsq.SQBuckets[0].ID(): &StatBucketValue{Count: 2, Sum: 2},
+		bsq.SQBuckets[1].ID(): &StatBucketValue{Count: 1, Sum: 1},
+	}
+	if asr := asrMetric.(*StatASR); len(asr.Events) != 0 {
+		t.Errorf("unexpected events in metric: %+v", asr.Events)
+	} else if !reflect.DeepEqual(eBkts, asr.Buckets) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eBkts), utils.ToJSON(asr.Buckets))
+	} else if asr.Count != 3 {
+		t.Errorf("unexpected count: %v", asr.Count)
+	}
+	if val := maxMetric.GetFloat64Value(); val != 3 {
+		t.Errorf("unexpected max: %v", val)
+	} else if bkt := maxMetric.(*StatMax).Buckets[bsq.SQBuckets[0].ID()]; !reflect.DeepEqual([]float64{2}, bkt.Values) {
+		t.Errorf("bucket values not reduced: %+v", bkt)
+	}
+	if nrRem := bsq.remExpiredBuckets(now.Add(2 * time.Minute)); nrRem != 0 { // within TTL
+		t.Errorf("unexpected buckets removed: %d", nrRem)
+	}
+	bsq.remOnBucketsLength(now.Add(time.Minute)) // within QueueLength, same bucket
+	if len(bsq.SQBuckets) != 2 {
+		t.Errorf("unexpected buckets: %s", utils.ToJSON(bsq.SQBuckets))
+	}
+	bsq.remOnBucketsLength(now.Add(2 * time.Minute))
+	bsq.addBucketEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "TestStatBuckets_4"}, now.Add(2*time.Minute))
+	bsq.remOnBucketsLength(now.Add(3 * time.Minute)) // QueueLength reached
+	if len(bsq.SQBuckets) != 2 {
+		t.Errorf("unexpected buckets: %s", utils.ToJSON(bsq.SQBuckets))
+	} else if asr := asrMetric.(*StatASR); len(asr.Buckets) != 2 || asr.Count != 2 || asr.Answered != 1 {
+		t.Errorf("unexpected metric: %s", utils.ToJSON(asr))
+	} else if val := maxMetric.GetFloat64Value(); val != 3 {
+		t.Errorf("unexpected max: %v", val)
+	}
+	if nrRem := bsq.remExpiredBuckets(now.Add(9 * time.Minute)); nrRem != 2 { // out of TTL
+		t.Errorf("unexpected buckets removed: %d", nrRem)
+	} else if len(bsq.SQBuckets) != 0 {
+		t.Errorf("unexpected buckets: %s", utils.ToJSON(bsq.SQBuckets))
+	} else if asr := asrMetric.(*StatASR); len(asr.Buckets) != 0 || asr.Count != 0 {
+		t.Errorf("unexpected metric: %s", utils.ToJSON(asr))
+	} else if val := maxMetric.GetFloat64Value(); val != STATS_NA {
+		t.Errorf("unexpected max: %v", val)
+	}
+}
@@ -21,6 +21,7 @@ package engine
 import (
 	"fmt"
 	"sort"
+	"strings"
 	// "time"
 
 	"github.com/cgrates/cgrates/utils"
@@ -59,17 +60,113 @@ func (sSpls *SortedSuppliers) SortWeight() {
 // SortCost is part of sort interface,
 // sort based on Cost with fallback on Weight
 func (sSpls *SortedSuppliers) SortCost() {
+	sSpls.sortCost(false)
+}
+
+// SortHighestCost is part of sort interface,
+// sort based on highest Cost with fallback on Weight
+func (sSpls *SortedSuppliers) SortHighestCost() {
+	sSpls.sortCost(true)
+}
+
+// sortCost orders on Cost in the requested direction with fallback on Weight
+func (sSpls *SortedSuppliers) sortCost(descending bool) {
 	sort.Slice(sSpls.SortedSuppliers, func(i, j int) bool {
 		if sSpls.SortedSuppliers[i].SortingData[utils.Cost].(float64) == sSpls.SortedSuppliers[j].SortingData[utils.Cost].(float64) {
 			return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
 		}
+		if descending {
+			return sSpls.SortedSuppliers[i].SortingData[utils.Cost].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Cost].(float64)
+		}
 		return sSpls.SortedSuppliers[i].SortingData[utils.Cost].(float64) < sSpls.SortedSuppliers[j].SortingData[utils.Cost].(float64)
 	})
 }
 
+// SortMargin is part of sort interface,
+// sort based on highest Margin with fallback on Weight
+func (sSpls *SortedSuppliers) SortMargin() {
+	sort.Slice(sSpls.SortedSuppliers, func(i, j int) bool {
+		if sSpls.SortedSuppliers[i].SortingData[utils.Margin].(float64) == sSpls.SortedSuppliers[j].SortingData[utils.Margin].(float64) {
+			return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
+		}
+		return sSpls.SortedSuppliers[i].SortingData[utils.Margin].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Margin].(float64)
+	})
+}
+
+// SortResourceUsage is part of sort interface,
+// sort based on ResourceUsage with fallback on Weight
+func (sSpls *SortedSuppliers) SortResourceUsage() {
+	sort.Slice(sSpls.SortedSuppliers, func(i, j int) bool {
+		if sSpls.SortedSuppliers[i].SortingData[utils.ResourceUsage].(float64) == sSpls.SortedSuppliers[j].SortingData[utils.ResourceUsage].(float64) {
+			return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
+		}
+		return sSpls.SortedSuppliers[i].SortingData[utils.ResourceUsage].(float64) < sSpls.SortedSuppliers[j].SortingData[utils.ResourceUsage].(float64)
+	})
+}
+
+// SortLoadDistribution is part of sort interface,
+// sort based on Load (ResourceUsage relative to Ratio) with fallback on Weight
+func (sSpls *SortedSuppliers) SortLoadDistribution() {
+	sort.Slice(sSpls.SortedSuppliers, func(i, j int) bool {
+		if sSpls.SortedSuppliers[i].SortingData[utils.Load].(float64) == sSpls.SortedSuppliers[j].SortingData[utils.Load].(float64) {
+			return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
+		}
+		return sSpls.SortedSuppliers[i].SortingData[utils.Load].(float64) < sSpls.SortedSuppliers[j].SortingData[utils.Load].(float64)
+	})
+}
+
+// SortQOS is part of sort interface,
+// sort based on the list of metrics in params with fallback on Weight
+func (sSpls *SortedSuppliers) SortQOS(params []string) {
+	sort.SliceStable(sSpls.SortedSuppliers, func(i, j int) bool {
+		for _, param := range params {
+			metricID, ascending := qosMetricDirection(param)
+			iVal, iHas := sSpls.SortedSuppliers[i].SortingData[metricID].(float64)
+			jVal, jHas := sSpls.SortedSuppliers[j].SortingData[metricID].(float64)
+			if !iHas || !jHas || iVal == jVal { // cannot decide on this metric, skip to next one
+				continue
+			}
+			// -1 means metric not available yet, give a chance to the supplier to build up stats
+			if iVal == -1 {
+				return true
+			} else if jVal == -1 {
+				return false
+			}
+			if ascending {
+				return iVal < jVal
+			}
+			return iVal > jVal
+		}
+		return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
+	})
+}
+
+// qosMetricDirection splits the sorting parameter into metricID and direction
+// *pdd defaults to ascending (lower is better), the rest to descending
+func qosMetricDirection(param string) (metricID string, ascending bool) {
+	metricID = param
+	ascending = param == utils.MetaPDD
+	if idx := strings.LastIndex(param, utils.InInFieldSep); idx != -1 {
+		switch param[idx+1:] {
+		case utils.MetaAscending:
+			metricID, ascending = param[:idx], true
+		case utils.MetaDescending:
+			metricID, ascending = param[:idx], false
+		}
+	}
+	return
+}
+
+// optsGetSuppliers is used to transmit extra options to supplier sorters
+type optsGetSuppliers struct {
+	ignoreErrors      bool
+	maxCost           *float64
+	sortingParameters []string
+}
+
 // SuppliersSorter is the interface which needs to be implemented by supplier sorters
 type SuppliersSorter interface {
-	SortSuppliers(string, []*Supplier, *utils.CGREvent) (*SortedSuppliers, error)
+	SortSuppliers(string, []*Supplier, *utils.CGREvent, *optsGetSuppliers) (*SortedSuppliers, error)
 }
 
 // NewSupplierSortDispatcher constructs SupplierSortDispatcher
@@ -77,6 +174,11 @@ func NewSupplierSortDispatcher(lcrS *SupplierService) (ssd SupplierSortDispatche
 	ssd = make(map[string]SuppliersSorter)
 	ssd[utils.MetaWeight] = NewWeightSorter()
 	ssd[utils.MetaLeastCost] = NewLeastCostSorter(lcrS)
+	ssd[utils.MetaHighestCost] = NewHighestCostSorter(lcrS)
+	ssd[utils.MetaMaxMargin] = NewMaxMarginSorter(lcrS)
+	ssd[utils.MetaQOS] = NewQOSSupplierSorter(lcrS)
+	ssd[utils.MetaLeastUsed] = NewLeastUsedSorter(lcrS)
+	ssd[utils.MetaLoadDistribution] = NewLoadDistributionSorter(lcrS)
 	return
 }
 
@@ -85,12 +187,12 @@ func NewSupplierSortDispatcher(lcrS *SupplierService) (ssd SupplierSortDispatche
 type SupplierSortDispatcher map[string]SuppliersSorter
 
 func (ssd SupplierSortDispatcher) SortSuppliers(prflID, strategy string,
-	suppls []*Supplier, suplEv *utils.CGREvent) (sortedSuppls *SortedSuppliers, err error) {
+	suppls []*Supplier, suplEv *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
 	sd, has := ssd[strategy]
 	if !has {
 		return nil, fmt.Errorf("unsupported sorting strategy: %s", strategy)
 	}
-	return sd.SortSuppliers(prflID, suppls, suplEv)
+	return sd.SortSuppliers(prflID, suppls, suplEv, extraOpts)
 }
 
 func NewWeightSorter() *WeightSorter {
@@ -103,7 +205,7 @@ type WeightSorter struct {
 }
 
 func (ws *WeightSorter) SortSuppliers(prflID string,
-	suppls []*Supplier, suplEv *utils.CGREvent) (sortedSuppls *SortedSuppliers, err error) {
+	suppls []*Supplier, suplEv *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
 	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
 		Sorting:         ws.sorting,
 		SortedSuppliers: make([]*SortedSupplier, len(suppls))}
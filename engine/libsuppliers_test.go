@@ -137,7 +137,7 @@ func TestLibSuppliersSortWeight(t *testing.T) {
 		Event:  make(map[string]interface{}),
 	}
 	ws := NewWeightSorter()
-	result, err := ws.SortSuppliers("SPL_WEIGHT_1", spl, se)
+	result, err := ws.SortSuppliers("SPL_WEIGHT_1", spl, se, &optsGetSuppliers{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -149,3 +149,218 @@ func TestLibSuppliersSortWeight(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", eSpls.Sorting, result.Sorting)
 	}
 }
+
+func TestLibSuppliersSortQOS(t *testing.T) {
+	sSpls := &SortedSuppliers{
+		SortedSuppliers: []*SortedSupplier{
+			&SortedSupplier{
+				SupplierID: "supplier1",
+				SortingData: map[string]interface{}{
+					utils.Weight:  10.0,
+					utils.MetaASR: 80.0,
+					utils.MetaPDD: 3.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier2",
+				SortingData: map[string]interface{}{
+					utils.Weight:  20.0,
+					utils.MetaASR: 80.0,
+					utils.MetaPDD: 2.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier3",
+				SortingData: map[string]interface{}{
+					utils.Weight:  30.0,
+					utils.MetaASR: 60.0,
+					utils.MetaPDD: 1.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier4",
+				SortingData: map[string]interface{}{
+					utils.Weight:  5.0,
+					utils.MetaASR: -1.0,
+					utils.MetaPDD: -1.0,
+				},
+			},
+		},
+	}
+	sSpls.SortQOS([]string{utils.MetaASR, utils.MetaPDD})
+	eIDs := []string{"supplier4", "supplier2", "supplier1", "supplier3"}
+	if rcv := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcv)
+	}
+	sSpls.SortQOS([]string{utils.MetaPDD + utils.InInFieldSep + utils.MetaDescending})
+	eIDs = []string{"supplier4", "supplier1", "supplier2", "supplier3"}
+	if rcv := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcv)
+	}
+	sSpls.SortQOS([]string{"*acd"}) // missing metric, fallback on weight
+	eIDs = []string{"supplier3", "supplier2", "supplier1", "supplier4"}
+	if rcv := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcv)
+	}
+}
+
+func TestLibSuppliersQOSMetricDirection(t *testing.T) {
+	if mID, asc := qosMetricDirection(utils.MetaASR); mID != utils.MetaASR || asc {
+		t.Errorf("received: %s, %v", mID, asc)
+	}
+	if mID, asc := qosMetricDirection(utils.MetaPDD); mID != utils.MetaPDD || !asc {
+		t.Errorf("received: %s, %v", mID, asc)
+	}
+	if mID, asc := qosMetricDirection("*acd:*asc"); mID != utils.MetaACD || !asc {
+		t.Errorf("received: %s, %v", mID, asc)
+	}
+}
+
+func TestLibSuppliersSortLoadDistribution(t *testing.T) {
+	sSpls := &SortedSuppliers{
+		SortedSuppliers: []*SortedSupplier{
+			&SortedSupplier{
+				SupplierID: "supplier1",
+				SortingData: map[string]interface{}{
+					utils.Weight:        10.0,
+					utils.ResourceUsage: 4.0,
+					utils.Ratio:         2.0,
+					utils.Load:          2.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier2",
+				SortingData: map[string]interface{}{
+					utils.Weight:        20.0,
+					utils.ResourceUsage: 3.0,
+					utils.Ratio:         1.0,
+					utils.Load:          3.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier3",
+				SortingData: map[string]interface{}{
+					utils.Weight:        30.0,
+					utils.ResourceUsage: 2.0,
+					utils.Ratio:         1.0,
+					utils.Load:          2.0,
+				},
+			},
+		},
+	}
+	sSpls.SortLoadDistribution()
+	eIDs := []string{"supplier3", "supplier1", "supplier2"}
+	if rcv := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcv)
+	}
+	sSpls.SortResourceUsage()
+	eIDs = []string{"supplier3", "supplier2", "supplier1"}
+	if rcv := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcv)
+	}
+}
+
+func TestLibSuppliersLoadRatios(t *testing.T) {
+	if dfltRatio, err := loadDefaultRatio(nil); err != nil {
+		t.Error(err)
+	} else if dfltRatio != 1.0 {
+		t.Errorf("Expecting: 1, received: %v", dfltRatio)
+	}
+	dfltRatio, err := loadDefaultRatio([]string{"*default:2"})
+	if err != nil {
+		t.Error(err)
+	} else if dfltRatio != 2.0 {
+		t.Errorf("Expecting: 2, received: %v", dfltRatio)
+	}
+	if _, err := loadDefaultRatio([]string{"supplier1:3"}); err == nil {
+		t.Error("Expecting error")
+	}
+	if ratio, err := loadRatio("3", dfltRatio); err != nil {
+		t.Error(err)
+	} else if ratio != 3.0 {
+		t.Errorf("Expecting: 3, received: %v", ratio)
+	}
+	if ratio, err := loadRatio("", dfltRatio); err != nil {
+		t.Error(err)
+	} else if ratio != 2.0 {
+		t.Errorf("Expecting: 2, received: %v", ratio)
+	}
+	if _, err := loadRatio("param1", dfltRatio); err == nil {
+		t.Error("Expecting error")
+	}
+	if _, err := loadRatio("-1", dfltRatio); err == nil {
+		t.Error("Expecting error")
+	}
+}
+
+func TestLibSuppliersSortHighestCost(t *testing.T) {
+	sSpls := &SortedSuppliers{
+		SortedSuppliers: []*SortedSupplier{
+			&SortedSupplier{
+				SupplierID: "supplier1",
+				SortingData: map[string]interface{}{
+					utils.Cost:   0.1,
+					utils.Weight: 10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier2",
+				SortingData: map[string]interface{}{
+					utils.Cost:   0.1,
+					utils.Weight: 20.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier3",
+				SortingData: map[string]interface{}{
+					utils.Cost:   0.2,
+					utils.Weight: 10.0,
+				},
+			},
+		},
+	}
+	sSpls.SortHighestCost()
+	eIDs := []string{"supplier3", "supplier2", "supplier1"}
+	if rcv := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcv)
+	}
+}
+
+func TestLibSuppliersSortMargin(t *testing.T) {
+	sSpls := &SortedSuppliers{
+		SortedSuppliers: []*SortedSupplier{
+			&SortedSupplier{
+				SupplierID: "supplier1",
+				SortingData: map[string]interface{}{
+					utils.Cost:     0.1,
+					utils.SellCost: 0.3,
+					utils.Margin:   0.2,
+					utils.Weight:   10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier2",
+				SortingData: map[string]interface{}{
+					utils.Cost:     0.4,
+					utils.SellCost: 0.3,
+					utils.Margin:   -0.1,
+					utils.Weight:   30.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier3",
+				SortingData: map[string]interface{}{
+					utils.Cost:     0.1,
+					utils.SellCost: 0.3,
+					utils.Margin:   0.2,
+					utils.Weight:   20.0,
+				},
+			},
+		},
+	}
+	sSpls.SortMargin()
+	eIDs := []string{"supplier3", "supplier1", "supplier2"}
+	if rcv := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rcv) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcv)
+	}
+}
@@ -1843,6 +1843,20 @@ func (tps TpResources) AsTPResources() (result []*utils.TPResource) {
 		if tp.AllocationMessage != "" {
 			rl.AllocationMessage = tp.AllocationMessage
 		}
+		if tp.RateInterval != "" {
+			rl.RateInterval = tp.RateInterval
+		}
+		if tp.TimedLimits != "" { // TimingID1:Limit1;TimingID2:Limit2
+			for _, tlStr := range strings.Split(tp.TimedLimits, utils.INFIELD_SEP) {
+				tl := new(utils.TPResourceTimedLimit)
+				if idx := strings.LastIndex(tlStr, utils.InInFieldSep); idx != -1 {
+					tl.TimingID, tl.Limit = tlStr[:idx], tlStr[idx+1:]
+				} else {
+					tl.Limit = tlStr
+				}
+				rl.TimedLimits = append(rl.TimedLimits, tl)
+			}
+		}
 		rl.Blocker = tp.Blocker
 		rl.Stored = tp.Stored
 		if len(tp.ActivationInterval) != 0 {
@@ -1889,6 +1903,13 @@ func APItoModelResource(rl *utils.TPResource) (mdls TpResources) {
 				mdl.Weight = rl.Weight
 				mdl.Limit = rl.Limit
 				mdl.AllocationMessage = rl.AllocationMessage
+				mdl.RateInterval = rl.RateInterval
+				for i, tl := range rl.TimedLimits {
+					if i != 0 {
+						mdl.TimedLimits += utils.INFIELD_SEP
+					}
+					mdl.TimedLimits += tl.TimingID + utils.InInFieldSep + tl.Limit
+				}
 				if rl.ActivationInterval != nil {
 					if rl.ActivationInterval.ActivationTime != "" {
 						mdl.ActivationInterval = rl.ActivationInterval.ActivationTime
@@ -1941,6 +1962,18 @@ func APItoResource(tpRL *utils.TPResource, timezone string) (rp *ResourceProfile
 			return nil, err
 		}
 	}
+	if tpRL.RateInterval != "" {
+		if rp.RateInterval, err = utils.ParseDurationWithNanosecs(tpRL.RateInterval); err != nil {
+			return nil, err
+		}
+	}
+	for _, tpTL := range tpRL.TimedLimits {
+		tl := &ResourceTimedLimit{TimingID: tpTL.TimingID}
+		if tl.Limit, err = strconv.ParseFloat(tpTL.Limit, 64); err != nil {
+			return nil, err
+		}
+		rp.TimedLimits = append(rp.TimedLimits, tl)
+	}
 	return rp, nil
 }
 
@@ -1981,6 +2014,9 @@ func (tps TpStatsS) AsTPStats() (result []*utils.TPStats) {
 		if tp.TTL != "" {
 			st.TTL = tp.TTL
 		}
+		if tp.BucketInterval != "" {
+			st.BucketInterval = tp.BucketInterval
+		}
 		if tp.Metrics != "" {
 			if _, has := metricmap[tp.Tenant]; !has {
 				metricmap[tp.Tenant] = make(map[string]map[string]*utils.MetricWithParams)
@@ -2091,6 +2127,7 @@ func APItoModelStats(st *utils.TPStats) (mdls TpStatsS) {
 				mdl.Weight = st.Weight
 				mdl.QueueLength = st.QueueLength
 				mdl.MinItems = st.MinItems
+				mdl.BucketInterval = st.BucketInterval
 				for i, val := range st.Metrics {
 					if i != 0 {
 						mdl.Metrics += utils.INFIELD_SEP
@@ -2135,6 +2172,11 @@ func APItoStats(tpST *utils.TPStats, timezone string) (st *StatQueueProfile, err
 			return nil, err
 		}
 	}
+	if tpST.BucketInterval != "" {
+		if st.BucketInterval, err = utils.ParseDurationWithNanosecs(tpST.BucketInterval); err != nil {
+			return nil, err
+		}
+	}
 	for _, trh := range tpST.Thresholds {
 		st.Thresholds = append(st.Thresholds, trh)
 	}
@@ -2712,6 +2754,7 @@ func (tps TPAttributes) AsTPAttributes() (result []*utils.TPAttributeProfile) {
 			th.Attributes = append(th.Attributes, &utils.TPAttribute{
 				FieldName:  tp.FieldName,
 				Initial:    tp.Initial,
+				Type:       tp.Type,
 				Substitute: tp.Substitute,
 				Append:     tp.Append,
 			})
@@ -2764,6 +2807,7 @@ func APItoModelTPAttribute(th *utils.TPAttributeProfile) (mdls TPAttributes) {
 		}
 		mdl.FieldName = reqAttribute.FieldName
 		mdl.Initial = reqAttribute.Initial
+		mdl.Type = reqAttribute.Type
 		mdl.Substitute = reqAttribute.Substitute
 		mdl.Append = reqAttribute.Append
 		mdls = append(mdls, mdl)
@@ -2793,6 +2837,7 @@ func APItoAttributeProfile(tpTH *utils.TPAttributeProfile, timezone string) (th
 		th.Attributes[reqAttr.FieldName][reqAttr.Initial] = &Attribute{
 			FieldName:  reqAttr.FieldName,
 			Initial:    reqAttr.Initial,
+			Type:       reqAttr.Type,
 			Substitute: reqAttr.Substitute,
 			Append:     reqAttr.Append,
 		}
@@ -788,6 +788,9 @@ func TestAPItoResource(t *testing.T) {
 		Limit:              "2",
 		Thresholds:         []string{"TRes1"},
 		AllocationMessage:  "asd",
+		RateInterval:       "1s",
+		TimedLimits: []*utils.TPResourceTimedLimit{
+			&utils.TPResourceTimedLimit{TimingID: "WORKDAYS_00", Limit: "1"}},
 	}
 	eRL := &ResourceProfile{
 		Tenant:            "cgrates.org",
@@ -799,6 +802,9 @@ func TestAPItoResource(t *testing.T) {
 		Thresholds:        []string{"TRes1"},
 		AllocationMessage: tpRL.AllocationMessage,
 		Limit:             2,
+		RateInterval:      time.Second,
+		TimedLimits: []*ResourceTimedLimit{
+			&ResourceTimedLimit{TimingID: "WORKDAYS_00", Limit: 1}},
 	}
 	at, _ := utils.ParseTimeDetectLayout("2014-07-29T15:00:00Z", "UTC")
 	eRL.ActivationInterval = &utils.ActivationInterval{ActivationTime: at}
@@ -807,6 +813,14 @@ func TestAPItoResource(t *testing.T) {
 	} else if !reflect.DeepEqual(eRL, rl) {
 		t.Errorf("Expecting: %+v, received: %+v", eRL, rl)
 	}
+	if mdls := APItoModelResource(tpRL); len(mdls) != 1 {
+		t.Errorf("Unexpected models: %s", utils.ToJSON(mdls))
+	} else if mdls[0].RateInterval != "1s" || mdls[0].TimedLimits != "WORKDAYS_00:1" {
+		t.Errorf("Unexpected model: %s", utils.ToJSON(mdls[0]))
+	} else if rcv := mdls.AsTPResources(); len(rcv) != 1 ||
+		!reflect.DeepEqual(tpRL.TimedLimits, rcv[0].TimedLimits) {
+		t.Errorf("Unexpected TPResources: %s", utils.ToJSON(rcv))
+	}
 }
 
 func TestTPStatsAsTPStats(t *testing.T) {
@@ -949,11 +963,12 @@ func TestAPItoTPStats(t *testing.T) {
 			&utils.MetricWithParams{MetricID: "*acd", Parameters: ""},
 			&utils.MetricWithParams{MetricID: "*acc", Parameters: ""},
 		},
-		MinItems:   1,
-		Thresholds: []string{"THRESH1", "THRESH2"},
-		Stored:     false,
-		Blocker:    false,
-		Weight:     20.0,
+		MinItems:       1,
+		Thresholds:     []string{"THRESH1", "THRESH2"},
+		Stored:         false,
+		Blocker:        false,
+		Weight:         20.0,
+		BucketInterval: "1m",
 	}
 
 	eTPs := &StatQueueProfile{ID: tps.ID,
@@ -967,8 +982,9 @@ func TestAPItoTPStats(t *testing.T) {
 		FilterIDs:  []string{"FLTR_1"},
 		Stored:     tps.Stored,
 		Blocker:    tps.Blocker,
-		Weight:     20.0,
-		MinItems:   tps.MinItems,
+		Weight:         20.0,
+		MinItems:       tps.MinItems,
+		BucketInterval: time.Minute,
 	}
 	if eTPs.TTL, err = utils.ParseDurationWithNanosecs(tps.TTL); err != nil {
 		t.Errorf("Got error: %+v", err)
@@ -981,6 +997,9 @@ func TestAPItoTPStats(t *testing.T) {
 	} else if !reflect.DeepEqual(eTPs, st) {
 		t.Errorf("Expecting: %+v, received: %+v", eTPs, st)
 	}
+	if rcv := APItoModelStats(tps).AsTPStats(); len(rcv) != 1 || rcv[0].BucketInterval != tps.BucketInterval {
+		t.Errorf("Received: %s", utils.ToJSON(rcv))
+	}
 }
 
 func TestAsTPThresholdAsAsTPThreshold(t *testing.T) {
@@ -1216,7 +1235,8 @@ func TestAPItoModelTPAttribute(t *testing.T) {
 			&utils.TPAttribute{
 				FieldName:  "FL1",
 				Initial:    "In1",
-				Substitute: "Al1",
+				Type:       utils.MetaVariable,
+				Substitute: "~Account",
 				Append:     true,
 			},
 		},
@@ -1231,7 +1251,8 @@ func TestAPItoModelTPAttribute(t *testing.T) {
 			FilterIDs:          "FLTR_ACNT_dan;FLTR_DST_DE",
 			FieldName:          "FL1",
 			Initial:            "In1",
-			Substitute:         "Al1",
+			Type:               utils.MetaVariable,
+			Substitute:         "~Account",
 			Append:             true,
 			ActivationInterval: "2014-07-14T14:35:00Z",
 			Weight:             20,
@@ -1253,7 +1274,8 @@ func TestModelAsTPAttribute(t *testing.T) {
 			FilterIDs:          "FLTR_ACNT_dan;FLTR_DST_DE",
 			FieldName:          "FL1",
 			Initial:            "In1",
-			Substitute:         "Al1",
+			Type:               utils.MetaVariable,
+			Substitute:         "~Account",
 			Append:             true,
 			ActivationInterval: "2014-07-14T14:35:00Z",
 			Weight:             20,
@@ -1273,7 +1295,8 @@ func TestModelAsTPAttribute(t *testing.T) {
 			&utils.TPAttribute{
 				FieldName:  "FL1",
 				Initial:    "In1",
-				Substitute: "Al1",
+				Type:       utils.MetaVariable,
+				Substitute: "~Account",
 				Append:     true,
 			},
 		},
@@ -412,6 +412,8 @@ type TpResource struct {
 	Stored             bool    `index:"8" re:""`
 	Weight             float64 `index:"9" re:"\d+\.?\d*"`
 	Thresholds         string  `index:"10" re:""`
+	RateInterval       string  `index:"11" re:""`
+	TimedLimits        string  `index:"12" re:""`
 	CreatedAt          time.Time
 }
 
@@ -431,6 +433,7 @@ type TpStats struct {
 	Weight             float64 `index:"10" re:"\d+\.?\d*"`
 	MinItems           int     `index:"11" re:""`
 	Thresholds         string  `index:"12" re:""`
+	BucketInterval     string  `index:"13" re:""`
 	CreatedAt          time.Time
 }
 
@@ -556,5 +559,6 @@ type TPAttribute struct {
 	Substitute         string  `index:"7" re:""`
 	Append             bool    `index:"8" re:""`
 	Weight             float64 `index:"9" re:"\d+\.?\d*"`
+	Type               string  `index:"10" re:""`
 	CreatedAt          time.Time
 }
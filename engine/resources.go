@@ -48,8 +48,10 @@ type ResourceProfile struct {
 	AllocationMessage  string                    // message returned by the winning resource on allocation
 	Blocker            bool                      // blocker flag to stop processing on filters matched
 	Stored             bool
-	Weight             float64  // Weight to sort the resources
-	Thresholds         []string // Thresholds to check after changing Limit
+	Weight             float64               // Weight to sort the resources
+	Thresholds         []string              // Thresholds to check after changing Limit
+	RateInterval       time.Duration         // limit the units allocated within this sliding window instead of the concurrent ones, ie: 1s for CPS
+	TimedLimits        []*ResourceTimedLimit // override the Limit while active, first active one wins
 }
 
 // TenantID returns unique identifier of the ResourceProfile in a multi-tenant environment
@@ -57,12 +59,48 @@ func (rp *ResourceProfile) TenantID() string {
 	return utils.ConcatenatedKey(rp.Tenant, rp.ID)
 }
 
+// limitAt returns the limit in effect at atTime, timings being queried over dm
+func (rp *ResourceProfile) limitAt(atTime time.Time, dm *DataManager) float64 {
+	for _, tl := range rp.TimedLimits {
+		if tl.isActiveAt(atTime, dm) {
+			return tl.Limit
+		}
+	}
+	return rp.Limit
+}
+
+// ResourceTimedLimit is a limit applied only within a timing, ie: business hours
+type ResourceTimedLimit struct {
+	TimingID           string                    // ID of the timing when the limit is active, empty for always
+	ActivationInterval *utils.ActivationInterval // optional time window of the limit
+	Limit              float64
+}
+
+// isActiveAt checks the ActivationInterval and the timing of the limit
+func (tl *ResourceTimedLimit) isActiveAt(atTime time.Time, dm *DataManager) bool {
+	if tl.ActivationInterval != nil && !tl.ActivationInterval.IsActiveAtTime(atTime) {
+		return false
+	}
+	if tl.TimingID == "" || tl.TimingID == utils.ANY {
+		return true
+	}
+	tmg, err := dm.GetTiming(tl.TimingID, false, utils.NonTransactional)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<ResourceS> cannot retrieve timing with id: %s, error: %s", tl.TimingID, err.Error()))
+		return false
+	}
+	return (&RITiming{Years: tmg.Years, Months: tmg.Months, MonthDays: tmg.MonthDays,
+		WeekDays: tmg.WeekDays, StartTime: tmg.StartTime, EndTime: tmg.EndTime}).IsActiveAt(atTime)
+}
+
 // ResourceUsage represents an usage counted
 type ResourceUsage struct {
 	Tenant     string
 	ID         string // Unique identifier of this ResourceUsage, Eg: FreeSWITCH UUID
 	ExpiryTime time.Time
 	Units      float64 // Number of units used
+	Reserved   bool    // held until confirmed, released automatically on ExpiryTime otherwise
 }
 
 func (ru *ResourceUsage) TenantID() string {
@@ -94,6 +132,22 @@ type Resource struct {
 	rPrf   *ResourceProfile // for ordering purposes
 }
 
+// Clone duplicates r, the usages not being shared with the original
+func (r *Resource) Clone() (cln *Resource) {
+	cln = &Resource{Tenant: r.Tenant, ID: r.ID, ttl: r.ttl, rPrf: r.rPrf}
+	if r.Usages != nil {
+		cln.Usages = make(map[string]*ResourceUsage, len(r.Usages))
+		for ruID, ru := range r.Usages {
+			cln.Usages[ruID] = ru.Clone()
+		}
+	}
+	if r.TTLIdx != nil {
+		cln.TTLIdx = make([]string, len(r.TTLIdx))
+		copy(cln.TTLIdx, r.TTLIdx)
+	}
+	return
+}
+
 // TenantID returns the unique ID in a multi-tenant environment
 func (r *Resource) TenantID() string {
 	return utils.ConcatenatedKey(r.Tenant, r.ID)
@@ -117,6 +171,9 @@ func (r *Resource) removeExpiredUnits() {
 			continue
 		}
 		delete(r.Usages, rID)
+		if r.tUsage == nil { // total usage not computed yet
+			continue
+		}
 		*r.tUsage -= ru.Units
 		if *r.tUsage < 0 { // something went wrong
 			utils.Logger.Warning(
@@ -148,11 +205,12 @@ func (r *Resource) recordUsage(ru *ResourceUsage) (err error) {
 	if _, hasID := r.Usages[ru.ID]; hasID {
 		return fmt.Errorf("duplicate resource usage with id: %s", ru.TenantID())
 	}
-	if r.ttl != nil {
-		if *r.ttl == 0 {
-			return // no recording for ttl of 0
-		}
-		ru = ru.Clone() // don't influence the initial ru
+	if r.ttl != nil && *r.ttl == 0 {
+		return // no recording for ttl of 0
+	}
+	ru = ru.Clone() // don't influence the initial ru
+	// reservations come with their own ExpiryTime
+	if r.ttl != nil && !ru.Reserved {
 		ru.ExpiryTime = time.Now().Add(*r.ttl)
 	}
 	r.Usages[ru.ID] = ru
@@ -160,11 +218,66 @@ func (r *Resource) recordUsage(ru *ResourceUsage) (err error) {
 		*r.tUsage += ru.Units
 	}
 	if !ru.ExpiryTime.IsZero() {
-		r.TTLIdx = append(r.TTLIdx, ru.ID)
+		r.indexTTL(ru)
+	}
+	return
+}
+
+// indexTTL adds the usage to TTLIdx, keeping it ordered on ExpiryTime
+func (r *Resource) indexTTL(ru *ResourceUsage) {
+	idx := sort.Search(len(r.TTLIdx), func(i int) bool {
+		u, has := r.Usages[r.TTLIdx[i]]
+		return has && u.ExpiryTime.After(ru.ExpiryTime)
+	})
+	r.TTLIdx = append(r.TTLIdx, "")
+	copy(r.TTLIdx[idx+1:], r.TTLIdx[idx:])
+	r.TTLIdx[idx] = ru.ID
+}
+
+// unindexTTL removes the usage with ruID out of TTLIdx
+func (r *Resource) unindexTTL(ruID string) {
+	for i, ruIDIdx := range r.TTLIdx {
+		if ruIDIdx == ruID {
+			r.TTLIdx = append(r.TTLIdx[:i], r.TTLIdx[i+1:]...)
+			break
+		}
+	}
+}
+
+// confirmUsage converts a reserved usage into a normal one, expiring based on the resource ttl
+func (r *Resource) confirmUsage(ruID string) (err error) {
+	if err = r.canConfirmUsage(ruID); err != nil {
+		return
+	}
+	ru := r.Usages[ruID]
+	r.unindexTTL(ruID)
+	ru.Reserved = false
+	ru.ExpiryTime = time.Time{}
+	if r.ttl != nil {
+		ru.ExpiryTime = time.Now().Add(*r.ttl)
+		r.indexTTL(ru)
+	}
+	return
+}
+
+// canConfirmUsage checks if there is an active reservation for ruID
+func (r *Resource) canConfirmUsage(ruID string) (err error) {
+	ru, hasIt := r.Usages[ruID]
+	if !hasIt || !ru.Reserved || !ru.isActive(time.Now()) {
+		return fmt.Errorf("cannot find reserved usage with id: %s", ruID)
 	}
 	return
 }
 
+// hasRateUsage checks if the usage with ruID is still counted within the RateInterval of a rate resource
+func (r *Resource) hasRateUsage(ruID string) (has bool) {
+	if r.rPrf == nil || r.rPrf.RateInterval <= 0 {
+		return
+	}
+	_, has = r.Usages[ruID]
+	return
+}
+
 // clearUsage clears the usage for an ID
 func (r *Resource) clearUsage(ruID string) (err error) {
 	ru, hasIt := r.Usages[ruID]
@@ -172,12 +285,7 @@ func (r *Resource) clearUsage(ruID string) (err error) {
 		return fmt.Errorf("cannot find usage record with id: %s", ruID)
 	}
 	if !ru.ExpiryTime.IsZero() {
-		for i, ruIDIdx := range r.TTLIdx {
-			if ruIDIdx == ruID {
-				r.TTLIdx = append(r.TTLIdx[:i], r.TTLIdx[i+1:]...)
-				break
-			}
-		}
+		r.unindexTTL(ruID)
 	}
 	if r.tUsage != nil {
 		*r.tUsage -= ru.Units
@@ -196,16 +304,19 @@ func (rs Resources) Sort() {
 
 // recordUsage will record the usage in all the resource limits, failing back on errors
 func (rs Resources) recordUsage(ru *ResourceUsage) (err error) {
-	var nonReservedIdx int // index of first resource not reserved
+	var recordedRs Resources
 	for _, r := range rs {
+		if r.hasRateUsage(ru.ID) { // retried attempt, already counted within the RateInterval
+			continue
+		}
 		if err = r.recordUsage(ru); err != nil {
 			utils.Logger.Warning(fmt.Sprintf("<ResourceLimits>, err: %s", err.Error()))
 			break
 		}
-		nonReservedIdx += 1
+		recordedRs = append(recordedRs, r)
 	}
 	if err != nil {
-		for _, r := range rs[:nonReservedIdx] {
+		for _, r := range recordedRs {
 			r.clearUsage(ru.TenantID()) // best effort
 		}
 	}
@@ -213,8 +324,12 @@ func (rs Resources) recordUsage(ru *ResourceUsage) (err error) {
 }
 
 // clearUsage gives back the units to the pool
+// rate resources keep the units until they leave the RateInterval
 func (rs Resources) clearUsage(ruTntID string) (err error) {
 	for _, r := range rs {
+		if r.rPrf != nil && r.rPrf.RateInterval > 0 {
+			continue
+		}
 		if errClear := r.clearUsage(ruTntID); errClear != nil &&
 			r.ttl != nil && *r.ttl != 0 { // we only consider not found error in case of ttl different than 0
 			utils.Logger.Warning(fmt.Sprintf("<ResourceLimits>, clear ruID: %s, err: %s", ruTntID, errClear.Error()))
@@ -224,6 +339,27 @@ func (rs Resources) clearUsage(ruTntID string) (err error) {
 	return
 }
 
+// confirmUsage confirms the reserved usage in all the resources
+// nothing is confirmed unless the reservation is active in all of them
+func (rs Resources) confirmUsage(ruID string) (err error) {
+	var confirmRs Resources
+	for _, r := range rs {
+		if r.ttl != nil && *r.ttl == 0 { // usage was not recorded
+			continue
+		}
+		r.removeExpiredUnits()
+		if err = r.canConfirmUsage(ruID); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<ResourceLimits>, confirm ruID: %s, err: %s", ruID, err.Error()))
+			return
+		}
+		confirmRs = append(confirmRs, r)
+	}
+	for _, r := range confirmRs {
+		r.confirmUsage(ruID)
+	}
+	return
+}
+
 // tenantIDs returns list of TenantIDs in resources
 func (rs Resources) tenantIDs() []*utils.TenantID {
 	tntIDs := make([]*utils.TenantID, len(rs))
@@ -244,7 +380,7 @@ func (rs Resources) tenatIDsStr() []string {
 // allocateResource attempts allocating resources for a *ResourceUsage
 // simulates on dryRun
 // returns utils.ErrResourceUnavailable if allocation is not possible
-func (rs Resources) allocateResource(ru *ResourceUsage, dryRun bool) (alcMessage string, err error) {
+func (rs Resources) allocateResource(ru *ResourceUsage, dryRun bool, dm *DataManager) (alcMessage string, err error) {
 	if len(rs) == 0 {
 		return "", utils.ErrResourceUnavailable
 	}
@@ -252,9 +388,14 @@ func (rs Resources) allocateResource(ru *ResourceUsage, dryRun bool) (alcMessage
 	guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lockIDs...)
 	defer guardian.Guardian.UnguardIDs(lockIDs...)
 	// Simulate resource usage
+	now := time.Now()
 	for _, r := range rs {
 		r.removeExpiredUnits()
-		if r.rPrf.Limit >= r.totalUsage()+ru.Units {
+		units := ru.Units
+		if r.hasRateUsage(ru.ID) { // retries of the same UsageID are not counted twice
+			units = 0
+		}
+		if r.rPrf.limitAt(now, dm) >= r.totalUsage()+units {
 			if alcMessage == "" {
 				if r.rPrf.AllocationMessage != "" {
 					alcMessage = r.rPrf.AllocationMessage
@@ -279,7 +420,8 @@ func (rs Resources) allocateResource(ru *ResourceUsage, dryRun bool) (alcMessage
 
 // Pas the config as a whole so we can ask access concurrently
 func NewResourceService(dm *DataManager, storeInterval time.Duration,
-	thdS rpcclient.RpcClientConnection, filterS *FilterS, indexedFields []string) (*ResourceService, error) {
+	thdS rpcclient.RpcClientConnection, filterS *FilterS, indexedFields []string,
+	reservationTTL time.Duration) (*ResourceService, error) {
 	if thdS != nil && reflect.ValueOf(thdS).IsNil() {
 		thdS = nil
 	}
@@ -288,6 +430,7 @@ func NewResourceService(dm *DataManager, storeInterval time.Duration,
 		storedResources:  make(utils.StringMap),
 		storeInterval:    storeInterval,
 		filterS:          filterS,
+		reservationTTL:   reservationTTL,
 		stopBackup:       make(chan struct{})}, nil
 }
 
@@ -302,6 +445,7 @@ type ResourceService struct {
 	storedResources  utils.StringMap              // keep a record of resources which need saving, map[resID]bool
 	srMux            sync.RWMutex                 // protects storedResources
 	storeInterval    time.Duration                // interval to dump data on
+	reservationTTL   time.Duration                // hold reserved usages for this long unless confirmed
 	stopBackup       chan struct{}                // control storing process
 }
 
@@ -465,7 +609,9 @@ func (rS *ResourceService) matchingResourcesForEvent(tenant string, ev map[strin
 		if rPrf.Stored && r.dirty == nil {
 			r.dirty = utils.BoolPointer(false)
 		}
-		if rPrf.UsageTTL >= 0 {
+		if rPrf.RateInterval > 0 { // usages stay recorded for the whole window
+			r.ttl = utils.DurationPointer(rPrf.RateInterval)
+		} else if rPrf.UsageTTL >= 0 {
 			r.ttl = utils.DurationPointer(rPrf.UsageTTL)
 		}
 		r.rPrf = rPrf
@@ -535,6 +681,125 @@ func (rS *ResourceService) V1ResourcesForEvent(args utils.ArgRSv1ResourceUsage,
 	return
 }
 
+// V1GetResource returns a resource with it's active usages
+func (rS *ResourceService) V1GetResource(args *utils.TenantID, reply *Resource) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	lockID := utils.ResourcesPrefix + args.TenantID()
+	guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lockID)
+	defer guardian.Guardian.UnguardIDs(lockID)
+	r, err := rS.dm.GetResource(args.Tenant, args.ID, false, "")
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	r.removeExpiredUnits()
+	*reply = *r.Clone() // do not share the cached usages with internal callers
+	return
+}
+
+// V1GetResourceIDs returns the list of resource IDs registered for a tenant
+func (rS *ResourceService) V1GetResourceIDs(tenant string, rIDs *[]string) (err error) {
+	prfx := utils.ResourcesPrefix + tenant + utils.CONCATENATED_KEY_SEP
+	keys, err := rS.dm.DataDB().GetKeysForPrefix(prfx)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return utils.ErrNotFound
+	}
+	retIDs := make([]string, len(keys))
+	for i, key := range keys {
+		retIDs[i] = key[len(prfx):]
+	}
+	sort.Strings(retIDs)
+	*rIDs = retIDs
+	return
+}
+
+// ResourceDetails is a snapshot of a Resource with its active usages
+type ResourceDetails struct {
+	Tenant     string
+	ID         string
+	Limit      float64          // limit in effect at the time of the snapshot
+	TotalUsage float64          // sum of the active usages
+	Usages     []*ResourceUsage // active usages, ordered on ExpiryTime with the ones not expiring last
+}
+
+// ArgGetResourcesDetails selects the resources in V1GetResourcesDetails
+type ArgGetResourcesDetails struct {
+	Tenant      string
+	ResourceIDs []string // empty for all the resources of the tenant
+}
+
+// resourceDetails builds the ResourceDetails for the resource with ID rID
+func (rS *ResourceService) resourceDetails(tenant, rID string) (rDtls *ResourceDetails, err error) {
+	lockID := utils.ResourcesPrefix + utils.ConcatenatedKey(tenant, rID)
+	guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lockID)
+	defer guardian.Guardian.UnguardIDs(lockID)
+	r, err := rS.dm.GetResource(tenant, rID, false, "")
+	if err != nil {
+		return
+	}
+	r.removeExpiredUnits()
+	rPrf := r.rPrf
+	if rPrf == nil {
+		if rPrf, err = rS.dm.GetResourceProfile(tenant, rID, false, utils.NonTransactional); err != nil {
+			return
+		}
+	}
+	now := time.Now()
+	rDtls = &ResourceDetails{Tenant: r.Tenant, ID: r.ID,
+		Limit: rPrf.limitAt(now, rS.dm), Usages: make([]*ResourceUsage, 0, len(r.Usages))}
+	for _, ru := range r.Usages {
+		if !ru.isActive(now) {
+			continue
+		}
+		rDtls.TotalUsage += ru.Units
+		rDtls.Usages = append(rDtls.Usages, ru.Clone())
+	}
+	sort.Slice(rDtls.Usages, func(i, j int) bool {
+		iExp, jExp := rDtls.Usages[i].ExpiryTime, rDtls.Usages[j].ExpiryTime
+		if iExp.Equal(jExp) {
+			return rDtls.Usages[i].ID < rDtls.Usages[j].ID
+		}
+		return !iExp.IsZero() && (jExp.IsZero() || iExp.Before(jExp))
+	})
+	return
+}
+
+// V1GetResourcesDetails returns the active usages of the resources, together with the limit in effect
+func (rS *ResourceService) V1GetResourcesDetails(args *ArgGetResourcesDetails, reply *[]*ResourceDetails) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"Tenant"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	rIDs := args.ResourceIDs
+	if len(rIDs) == 0 {
+		if err = rS.V1GetResourceIDs(args.Tenant, &rIDs); err != nil {
+			return
+		}
+	}
+	rDtls := make([]*ResourceDetails, 0, len(rIDs))
+	for _, rID := range rIDs {
+		rDtl, err := rS.resourceDetails(args.Tenant, rID)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return utils.NewErrServerError(err)
+		}
+		rDtls = append(rDtls, rDtl)
+	}
+	if len(rDtls) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = rDtls
+	return
+}
+
 // V1AuthorizeResources queries service to find if an Usage is allowed
 func (rS *ResourceService) V1AuthorizeResources(args utils.ArgRSv1ResourceUsage, reply *string) (err error) {
 	var alcMessage string
@@ -555,7 +820,7 @@ func (rS *ResourceService) V1AuthorizeResources(args utils.ArgRSv1ResourceUsage,
 		&ResourceUsage{
 			Tenant: args.CGREvent.Tenant,
 			ID:     args.UsageID,
-			Units:  args.Units}, true); err != nil {
+			Units:  args.Units}, true, rS.dm); err != nil {
 		if err == utils.ErrResourceUnavailable {
 			err = utils.ErrResourceUnauthorized
 			cache.Set(utils.EventResourcesPrefix+args.UsageID, nil, true, "")
@@ -574,6 +839,36 @@ func (rS *ResourceService) V1AllocateResource(args utils.ArgRSv1ResourceUsage, r
 	if missing := utils.MissingStructFields(&args, []string{"UsageID"}); len(missing) != 0 { //Params missing
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
+	alcMsg, err := rS.allocateUsage(args,
+		&ResourceUsage{Tenant: args.CGREvent.Tenant, ID: args.UsageID, Units: args.Units})
+	if err != nil {
+		return
+	}
+	*reply = alcMsg
+	return
+}
+
+// V1ReserveResource holds the units for reservationTTL
+// the reservation is released automatically unless confirmed with V1ConfirmResource
+func (rS *ResourceService) V1ReserveResource(args utils.ArgRSv1ResourceUsage, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&args.CGREvent, []string{"Tenant"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if missing := utils.MissingStructFields(&args, []string{"UsageID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	alcMsg, err := rS.allocateUsage(args,
+		&ResourceUsage{Tenant: args.CGREvent.Tenant, ID: args.UsageID, Units: args.Units,
+			ExpiryTime: time.Now().Add(rS.reservationTTL), Reserved: true})
+	if err != nil {
+		return
+	}
+	*reply = alcMsg
+	return
+}
+
+// allocateUsage records ru on the resources matching the event, indexing them for later queries
+func (rS *ResourceService) allocateUsage(args utils.ArgRSv1ResourceUsage, ru *ResourceUsage) (alcMsg string, err error) {
 	var wasCached bool
 	mtcRLs := rS.cachedResourcesForEvent(args.UsageID)
 	if mtcRLs == nil {
@@ -583,9 +878,7 @@ func (rS *ResourceService) V1AllocateResource(args utils.ArgRSv1ResourceUsage, r
 	} else {
 		wasCached = true
 	}
-	alcMsg, err := mtcRLs.allocateResource(
-		&ResourceUsage{Tenant: args.CGREvent.Tenant, ID: args.UsageID, Units: args.Units}, false)
-	if err != nil {
+	if alcMsg, err = mtcRLs.allocateResource(ru, false, rS.dm); err != nil {
 		return
 	}
 
@@ -618,7 +911,46 @@ func (rS *ResourceService) V1AllocateResource(args utils.ArgRSv1ResourceUsage, r
 		}
 		rS.processThresholds(r)
 	}
-	*reply = alcMsg
+	return
+}
+
+// V1ConfirmResource converts a reservation made with V1ReserveResource into a normal allocation
+func (rS *ResourceService) V1ConfirmResource(args utils.ArgRSv1ResourceUsage, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&args.CGREvent, []string{"Tenant"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if missing := utils.MissingStructFields(&args, []string{"UsageID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	mtcRLs := rS.cachedResourcesForEvent(args.UsageID)
+	if mtcRLs == nil {
+		if mtcRLs, err = rS.matchingResourcesForEvent(args.CGREvent.Tenant, args.CGREvent.Event); err != nil {
+			return
+		}
+	}
+	if len(mtcRLs) == 0 {
+		return utils.ErrNotFound
+	}
+	lockIDs := utils.PrefixSliceItems(mtcRLs.tenatIDsStr(), utils.ResourcesPrefix)
+	guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lockIDs...)
+	defer guardian.Guardian.UnguardIDs(lockIDs...)
+	if err = mtcRLs.confirmUsage(args.UsageID); err != nil { // reservation expired or missing, the rest expire on their own
+		return utils.ErrNotFound
+	}
+	for _, r := range mtcRLs {
+		if rS.storeInterval == 0 || r.dirty == nil {
+			continue
+		}
+		if rS.storeInterval == -1 {
+			rS.StoreResource(r)
+		} else {
+			*r.dirty = true // mark it to be saved
+			rS.srMux.Lock()
+			rS.storedResources[r.TenantID()] = true
+			rS.srMux.Unlock()
+		}
+	}
+	*reply = utils.OK
 	return
 }
 
@@ -107,6 +107,24 @@ func TestRSRemoveExpiredUnits(t *testing.T) {
 	}
 }
 
+func TestRSClone(t *testing.T) {
+	r := &Resource{Tenant: "cgrates.org", ID: "RES_CLONE",
+		Usages: map[string]*ResourceUsage{
+			"RU1": &ResourceUsage{Tenant: "cgrates.org", ID: "RU1", Units: 1},
+		},
+		TTLIdx: []string{"RU1"}}
+	cln := r.Clone()
+	if !reflect.DeepEqual(r, cln) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(r), utils.ToJSON(cln))
+	}
+	cln.Usages["RU1"].Units = 2
+	cln.Usages["RU2"] = &ResourceUsage{Tenant: "cgrates.org", ID: "RU2", Units: 1}
+	cln.TTLIdx[0] = "RU2"
+	if len(r.Usages) != 1 || r.Usages["RU1"].Units != 1 || r.TTLIdx[0] != "RU1" {
+		t.Errorf("Original modified: %s", utils.ToJSON(r))
+	}
+}
+
 func TestRSUsedUnits(t *testing.T) {
 	r1.Usages = map[string]*ResourceUsage{
 		ru1.ID: ru1,
@@ -185,19 +203,19 @@ func TestRSAllocateResource(t *testing.T) {
 	rs.clearUsage(ru2.ID)
 	ru1.ExpiryTime = time.Now().Add(time.Duration(1 * time.Second))
 	ru2.ExpiryTime = time.Now().Add(time.Duration(1 * time.Second))
-	if alcMessage, err := rs.allocateResource(ru1, false); err != nil {
+	if alcMessage, err := rs.allocateResource(ru1, false, dm); err != nil {
 		t.Error(err.Error())
 	} else {
 		if alcMessage != "ALLOC" {
 			t.Errorf("Wrong allocation message: %v", alcMessage)
 		}
 	}
-	if _, err := rs.allocateResource(ru2, false); err != utils.ErrResourceUnavailable {
+	if _, err := rs.allocateResource(ru2, false, dm); err != utils.ErrResourceUnavailable {
 		t.Error("Did not receive " + utils.ErrResourceUnavailable.Error() + " error")
 	}
 	rs[0].rPrf.Limit = 1
 	rs[1].rPrf.Limit = 4
-	if alcMessage, err := rs.allocateResource(ru1, true); err != nil {
+	if alcMessage, err := rs.allocateResource(ru1, true, dm); err != nil {
 		t.Error(err.Error())
 	} else {
 		if alcMessage != "RL2" {
@@ -205,7 +223,7 @@ func TestRSAllocateResource(t *testing.T) {
 		}
 	}
 
-	if alcMessage, err := rs.allocateResource(ru2, false); err != nil {
+	if alcMessage, err := rs.allocateResource(ru2, false, dm); err != nil {
 		t.Error(err.Error())
 	} else {
 		if alcMessage != "RL2" {
@@ -214,7 +232,7 @@ func TestRSAllocateResource(t *testing.T) {
 	}
 
 	ru2.Units = 0
-	if _, err := rs.allocateResource(ru2, false); err == nil {
+	if _, err := rs.allocateResource(ru2, false, dm); err == nil {
 		t.Error("Duplicate ResourceUsage id should not be allowed")
 	}
 }
@@ -293,3 +311,163 @@ func TestV1AuthorizeResourceMissingStruct(t *testing.T) {
 		t.Error(err.Error())
 	}
 }
+
+func TestRSReserveConfirmUsage(t *testing.T) {
+	r := &Resource{
+		Tenant: "cgrates.org",
+		ID:     "RES_RESERVE",
+		Usages: make(map[string]*ResourceUsage),
+		ttl:    utils.DurationPointer(time.Duration(time.Hour)),
+	}
+	ruReserved := &ResourceUsage{Tenant: "cgrates.org", ID: "RU_RESERVED", Units: 1,
+		ExpiryTime: time.Now().Add(time.Minute), Reserved: true}
+	ruExpired := &ResourceUsage{Tenant: "cgrates.org", ID: "RU_EXPIRED", Units: 2,
+		ExpiryTime: time.Now().Add(-time.Minute), Reserved: true}
+	for _, ru := range []*ResourceUsage{ruReserved, ruExpired} {
+		if err := r.recordUsage(ru); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual([]string{"RU_EXPIRED", "RU_RESERVED"}, r.TTLIdx) {
+		t.Errorf("TTLIdx not ordered on ExpiryTime: %+v", r.TTLIdx)
+	}
+	if err := r.confirmUsage("RU_EXPIRED"); err == nil {
+		t.Error("Expecting error on expired reservation")
+	}
+	r.removeExpiredUnits()
+	if _, has := r.Usages["RU_EXPIRED"]; has {
+		t.Error("Expired reservation not released")
+	} else if usage := r.totalUsage(); usage != 1 {
+		t.Errorf("Expecting: 1, received: %v", usage)
+	}
+	if err := r.confirmUsage("RU_RESERVED"); err != nil {
+		t.Error(err)
+	} else if ru := r.Usages["RU_RESERVED"]; ru.Reserved ||
+		ru.ExpiryTime.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("Usage not confirmed: %+v", ru)
+	} else if !reflect.DeepEqual([]string{"RU_RESERVED"}, r.TTLIdx) {
+		t.Errorf("Unexpected TTLIdx: %+v", r.TTLIdx)
+	}
+	if err := r.confirmUsage("RU_RESERVED"); err == nil {
+		t.Error("Expecting error on already confirmed usage")
+	}
+	r2 := &Resource{
+		Tenant: "cgrates.org",
+		ID:     "RES_RESERVE2",
+		Usages: make(map[string]*ResourceUsage),
+	}
+	ruReserved2 := &ResourceUsage{Tenant: "cgrates.org", ID: "RU_RESERVED2", Units: 1,
+		ExpiryTime: time.Now().Add(time.Minute), Reserved: true}
+	if err := r.recordUsage(ruReserved2); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Resources{r, r2}).confirmUsage("RU_RESERVED2"); err == nil {
+		t.Error("Expecting error on missing reservation")
+	} else if !r.Usages["RU_RESERVED2"].Reserved {
+		t.Error("Reservation confirmed partially")
+	}
+}
+
+func TestRSProfileLimitAt(t *testing.T) {
+	if err := dm.SetTiming(&utils.TPTiming{ID: "TM_WEEKEND",
+		WeekDays: utils.WeekDays{time.Saturday, time.Sunday}, StartTime: "00:00:00"}); err != nil {
+		t.Fatal(err)
+	}
+	rPrf := &ResourceProfile{
+		Tenant: "cgrates.org",
+		ID:     "RES_TIMED",
+		Limit:  10,
+		TimedLimits: []*ResourceTimedLimit{
+			&ResourceTimedLimit{
+				ActivationInterval: &utils.ActivationInterval{
+					ActivationTime: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+					ExpiryTime:     time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC)},
+				Limit: 1},
+			&ResourceTimedLimit{TimingID: "TM_WEEKEND", Limit: 2},
+			&ResourceTimedLimit{TimingID: "TM_MISSING", Limit: 3},
+		},
+	}
+	for atTime, eLimit := range map[time.Time]float64{
+		time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC): 1,  // activation interval, monday
+		time.Date(2018, 1, 6, 10, 0, 0, 0, time.UTC): 2,  // saturday
+		time.Date(2018, 1, 8, 10, 0, 0, 0, time.UTC): 10, // monday, default limit
+	} {
+		if limit := rPrf.limitAt(atTime, dm); limit != eLimit {
+			t.Errorf("At: %v, expecting: %v, received: %v", atTime, eLimit, limit)
+		}
+	}
+}
+
+func TestRSRateResourceClearUsage(t *testing.T) {
+	r := &Resource{
+		Tenant: "cgrates.org",
+		ID:     "RES_CPS",
+		Usages: make(map[string]*ResourceUsage),
+		ttl:    utils.DurationPointer(time.Second),
+		rPrf: &ResourceProfile{Tenant: "cgrates.org", ID: "RES_CPS",
+			Limit: 1, RateInterval: time.Second},
+	}
+	rs := Resources{r}
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org",
+		ID: "CALL1", Units: 1}, false, dm); err != nil {
+		t.Fatal(err)
+	}
+	rs.clearUsage("CALL1") // call ended, still counted within the RateInterval
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org",
+		ID: "CALL2", Units: 1}, true, dm); err != utils.ErrResourceUnavailable {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrResourceUnavailable, err)
+	}
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org",
+		ID: "CALL1", Units: 1}, false, dm); err != nil { // retried attempt is not counted twice
+		t.Error(err)
+	} else if usage := r.totalUsage(); usage != 1 {
+		t.Errorf("Expecting: 1, received: %v", usage)
+	}
+	r.Usages["CALL1"].ExpiryTime = time.Now().Add(-time.Millisecond)
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org",
+		ID: "CALL2", Units: 1}, true, dm); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRSV1GetResourcesDetails(t *testing.T) {
+	data, _ := NewMapStorage()
+	dmDtls := NewDataManager(data)
+	rS := &ResourceService{dm: dmDtls}
+	if err := dmDtls.SetResourceProfile(&ResourceProfile{Tenant: "cgrates.org",
+		ID: "RES_DTLS", Limit: 5}, false); err != nil {
+		t.Fatal(err)
+	}
+	expTime := time.Now().Add(time.Hour)
+	if err := dmDtls.SetResource(&Resource{Tenant: "cgrates.org", ID: "RES_DTLS",
+		Usages: map[string]*ResourceUsage{
+			"RU_NOEXP":   &ResourceUsage{Tenant: "cgrates.org", ID: "RU_NOEXP", Units: 1},
+			"RU_EXP":     &ResourceUsage{Tenant: "cgrates.org", ID: "RU_EXP", Units: 2, ExpiryTime: expTime},
+			"RU_EXPIRED": &ResourceUsage{Tenant: "cgrates.org", ID: "RU_EXPIRED", Units: 3, ExpiryTime: time.Now().Add(-time.Hour)},
+		},
+		TTLIdx: []string{"RU_EXPIRED", "RU_EXP"}}); err != nil {
+		t.Fatal(err)
+	}
+	var rIDs []string
+	if err := rS.V1GetResourceIDs("cgrates.org", &rIDs); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"RES_DTLS"}, rIDs) {
+		t.Errorf("Unexpected resource IDs: %+v", rIDs)
+	}
+	var rDtls []*ResourceDetails
+	if err := rS.V1GetResourcesDetails(&ArgGetResourcesDetails{Tenant: "cgrates.org"}, &rDtls); err != nil {
+		t.Fatal(err)
+	}
+	if len(rDtls) != 1 {
+		t.Fatalf("Unexpected details: %s", utils.ToJSON(rDtls))
+	}
+	if rDtls[0].Limit != 5 || rDtls[0].TotalUsage != 3 ||
+		len(rDtls[0].Usages) != 2 ||
+		rDtls[0].Usages[0].ID != "RU_EXP" || rDtls[0].Usages[1].ID != "RU_NOEXP" {
+		t.Errorf("Unexpected details: %s", utils.ToJSON(rDtls))
+	}
+	if err := rS.V1GetResourcesDetails(&ArgGetResourcesDetails{Tenant: "cgrates.org",
+		ResourceIDs: []string{"RES_MISSING"}}, &rDtls); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
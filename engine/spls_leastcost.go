@@ -19,8 +19,6 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
-	"fmt"
-
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -29,38 +27,40 @@ func NewLeastCostSorter(spS *SupplierService) *LeastCostSorter {
 		sorting: utils.MetaLeastCost}
 }
 
+// NewHighestCostSorter returns a cost sorter ordering suppliers with the highest cost first
+func NewHighestCostSorter(spS *SupplierService) *LeastCostSorter {
+	return &LeastCostSorter{spS: spS,
+		sorting: utils.MetaHighestCost, descending: true}
+}
+
 // LeastCostSorter sorts suppliers based on their cost
 type LeastCostSorter struct {
-	sorting string
-	spS     *SupplierService
+	sorting    string
+	descending bool // highest cost first
+	spS        *SupplierService
 }
 
 func (lcs *LeastCostSorter) SortSuppliers(prflID string,
-	suppls []*Supplier, ev *utils.CGREvent) (sortedSuppls *SortedSuppliers, err error) {
+	suppls []*Supplier, ev *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
 	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
 		Sorting:         lcs.sorting,
 		SortedSuppliers: make([]*SortedSupplier, 0)}
 	for _, s := range suppls {
-		costData, err := lcs.spS.costForEvent(ev, s.AccountIDs, s.RatingPlanIDs)
+		srtData, err := lcs.spS.costSortingData(prflID, s, ev, extraOpts)
 		if err != nil {
 			return nil, err
-		} else if len(costData) == 0 {
-			utils.Logger.Warning(
-				fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, missing cost information",
-					utils.SupplierS, prflID, s.ID))
+		} else if srtData == nil {
 			continue
 		}
-		srtData := map[string]interface{}{
-			utils.Weight: s.Weight,
-		}
-		for k, v := range costData {
-			srtData[k] = v
-		}
 		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, &SortedSupplier{
 			SupplierID:         s.ID,
 			SortingData:        srtData,
 			SupplierParameters: s.SupplierParameters})
 	}
-	sortedSuppls.SortCost()
+	if lcs.descending {
+		sortedSuppls.SortHighestCost()
+	} else {
+		sortedSuppls.SortCost()
+	}
 	return
 }
@@ -0,0 +1,145 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewLeastUsedSorter(spS *SupplierService) *LeastUsedSorter {
+	return &LeastUsedSorter{spS: spS,
+		sorting: utils.MetaLeastUsed}
+}
+
+// LeastUsedSorter sorts suppliers based on their usage out of ResourceS
+type LeastUsedSorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+func (lus *LeastUsedSorter) SortSuppliers(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         lus.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0, len(suppls))}
+	for _, s := range suppls {
+		tUsage, err := lus.spS.supplierUsage(prflID, s, ev, extraOpts)
+		if err != nil {
+			return nil, err
+		} else if tUsage == nil {
+			continue
+		}
+		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, &SortedSupplier{
+			SupplierID: s.ID,
+			SortingData: map[string]interface{}{
+				utils.Weight:        s.Weight,
+				utils.ResourceUsage: *tUsage},
+			SupplierParameters: s.SupplierParameters})
+	}
+	sortedSuppls.SortResourceUsage()
+	return
+}
+
+func NewLoadDistributionSorter(spS *SupplierService) *LoadDistributionSorter {
+	return &LoadDistributionSorter{spS: spS,
+		sorting: utils.MetaLoadDistribution}
+}
+
+// LoadDistributionSorter sorts suppliers so their usage out of ResourceS
+// follows the ratios defined in each supplier's SupplierParameters (ie: 2),
+// suppliers without one using the profile default out of SortingParams (ie: *default:1)
+type LoadDistributionSorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+func (lds *LoadDistributionSorter) SortSuppliers(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
+	dfltRatio, err := loadDefaultRatio(extraOpts.sortingParameters)
+	if err != nil {
+		return nil, err
+	}
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         lds.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0, len(suppls))}
+	for _, s := range suppls {
+		ratio, err := loadRatio(s.SupplierParameters, dfltRatio)
+		if err != nil {
+			return nil, err
+		}
+		if ratio == 0 {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, ratio is 0",
+					utils.SupplierS, prflID, s.ID))
+			continue
+		}
+		tUsage, err := lds.spS.supplierUsage(prflID, s, ev, extraOpts)
+		if err != nil {
+			return nil, err
+		} else if tUsage == nil {
+			continue
+		}
+		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, &SortedSupplier{
+			SupplierID: s.ID,
+			SortingData: map[string]interface{}{
+				utils.Weight:        s.Weight,
+				utils.ResourceUsage: *tUsage,
+				utils.Ratio:         ratio,
+				utils.Load:          *tUsage / ratio},
+			SupplierParameters: s.SupplierParameters})
+	}
+	sortedSuppls.SortLoadDistribution()
+	return
+}
+
+// loadDefaultRatio parses the profile default ratio out of SortingParams in the form *default:<Ratio>, 1 if not defined
+func loadDefaultRatio(params []string) (dfltRatio float64, err error) {
+	dfltRatio = 1
+	for _, param := range params {
+		paramSplt := strings.Split(param, utils.InInFieldSep)
+		if len(paramSplt) != 2 || paramSplt[0] != utils.META_DEFAULT {
+			return 0, fmt.Errorf("invalid load distribution default ratio: <%s>", param)
+		}
+		if dfltRatio, err = parseLoadRatio(paramSplt[1]); err != nil {
+			return 0, err
+		}
+	}
+	return
+}
+
+// loadRatio returns the ratio defined in the SupplierParameters of the supplier, dfltRatio if empty
+func loadRatio(supplParams string, dfltRatio float64) (float64, error) {
+	if supplParams == "" {
+		return dfltRatio, nil
+	}
+	return parseLoadRatio(supplParams)
+}
+
+func parseLoadRatio(ratioStr string) (ratio float64, err error) {
+	if ratio, err = strconv.ParseFloat(ratioStr, 64); err != nil {
+		return 0, fmt.Errorf("invalid load distribution ratio: <%s>", ratioStr)
+	} else if ratio < 0 {
+		return 0, fmt.Errorf("negative load distribution ratio: <%s>", ratioStr)
+	}
+	return
+}
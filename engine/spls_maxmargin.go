@@ -0,0 +1,63 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewMaxMarginSorter(spS *SupplierService) *MaxMarginSorter {
+	return &MaxMarginSorter{spS: spS,
+		sorting: utils.MetaMaxMargin}
+}
+
+// MaxMarginSorter sorts suppliers based on the margin between
+// the cost of the event for it's account (sell) and the supplier cost (buy)
+type MaxMarginSorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+func (mms *MaxMarginSorter) SortSuppliers(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
+	sellCost, err := mms.spS.sellCostForEvent(ev)
+	if err != nil {
+		return nil, err
+	}
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         mms.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0)}
+	for _, s := range suppls {
+		srtData, err := mms.spS.costSortingData(prflID, s, ev, extraOpts)
+		if err != nil {
+			return nil, err
+		} else if srtData == nil {
+			continue
+		}
+		srtData[utils.SellCost] = sellCost
+		srtData[utils.Margin] = utils.Round(sellCost-srtData[utils.Cost].(float64),
+			globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, &SortedSupplier{
+			SupplierID:         s.ID,
+			SortingData:        srtData,
+			SupplierParameters: s.SupplierParameters})
+	}
+	sortedSuppls.SortMargin()
+	return
+}
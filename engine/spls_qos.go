@@ -0,0 +1,69 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewQOSSupplierSorter(spS *SupplierService) *QOSSupplierSorter {
+	return &QOSSupplierSorter{spS: spS,
+		sorting: utils.MetaQOS}
+}
+
+// QOSSupplierSorter sorts suppliers based on metrics out of their StatQueues
+type QOSSupplierSorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+func (qos *QOSSupplierSorter) SortSuppliers(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         qos.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0, len(suppls))}
+	for _, s := range suppls {
+		srtData := map[string]interface{}{
+			utils.Weight: s.Weight,
+		}
+		if len(s.StatIDs) != 0 {
+			metrics, err := qos.spS.statMetrics(s.StatIDs, ev.Tenant)
+			if err != nil {
+				if !extraOpts.ignoreErrors {
+					return nil, err
+				}
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, err: %s",
+						utils.SupplierS, prflID, s.ID, err.Error()))
+				continue
+			}
+			for metricID, val := range metrics {
+				srtData[metricID] = val
+			}
+		}
+		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, &SortedSupplier{
+			SupplierID:         s.ID,
+			SortingData:        srtData,
+			SupplierParameters: s.SupplierParameters})
+	}
+	sortedSuppls.SortQOS(extraOpts.sortingParameters)
+	return
+}
@@ -22,7 +22,11 @@ import (
 	"fmt"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
+	"math"
+	"math/rand"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -30,15 +34,21 @@ import (
 // cfg serves as general purpose container to pass config options to metric
 func NewStatMetric(metricID string, minItems int, extraParams string) (sm StatMetric, err error) {
 	metrics := map[string]func(int, string) (StatMetric, error){
-		utils.MetaASR:     NewASR,
-		utils.MetaACD:     NewACD,
-		utils.MetaTCD:     NewTCD,
-		utils.MetaACC:     NewACC,
-		utils.MetaTCC:     NewTCC,
-		utils.MetaPDD:     NewPDD,
-		utils.MetaDDC:     NewDCC,
-		utils.MetaSum:     NewStatSum,
-		utils.MetaAverage: NewStatAverage,
+		utils.MetaASR:           NewASR,
+		utils.MetaACD:           NewACD,
+		utils.MetaTCD:           NewTCD,
+		utils.MetaACC:           NewACC,
+		utils.MetaTCC:           NewTCC,
+		utils.MetaPDD:           NewPDD,
+		utils.MetaDDC:           NewDCC,
+		utils.MetaSum:           NewStatSum,
+		utils.MetaAverage:       NewStatAverage,
+		utils.MetaMax:           NewStatMax,
+		utils.MetaMin:           NewStatMin,
+		utils.MetaDistinctCount: NewStatDistinctCount,
+	}
+	if percentile, isPercentile := percentileFromMetricID(metricID); isPercentile {
+		return NewStatPercentile(minItems, extraParams, percentile)
 	}
 	if _, has := metrics[metricID]; !has {
 		return nil, fmt.Errorf("unsupported metric: %s", metricID)
@@ -46,6 +56,16 @@ func NewStatMetric(metricID string, minItems int, extraParams string) (sm StatMe
 	return metrics[metricID](minItems, extraParams)
 }
 
+// newStoredStatMetric instantiates an empty StatMetric to be populated out of its marshaled form,
+// the metric parameters being restored together with the data
+func newStoredStatMetric(metricID string, minItems int) (StatMetric, error) {
+	if metricID == utils.MetaDistinctCount {
+		return &StatDistinctCount{FieldValues: make(map[string]utils.StringMap),
+			Events: make(map[string]string), MinItems: minItems}, nil
+	}
+	return NewStatMetric(metricID, minItems, "")
+}
+
 // StatMetric is the interface which a metric should implement
 type StatMetric interface {
 	GetValue() interface{}
@@ -53,16 +73,64 @@ type StatMetric interface {
 	GetFloat64Value() (val float64)
 	AddEvent(ev *utils.CGREvent) error
 	RemEvent(evTenantID string) error
+	AddBucketEvent(ev *utils.CGREvent, bktID string) error
+	RemBucket(bktID string) error
 	Marshal(ms Marshaler) (marshaled []byte, err error)
 	LoadMarshaled(ms Marshaler, marshaled []byte) (err error)
 }
 
+// StatBucketValue aggregates the events of one bucket within a metric
+type StatBucketValue struct {
+	Count  int64           // number of events
+	Sum    float64         // sum of the event values
+	Values []float64       // event values, for metrics not computed out of Sum
+	Fields utils.StringMap // distinct field values
+}
+
+// StatBuckets aggregates the events per bucket instead of tracking each of them
+// used when the queue has a BucketInterval
+type StatBuckets struct {
+	Buckets map[string]*StatBucketValue // map[BucketID]*StatBucketValue
+}
+
+// bucket returns the value of bucket with bktID, opening it if not already there
+func (sb *StatBuckets) bucket(bktID string) (bkt *StatBucketValue) {
+	if sb.Buckets == nil {
+		sb.Buckets = make(map[string]*StatBucketValue)
+	}
+	bkt, has := sb.Buckets[bktID]
+	if !has {
+		bkt = new(StatBucketValue)
+		sb.Buckets[bktID] = bkt
+	}
+	return
+}
+
+// remBucket removes the bucket with bktID, returning its value
+func (sb *StatBuckets) remBucket(bktID string) (bkt *StatBucketValue, err error) {
+	bkt, has := sb.Buckets[bktID]
+	if !has {
+		return nil, utils.ErrNotFound
+	}
+	delete(sb.Buckets, bktID)
+	return
+}
+
+// bucketEvents returns the number of events aggregated within buckets
+func (sb *StatBuckets) bucketEvents() (nrEvents int) {
+	for _, bkt := range sb.Buckets {
+		nrEvents += int(bkt.Count)
+	}
+	return
+}
+
 func NewASR(minItems int, extraParams string) (StatMetric, error) {
 	return &StatASR{Events: make(map[string]bool), MinItems: minItems}, nil
 }
 
 // ASR implements AverageSuccessRatio metric
 type StatASR struct {
+	StatBuckets
 	Answered float64
 	Count    float64
 	Events   map[string]bool // map[EventTenantID]Answered
@@ -73,7 +141,7 @@ type StatASR struct {
 // getValue returns asr.val
 func (asr *StatASR) getValue() float64 {
 	if asr.val == nil {
-		if (asr.MinItems > 0 && len(asr.Events) < asr.MinItems) || (asr.Count == 0) {
+		if (asr.MinItems > 0 && len(asr.Events)+asr.bucketEvents() < asr.MinItems) || (asr.Count == 0) {
 			asr.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			asr.val = utils.Float64Pointer(utils.Round((asr.Answered / asr.Count * 100),
@@ -102,15 +170,23 @@ func (asr *StatASR) GetFloat64Value() (val float64) {
 	return asr.getValue()
 }
 
-// AddEvent is part of StatMetric interface
-func (asr *StatASR) AddEvent(ev *utils.CGREvent) (err error) {
-	var answered bool
+// asrEventAnswered checks if the event has AnswerTime
+func asrEventAnswered(ev *utils.CGREvent) (answered bool, err error) {
 	if at, err := ev.FieldAsTime(utils.AnswerTime, config.CgrConfig().DefaultTimezone); err != nil &&
 		err != utils.ErrNotFound {
-		return err
+		return false, err
 	} else if !at.IsZero() {
 		answered = true
 	}
+	return
+}
+
+// AddEvent is part of StatMetric interface
+func (asr *StatASR) AddEvent(ev *utils.CGREvent) (err error) {
+	answered, err := asrEventAnswered(ev)
+	if err != nil {
+		return
+	}
 	asr.Events[ev.TenantID()] = answered
 	asr.Count += 1
 	if answered {
@@ -134,6 +210,35 @@ func (asr *StatASR) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+// AddBucketEvent is part of StatMetric interface
+func (asr *StatASR) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	answered, err := asrEventAnswered(ev)
+	if err != nil {
+		return
+	}
+	bkt := asr.bucket(bktID)
+	bkt.Count++
+	asr.Count += 1
+	if answered {
+		bkt.Sum += 1
+		asr.Answered += 1
+	}
+	asr.val = nil
+	return
+}
+
+// RemBucket is part of StatMetric interface
+func (asr *StatASR) RemBucket(bktID string) (err error) {
+	bkt, err := asr.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	asr.Answered -= bkt.Sum
+	asr.Count -= float64(bkt.Count)
+	asr.val = nil
+	return
+}
+
 // Marshal is part of StatMetric interface
 func (asr *StatASR) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(asr)
@@ -150,6 +255,7 @@ func NewACD(minItems int, extraParams string) (StatMetric, error) {
 
 // ACD implements AverageCallDuration metric
 type StatACD struct {
+	StatBuckets
 	Sum      time.Duration
 	Count    int64
 	Events   map[string]time.Duration // map[EventTenantID]Duration
@@ -160,7 +266,7 @@ type StatACD struct {
 // getValue returns acr.val
 func (acd *StatACD) getValue() time.Duration {
 	if acd.val == nil {
-		if (acd.MinItems > 0 && len(acd.Events) < acd.MinItems) || (acd.Count == 0) {
+		if (acd.MinItems > 0 && len(acd.Events)+acd.bucketEvents() < acd.MinItems) || (acd.Count == 0) {
 			acd.val = utils.DurationPointer(time.Duration((-1) * time.Nanosecond))
 		} else {
 			acd.val = utils.DurationPointer(time.Duration(acd.Sum.Nanoseconds() / acd.Count))
@@ -191,19 +297,25 @@ func (acd *StatACD) GetFloat64Value() (v float64) {
 	return
 }
 
-func (acd *StatACD) AddEvent(ev *utils.CGREvent) (err error) {
-	var value time.Duration
+// answeredEventUsage returns the Usage of an answered event, 0 if not answered
+func answeredEventUsage(ev *utils.CGREvent) (usage time.Duration, err error) {
 	if at, err := ev.FieldAsTime(utils.AnswerTime, config.CgrConfig().DefaultTimezone); err != nil {
-		return err
+		return 0, err
 	} else if !at.IsZero() {
-		if duration, err := ev.FieldAsDuration(utils.Usage); err != nil &&
+		if usage, err = ev.FieldAsDuration(utils.Usage); err != nil &&
 			err != utils.ErrNotFound {
-			return err
-		} else {
-			value = duration
-			acd.Sum += duration
+			return 0, err
 		}
 	}
+	return usage, nil
+}
+
+func (acd *StatACD) AddEvent(ev *utils.CGREvent) (err error) {
+	value, err := answeredEventUsage(ev)
+	if err != nil {
+		return
+	}
+	acd.Sum += value
 	acd.Events[ev.TenantID()] = value
 	acd.Count += 1
 	acd.val = nil
@@ -224,6 +336,31 @@ func (acd *StatACD) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (acd *StatACD) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	value, err := answeredEventUsage(ev)
+	if err != nil {
+		return
+	}
+	bkt := acd.bucket(bktID)
+	bkt.Sum += float64(value)
+	bkt.Count++
+	acd.Sum += value
+	acd.Count += 1
+	acd.val = nil
+	return
+}
+
+func (acd *StatACD) RemBucket(bktID string) (err error) {
+	bkt, err := acd.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	acd.Sum -= time.Duration(bkt.Sum)
+	acd.Count -= bkt.Count
+	acd.val = nil
+	return
+}
+
 func (acd *StatACD) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(acd)
 }
@@ -237,6 +374,7 @@ func NewTCD(minItems int, extraParams string) (StatMetric, error) {
 
 // TCD implements TotalCallDuration metric
 type StatTCD struct {
+	StatBuckets
 	Sum      time.Duration
 	Count    int64
 	Events   map[string]time.Duration // map[EventTenantID]Duration
@@ -247,7 +385,7 @@ type StatTCD struct {
 // getValue returns tcd.val
 func (tcd *StatTCD) getValue() time.Duration {
 	if tcd.val == nil {
-		if (tcd.MinItems > 0 && len(tcd.Events) < tcd.MinItems) || (tcd.Count == 0) {
+		if (tcd.MinItems > 0 && len(tcd.Events)+tcd.bucketEvents() < tcd.MinItems) || (tcd.Count == 0) {
 			tcd.val = utils.DurationPointer(time.Duration((-1) * time.Nanosecond))
 		} else {
 			tcd.val = utils.DurationPointer(time.Duration(tcd.Sum.Nanoseconds()))
@@ -279,19 +417,11 @@ func (tcd *StatTCD) GetFloat64Value() (v float64) {
 }
 
 func (tcd *StatTCD) AddEvent(ev *utils.CGREvent) (err error) {
-	var value time.Duration
-	if at, err := ev.FieldAsTime(utils.AnswerTime, config.CgrConfig().DefaultTimezone); err != nil {
-		return err
-	} else if !at.IsZero() {
-		if duration, err := ev.FieldAsDuration(utils.Usage); err != nil &&
-			err != utils.ErrNotFound {
-			return err
-		} else {
-			value = duration
-			tcd.Sum += duration
-		}
-
+	value, err := answeredEventUsage(ev)
+	if err != nil {
+		return
 	}
+	tcd.Sum += value
 	tcd.Events[ev.TenantID()] = value
 	tcd.Count += 1
 	tcd.val = nil
@@ -312,6 +442,31 @@ func (tcd *StatTCD) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (tcd *StatTCD) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	value, err := answeredEventUsage(ev)
+	if err != nil {
+		return
+	}
+	bkt := tcd.bucket(bktID)
+	bkt.Sum += float64(value)
+	bkt.Count++
+	tcd.Sum += value
+	tcd.Count += 1
+	tcd.val = nil
+	return
+}
+
+func (tcd *StatTCD) RemBucket(bktID string) (err error) {
+	bkt, err := tcd.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	tcd.Sum -= time.Duration(bkt.Sum)
+	tcd.Count -= bkt.Count
+	tcd.val = nil
+	return
+}
+
 func (tcd *StatTCD) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(tcd)
 }
@@ -326,6 +481,7 @@ func NewACC(minItems int, extraParams string) (StatMetric, error) {
 
 // ACC implements AverageCallCost metric
 type StatACC struct {
+	StatBuckets
 	Sum      float64
 	Count    float64
 	Events   map[string]float64 // map[EventTenantID]Cost
@@ -336,7 +492,7 @@ type StatACC struct {
 // getValue returns tcd.val
 func (acc *StatACC) getValue() float64 {
 	if acc.val == nil {
-		if (acc.MinItems > 0 && len(acc.Events) < acc.MinItems) || (acc.Count == 0) {
+		if (acc.MinItems > 0 && len(acc.Events)+acc.bucketEvents() < acc.MinItems) || (acc.Count == 0) {
 			acc.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			acc.val = utils.Float64Pointer(utils.Round((acc.Sum / acc.Count),
@@ -364,19 +520,27 @@ func (acc *StatACC) GetFloat64Value() (v float64) {
 	return acc.getValue()
 }
 
-func (acc *StatACC) AddEvent(ev *utils.CGREvent) (err error) {
-	var value float64
+// answeredEventCost returns the Cost of an answered event, 0 if not answered
+func answeredEventCost(ev *utils.CGREvent) (cost float64, err error) {
 	if at, err := ev.FieldAsTime(utils.AnswerTime, config.CgrConfig().DefaultTimezone); err != nil {
-		return err
+		return 0, err
 	} else if !at.IsZero() {
-		if cost, err := ev.FieldAsFloat64(utils.COST); err != nil &&
+		if cost, err = ev.FieldAsFloat64(utils.COST); err != nil &&
 			err != utils.ErrNotFound {
-			return err
-		} else if cost >= 0 {
-			value = cost
-			acc.Sum += cost
+			return 0, err
+		} else if cost < 0 {
+			cost = 0
 		}
 	}
+	return cost, nil
+}
+
+func (acc *StatACC) AddEvent(ev *utils.CGREvent) (err error) {
+	value, err := answeredEventCost(ev)
+	if err != nil {
+		return
+	}
+	acc.Sum += value
 	acc.Events[ev.TenantID()] = value
 	acc.Count += 1
 	acc.val = nil
@@ -397,6 +561,31 @@ func (acc *StatACC) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (acc *StatACC) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	value, err := answeredEventCost(ev)
+	if err != nil {
+		return
+	}
+	bkt := acc.bucket(bktID)
+	bkt.Sum += value
+	bkt.Count++
+	acc.Sum += value
+	acc.Count += 1
+	acc.val = nil
+	return
+}
+
+func (acc *StatACC) RemBucket(bktID string) (err error) {
+	bkt, err := acc.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	acc.Sum -= bkt.Sum
+	acc.Count -= float64(bkt.Count)
+	acc.val = nil
+	return
+}
+
 func (acc *StatACC) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(acc)
 }
@@ -411,6 +600,7 @@ func NewTCC(minItems int, extraParams string) (StatMetric, error) {
 
 // TCC implements TotalCallCost metric
 type StatTCC struct {
+	StatBuckets
 	Sum      float64
 	Count    float64
 	Events   map[string]float64 // map[EventTenantID]Cost
@@ -421,7 +611,7 @@ type StatTCC struct {
 // getValue returns tcd.val
 func (tcc *StatTCC) getValue() float64 {
 	if tcc.val == nil {
-		if (tcc.MinItems > 0 && len(tcc.Events) < tcc.MinItems) || (tcc.Count == 0) {
+		if (tcc.MinItems > 0 && len(tcc.Events)+tcc.bucketEvents() < tcc.MinItems) || (tcc.Count == 0) {
 			tcc.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			tcc.val = utils.Float64Pointer(utils.Round(tcc.Sum,
@@ -449,18 +639,11 @@ func (tcc *StatTCC) GetFloat64Value() (v float64) {
 }
 
 func (tcc *StatTCC) AddEvent(ev *utils.CGREvent) (err error) {
-	var value float64
-	if at, err := ev.FieldAsTime(utils.AnswerTime, config.CgrConfig().DefaultTimezone); err != nil {
-		return err
-	} else if !at.IsZero() {
-		if cost, err := ev.FieldAsFloat64(utils.COST); err != nil &&
-			err != utils.ErrNotFound {
-			return err
-		} else if cost >= 0 {
-			value = cost
-			tcc.Sum += cost
-		}
+	value, err := answeredEventCost(ev)
+	if err != nil {
+		return
 	}
+	tcc.Sum += value
 	tcc.Events[ev.TenantID()] = value
 	tcc.Count += 1
 	tcc.val = nil
@@ -481,6 +664,31 @@ func (tcc *StatTCC) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (tcc *StatTCC) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	value, err := answeredEventCost(ev)
+	if err != nil {
+		return
+	}
+	bkt := tcc.bucket(bktID)
+	bkt.Sum += value
+	bkt.Count++
+	tcc.Sum += value
+	tcc.Count += 1
+	tcc.val = nil
+	return
+}
+
+func (tcc *StatTCC) RemBucket(bktID string) (err error) {
+	bkt, err := tcc.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	tcc.Sum -= bkt.Sum
+	tcc.Count -= float64(bkt.Count)
+	tcc.val = nil
+	return
+}
+
 func (tcc *StatTCC) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(tcc)
 }
@@ -495,6 +703,7 @@ func NewPDD(minItems int, extraParams string) (StatMetric, error) {
 
 // PDD implements Post Dial Delay (average) metric
 type StatPDD struct {
+	StatBuckets
 	Sum      time.Duration
 	Count    int64
 	Events   map[string]time.Duration // map[EventTenantID]Duration
@@ -505,7 +714,7 @@ type StatPDD struct {
 // getValue returns pdd.val
 func (pdd *StatPDD) getValue() time.Duration {
 	if pdd.val == nil {
-		if (pdd.MinItems > 0 && len(pdd.Events) < pdd.MinItems) || (pdd.Count == 0) {
+		if (pdd.MinItems > 0 && len(pdd.Events)+pdd.bucketEvents() < pdd.MinItems) || (pdd.Count == 0) {
 			pdd.val = utils.DurationPointer(time.Duration((-1) * time.Nanosecond))
 		} else {
 			pdd.val = utils.DurationPointer(time.Duration(pdd.Sum.Nanoseconds() / pdd.Count))
@@ -536,20 +745,26 @@ func (pdd *StatPDD) GetFloat64Value() (v float64) {
 	return
 }
 
-func (pdd *StatPDD) AddEvent(ev *utils.CGREvent) (err error) {
-	var value time.Duration
+// answeredEventPDD returns the PDD of an answered event, 0 if not answered
+func answeredEventPDD(ev *utils.CGREvent) (pdd time.Duration, err error) {
 	if at, err := ev.FieldAsTime(utils.AnswerTime, config.CgrConfig().DefaultTimezone); err != nil &&
 		err != utils.ErrNotFound {
-		return err
+		return 0, err
 	} else if !at.IsZero() {
-		if duration, err := ev.FieldAsDuration(utils.PDD); err != nil &&
+		if pdd, err = ev.FieldAsDuration(utils.PDD); err != nil &&
 			err != utils.ErrNotFound {
-			return err
-		} else {
-			value = duration
-			pdd.Sum += duration
+			return 0, err
 		}
 	}
+	return pdd, nil
+}
+
+func (pdd *StatPDD) AddEvent(ev *utils.CGREvent) (err error) {
+	value, err := answeredEventPDD(ev)
+	if err != nil {
+		return
+	}
+	pdd.Sum += value
 	pdd.Events[ev.TenantID()] = value
 	pdd.Count += 1
 	pdd.val = nil
@@ -570,6 +785,31 @@ func (pdd *StatPDD) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (pdd *StatPDD) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	value, err := answeredEventPDD(ev)
+	if err != nil {
+		return
+	}
+	bkt := pdd.bucket(bktID)
+	bkt.Sum += float64(value)
+	bkt.Count++
+	pdd.Sum += value
+	pdd.Count += 1
+	pdd.val = nil
+	return
+}
+
+func (pdd *StatPDD) RemBucket(bktID string) (err error) {
+	bkt, err := pdd.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	pdd.Sum -= time.Duration(bkt.Sum)
+	pdd.Count -= bkt.Count
+	pdd.val = nil
+	return
+}
+
 func (pdd *StatPDD) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(pdd)
 }
@@ -583,13 +823,14 @@ func NewDCC(minItems int, extraParams string) (StatMetric, error) {
 
 // DDC implements Destination Distinct Count metric
 type StatDDC struct {
+	StatBuckets
 	Destinations map[string]utils.StringMap
 	Events       map[string]string // map[EventTenantID]Destination
 	MinItems     int
 }
 
 func (ddc *StatDDC) GetStringValue(fmtOpts string) (valStr string) {
-	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events) < ddc.MinItems) {
+	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events)+ddc.bucketEvents() < ddc.MinItems) {
 		valStr = utils.NOT_AVAILABLE
 	} else {
 		valStr = fmt.Sprintf("%+v", len(ddc.Destinations))
@@ -602,7 +843,7 @@ func (ddc *StatDDC) GetValue() (v interface{}) {
 }
 
 func (ddc *StatDDC) GetFloat64Value() (v float64) {
-	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events) < ddc.MinItems) {
+	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events)+ddc.bucketEvents() < ddc.MinItems) {
 		v = -1.0
 	} else {
 		v = float64(len(ddc.Destinations))
@@ -637,6 +878,38 @@ func (ddc *StatDDC) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (ddc *StatDDC) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	var dest string
+	if dest, err = ev.FieldAsString(utils.Destination); err != nil {
+		return err
+	}
+	if _, has := ddc.Destinations[dest]; !has {
+		ddc.Destinations[dest] = make(map[string]bool)
+	}
+	ddc.Destinations[dest][bktID] = true
+	bkt := ddc.bucket(bktID)
+	if bkt.Fields == nil {
+		bkt.Fields = make(utils.StringMap)
+	}
+	bkt.Fields[dest] = true
+	bkt.Count++
+	return
+}
+
+func (ddc *StatDDC) RemBucket(bktID string) (err error) {
+	bkt, err := ddc.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	for dest := range bkt.Fields {
+		delete(ddc.Destinations[dest], bktID)
+		if len(ddc.Destinations[dest]) == 0 {
+			delete(ddc.Destinations, dest)
+		}
+	}
+	return
+}
+
 func (ddc *StatDDC) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(DDC)
 }
@@ -648,6 +921,7 @@ func NewStatSum(minItems int, extraParams string) (StatMetric, error) {
 }
 
 type StatSum struct {
+	StatBuckets
 	Sum       float64
 	Events    map[string]float64 // map[EventTenantID]Cost
 	MinItems  int
@@ -658,7 +932,7 @@ type StatSum struct {
 // getValue returns tcd.val
 func (sum *StatSum) getValue() float64 {
 	if sum.val == nil {
-		if len(sum.Events) == 0 || len(sum.Events) < sum.MinItems {
+		if nrEvents := len(sum.Events) + sum.bucketEvents(); nrEvents == 0 || nrEvents < sum.MinItems {
 			sum.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			sum.val = utils.Float64Pointer(utils.Round(sum.Sum,
@@ -712,6 +986,31 @@ func (sum *StatSum) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (sum *StatSum) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	val, err := ev.FieldAsFloat64(sum.FieldName)
+	if err != nil && err != utils.ErrNotFound {
+		return
+	}
+	bkt := sum.bucket(bktID)
+	if val >= 0 {
+		bkt.Sum += val
+		sum.Sum += val
+	}
+	bkt.Count++
+	sum.val = nil
+	return nil
+}
+
+func (sum *StatSum) RemBucket(bktID string) (err error) {
+	bkt, err := sum.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	sum.Sum -= bkt.Sum
+	sum.val = nil
+	return
+}
+
 func (sum *StatSum) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(sum)
 }
@@ -726,6 +1025,7 @@ func NewStatAverage(minItems int, extraParams string) (StatMetric, error) {
 
 // StatAverage implements TotalCallCost metric
 type StatAverage struct {
+	StatBuckets
 	Sum       float64
 	Count     float64
 	Events    map[string]float64 // map[EventTenantID]Cost
@@ -737,7 +1037,7 @@ type StatAverage struct {
 // getValue returns tcd.val
 func (avg *StatAverage) getValue() float64 {
 	if avg.val == nil {
-		if (avg.MinItems > 0 && len(avg.Events) < avg.MinItems) || (avg.Count == 0) {
+		if (avg.MinItems > 0 && len(avg.Events)+avg.bucketEvents() < avg.MinItems) || (avg.Count == 0) {
 			avg.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			avg.val = utils.Float64Pointer(utils.Round((avg.Sum / avg.Count),
@@ -794,6 +1094,33 @@ func (avg *StatAverage) RemEvent(evTenantID string) (err error) {
 	return
 }
 
+func (avg *StatAverage) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	val, err := ev.FieldAsFloat64(avg.FieldName)
+	if err != nil && err != utils.ErrNotFound {
+		return
+	}
+	if val > 0 {
+		bkt := avg.bucket(bktID)
+		bkt.Sum += val
+		bkt.Count++
+		avg.Sum += val
+		avg.Count += 1
+		avg.val = nil
+	}
+	return nil
+}
+
+func (avg *StatAverage) RemBucket(bktID string) (err error) {
+	bkt, err := avg.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	avg.Sum -= bkt.Sum
+	avg.Count -= float64(bkt.Count)
+	avg.val = nil
+	return
+}
+
 func (avg *StatAverage) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(avg)
 }
@@ -801,3 +1128,353 @@ func (avg *StatAverage) Marshal(ms Marshaler) (marshaled []byte, err error) {
 func (avg *StatAverage) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
 	return ms.Unmarshal(marshaled, avg)
 }
+
+// percentileFromMetricID returns the percentile out of metric IDs like *p95
+func percentileFromMetricID(metricID string) (percentile float64, isPercentile bool) {
+	if !strings.HasPrefix(metricID, utils.MetaPercentile) {
+		return
+	}
+	percentile, err := strconv.ParseFloat(metricID[len(utils.MetaPercentile):], 64)
+	if err != nil || percentile <= 0 || percentile > 100 {
+		return 0, false
+	}
+	return percentile, true
+}
+
+// statFieldAsFloat64 returns the value of an event field as float64
+// Usage and PDD are always read as durations and converted to seconds, as ACD and PDD metrics do
+func statFieldAsFloat64(ev *utils.CGREvent, fldName string) (val float64, err error) {
+	if fldName != utils.Usage && fldName != utils.PDD {
+		return ev.FieldAsFloat64(fldName)
+	}
+	dur, err := ev.FieldAsDuration(fldName)
+	if err != nil {
+		return
+	}
+	return dur.Seconds(), nil
+}
+
+// statFieldName returns the field the metric is computed on, Usage by default
+func statFieldName(extraParams string) string {
+	if extraParams == "" {
+		return utils.Usage
+	}
+	return extraParams
+}
+
+// statMetricNA checks if there are too few events to compute the metric
+func statMetricNA(nrEvents, minItems int) bool {
+	return nrEvents == 0 || nrEvents < minItems
+}
+
+// statMetricStringValue formats the value of a metric, STATS_NA as N/A
+func statMetricStringValue(val float64) string {
+	if val == STATS_NA {
+		return utils.NOT_AVAILABLE
+	}
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+// statBucketMaxValues limits the raw values kept per bucket by the metrics which cannot reduce them (ie: percentiles)
+// above it the bucket keeps a uniform sample of its events so the memory stays bounded for long BucketIntervals
+const statBucketMaxValues = 1000
+
+// StatFieldValues is the common part of the metrics computed out of the values of FieldName
+type StatFieldValues struct {
+	StatBuckets
+	Events    map[string]float64 // map[EventTenantID]Value
+	MinItems  int
+	FieldName string
+	val       *float64                     // cached metric value
+	compute   func(vals []float64) float64 // computes the metric out of the values
+	reduce    bool                         // the values of a bucket can be replaced by the computed one
+}
+
+// newStatFieldValues instantiates StatFieldValues with the function computing the metric
+func newStatFieldValues(minItems int, extraParams string,
+	compute func(vals []float64) float64, reduce bool) StatFieldValues {
+	return StatFieldValues{Events: make(map[string]float64), MinItems: minItems,
+		FieldName: statFieldName(extraParams), compute: compute, reduce: reduce}
+}
+
+// getValue returns sfv.val
+func (sfv *StatFieldValues) getValue() float64 {
+	if sfv.val == nil {
+		if statMetricNA(len(sfv.Events)+sfv.bucketEvents(), sfv.MinItems) {
+			sfv.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			vals := make([]float64, 0, len(sfv.Events))
+			for _, val := range sfv.Events {
+				vals = append(vals, val)
+			}
+			for _, bkt := range sfv.Buckets {
+				vals = append(vals, bkt.Values...)
+			}
+			sfv.val = utils.Float64Pointer(utils.Round(sfv.compute(vals),
+				config.CgrConfig().RoundingDecimals, utils.ROUNDING_MIDDLE))
+		}
+	}
+	return *sfv.val
+}
+
+func (sfv *StatFieldValues) GetStringValue(fmtOpts string) (valStr string) {
+	return statMetricStringValue(sfv.getValue())
+}
+
+func (sfv *StatFieldValues) GetValue() (v interface{}) {
+	return sfv.getValue()
+}
+
+func (sfv *StatFieldValues) GetFloat64Value() (v float64) {
+	return sfv.getValue()
+}
+
+func (sfv *StatFieldValues) AddEvent(ev *utils.CGREvent) (err error) {
+	val, err := statFieldAsFloat64(ev, sfv.FieldName)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	sfv.Events[ev.TenantID()] = val
+	sfv.val = nil
+	return
+}
+
+func (sfv *StatFieldValues) RemEvent(evTenantID string) (err error) {
+	if _, has := sfv.Events[evTenantID]; !has {
+		return utils.ErrNotFound
+	}
+	delete(sfv.Events, evTenantID)
+	sfv.val = nil
+	return
+}
+
+func (sfv *StatFieldValues) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	val, err := statFieldAsFloat64(ev, sfv.FieldName)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	bkt := sfv.bucket(bktID)
+	switch {
+	case sfv.reduce:
+		bkt.Values = []float64{sfv.compute(append(bkt.Values, val))}
+	case len(bkt.Values) < statBucketMaxValues:
+		bkt.Values = append(bkt.Values, val)
+	default: // bucket full, replace values so each event of the bucket has the same chance to be kept (reservoir sampling)
+		if idx := rand.Int63n(bkt.Count + 1); idx < statBucketMaxValues {
+			bkt.Values[idx] = val
+		}
+	}
+	bkt.Count++
+	sfv.val = nil
+	return
+}
+
+func (sfv *StatFieldValues) RemBucket(bktID string) (err error) {
+	if _, err = sfv.remBucket(bktID); err != nil {
+		return
+	}
+	sfv.val = nil
+	return
+}
+
+func NewStatPercentile(minItems int, extraParams string, percentile float64) (StatMetric, error) {
+	pct := &StatPercentile{Percentile: percentile}
+	pct.StatFieldValues = newStatFieldValues(minItems, extraParams, pct.computePercentile, false)
+	return pct, nil
+}
+
+// StatPercentile implements the percentile (nearest-rank) metric, ie: *p95
+type StatPercentile struct {
+	StatFieldValues
+	Percentile float64
+}
+
+// computePercentile returns the value with the nearest rank to Percentile
+func (pct *StatPercentile) computePercentile(vals []float64) float64 {
+	sort.Float64s(vals)
+	rank := int(math.Ceil(pct.Percentile / 100 * float64(len(vals))))
+	if rank < 1 {
+		rank = 1
+	}
+	return vals[rank-1]
+}
+
+func (pct *StatPercentile) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(pct)
+}
+
+func (pct *StatPercentile) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, pct)
+}
+
+func NewStatMax(minItems int, extraParams string) (StatMetric, error) {
+	return &StatMax{newStatFieldValues(minItems, extraParams, maxFloat64, true)}, nil
+}
+
+// StatMax implements the maximum value metric
+type StatMax struct {
+	StatFieldValues
+}
+
+// maxFloat64 returns the highest of the values
+func maxFloat64(vals []float64) float64 {
+	maxVal := math.Inf(-1)
+	for _, val := range vals {
+		if val > maxVal {
+			maxVal = val
+		}
+	}
+	return maxVal
+}
+
+func (mx *StatMax) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(mx)
+}
+
+func (mx *StatMax) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, mx)
+}
+
+func NewStatMin(minItems int, extraParams string) (StatMetric, error) {
+	return &StatMin{newStatFieldValues(minItems, extraParams, minFloat64, true)}, nil
+}
+
+// StatMin implements the minimum value metric
+type StatMin struct {
+	StatFieldValues
+}
+
+// minFloat64 returns the lowest of the values
+func minFloat64(vals []float64) float64 {
+	minVal := math.Inf(1)
+	for _, val := range vals {
+		if val < minVal {
+			minVal = val
+		}
+	}
+	return minVal
+}
+
+func (mn *StatMin) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(mn)
+}
+
+func (mn *StatMin) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, mn)
+}
+
+func NewStatDistinctCount(minItems int, extraParams string) (StatMetric, error) {
+	if extraParams == "" {
+		return nil, utils.NewErrMandatoryIeMissing("FieldName")
+	}
+	return &StatDistinctCount{FieldValues: make(map[string]utils.StringMap),
+		Events: make(map[string]string), MinItems: minItems, FieldName: extraParams}, nil
+}
+
+// StatDistinctCount implements the distinct count of values for FieldName
+type StatDistinctCount struct {
+	StatBuckets
+	FieldValues map[string]utils.StringMap // map[FieldValue]map[EventTenantID]bool
+	Events      map[string]string          // map[EventTenantID]FieldValue
+	MinItems    int
+	FieldName   string
+}
+
+// getValue returns the number of distinct values or STATS_NA
+func (dc *StatDistinctCount) getValue() float64 {
+	if statMetricNA(len(dc.Events)+dc.bucketEvents(), dc.MinItems) {
+		return STATS_NA
+	}
+	return float64(len(dc.FieldValues))
+}
+
+func (dc *StatDistinctCount) GetStringValue(fmtOpts string) (valStr string) {
+	return statMetricStringValue(dc.getValue())
+}
+
+func (dc *StatDistinctCount) GetValue() (v interface{}) {
+	return dc.getValue()
+}
+
+func (dc *StatDistinctCount) GetFloat64Value() (v float64) {
+	return dc.getValue()
+}
+
+func (dc *StatDistinctCount) AddEvent(ev *utils.CGREvent) (err error) {
+	fldVal, err := ev.FieldAsString(dc.FieldName)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	if _, has := dc.FieldValues[fldVal]; !has {
+		dc.FieldValues[fldVal] = make(utils.StringMap)
+	}
+	dc.FieldValues[fldVal][ev.TenantID()] = true
+	dc.Events[ev.TenantID()] = fldVal
+	return
+}
+
+func (dc *StatDistinctCount) RemEvent(evTenantID string) (err error) {
+	fldVal, has := dc.Events[evTenantID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	delete(dc.Events, evTenantID)
+	if len(dc.FieldValues[fldVal]) == 1 {
+		delete(dc.FieldValues, fldVal)
+		return
+	}
+	delete(dc.FieldValues[fldVal], evTenantID)
+	return
+}
+
+func (dc *StatDistinctCount) AddBucketEvent(ev *utils.CGREvent, bktID string) (err error) {
+	fldVal, err := ev.FieldAsString(dc.FieldName)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	if _, has := dc.FieldValues[fldVal]; !has {
+		dc.FieldValues[fldVal] = make(utils.StringMap)
+	}
+	dc.FieldValues[fldVal][bktID] = true
+	bkt := dc.bucket(bktID)
+	if bkt.Fields == nil {
+		bkt.Fields = make(utils.StringMap)
+	}
+	bkt.Fields[fldVal] = true
+	bkt.Count++
+	return
+}
+
+func (dc *StatDistinctCount) RemBucket(bktID string) (err error) {
+	bkt, err := dc.remBucket(bktID)
+	if err != nil {
+		return
+	}
+	for fldVal := range bkt.Fields {
+		delete(dc.FieldValues[fldVal], bktID)
+		if len(dc.FieldValues[fldVal]) == 0 {
+			delete(dc.FieldValues, fldVal)
+		}
+	}
+	return
+}
+
+func (dc *StatDistinctCount) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(dc)
+}
+
+func (dc *StatDistinctCount) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, dc)
+}
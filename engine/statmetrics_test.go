@@ -18,6 +18,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"strconv"
 	"testing"
 	"time"
 
@@ -1150,3 +1151,228 @@ func TestStatAverageGetStringValue(t *testing.T) {
 		t.Errorf("wrong statAvg value: %s", strVal)
 	}
 }
+
+func TestStatPercentileGetFloat64Value(t *testing.T) {
+	pct, err := NewStatMetric("*p90", 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStatMetric("*p101", 2, ""); err == nil {
+		t.Error("Expecting error")
+	}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{
+			utils.Usage: time.Duration(10 * time.Second)}}
+	pct.AddEvent(ev)
+	if v := pct.GetFloat64Value(); v != -1.0 {
+		t.Errorf("wrong percentile value: %v", v)
+	}
+	for i, usage := range []string{"1s", "2s", "3s", "4s", "5s", "6s", "7s", "8s", "9s"} {
+		pct.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i+2),
+			Event: map[string]interface{}{utils.Usage: usage}})
+	}
+	pct.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_NO_USAGE"})
+	if v := pct.GetFloat64Value(); v != 9.0 {
+		t.Errorf("wrong percentile value: %v", v)
+	}
+	pct.RemEvent(ev.TenantID())
+	if strVal := pct.GetStringValue(""); strVal != "9" {
+		t.Errorf("wrong percentile value: %s", strVal)
+	}
+	if err := pct.RemEvent("cgrates.org:EVENT_NO_USAGE"); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	p50, _ := NewStatPercentile(0, "Cost", 50)
+	for i, cost := range []float64{0.1, 0.5, 0.3, 0.2} {
+		p50.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i+1),
+			Event: map[string]interface{}{"Cost": cost}})
+	}
+	if v := p50.GetFloat64Value(); v != 0.2 {
+		t.Errorf("wrong percentile value: %v", v)
+	}
+}
+
+func TestStatPercentileBucketValuesCap(t *testing.T) {
+	p50, _ := NewStatPercentile(0, "Cost", 50)
+	for i := 0; i < 2*statBucketMaxValues; i++ {
+		p50.AddBucketEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+			Event: map[string]interface{}{"Cost": 1.0}}, "BUCKET_1")
+	}
+	if bkt := p50.(*StatPercentile).Buckets["BUCKET_1"]; bkt.Count != 2*statBucketMaxValues {
+		t.Errorf("unexpected count: %d", bkt.Count)
+	} else if len(bkt.Values) != statBucketMaxValues {
+		t.Errorf("unexpected values kept: %d", len(bkt.Values))
+	}
+	if v := p50.GetFloat64Value(); v != 1.0 {
+		t.Errorf("wrong percentile value: %v", v)
+	}
+}
+
+func TestStatMaxMinGetFloat64Value(t *testing.T) {
+	statMax, _ := NewStatMax(2, "Cost")
+	statMin, _ := NewStatMin(2, "Cost")
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{"Cost": "12.3"}}
+	ev2 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_2",
+		Event: map[string]interface{}{"Cost": 1.2}}
+	ev3 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_3",
+		Event: map[string]interface{}{"Cost": "5.6"}}
+	for _, metric := range []StatMetric{statMax, statMin} {
+		metric.AddEvent(ev)
+		if v := metric.GetFloat64Value(); v != -1.0 {
+			t.Errorf("wrong value: %v", v)
+		}
+		metric.AddEvent(ev2)
+		metric.AddEvent(ev3)
+	}
+	if v := statMax.GetFloat64Value(); v != 12.3 {
+		t.Errorf("wrong max value: %v", v)
+	}
+	if v := statMin.GetFloat64Value(); v != 1.2 {
+		t.Errorf("wrong min value: %v", v)
+	}
+	statMax.RemEvent(ev.TenantID())
+	statMin.RemEvent(ev2.TenantID())
+	if strVal := statMax.GetStringValue(""); strVal != "5.6" {
+		t.Errorf("wrong max value: %s", strVal)
+	}
+	if strVal := statMin.GetStringValue(""); strVal != "5.6" {
+		t.Errorf("wrong min value: %s", strVal)
+	}
+	statMax.RemEvent(ev2.TenantID())
+	if strVal := statMax.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+		t.Errorf("wrong max value: %s", strVal)
+	}
+}
+
+func TestStatDistinctCountGetFloat64Value(t *testing.T) {
+	dc, _ := NewStatDistinctCount(2, utils.Account)
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{utils.Account: "1001"}}
+	ev2 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_2",
+		Event: map[string]interface{}{utils.Account: "1001"}}
+	ev3 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_3",
+		Event: map[string]interface{}{utils.Account: "1002"}}
+	dc.AddEvent(ev)
+	if v := dc.GetFloat64Value(); v != -1.0 {
+		t.Errorf("wrong distinct count value: %v", v)
+	}
+	dc.AddEvent(ev2)
+	dc.AddEvent(ev3)
+	if v := dc.GetFloat64Value(); v != 2.0 {
+		t.Errorf("wrong distinct count value: %v", v)
+	}
+	dc.RemEvent(ev.TenantID())
+	if strVal := dc.GetStringValue(""); strVal != "2" {
+		t.Errorf("wrong distinct count value: %s", strVal)
+	}
+	dc.RemEvent(ev3.TenantID())
+	if strVal := dc.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+		t.Errorf("wrong distinct count value: %s", strVal)
+	} else if v := dc.GetValue(); v != -1.0 {
+		t.Errorf("wrong distinct count value: %v", v)
+	}
+	if _, err := NewStatDistinctCount(2, ""); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing("FieldName").Error() {
+		t.Errorf("Received: %v", err)
+	}
+}
+
+func TestStatFieldAsFloat64(t *testing.T) {
+	for _, usage := range []interface{}{time.Duration(10 * time.Second), "10s",
+		float64(10 * time.Second), "10000000000"} {
+		ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+			Event: map[string]interface{}{utils.Usage: usage, utils.PDD: usage}}
+		for _, fldName := range []string{utils.Usage, utils.PDD} {
+			if val, err := statFieldAsFloat64(ev, fldName); err != nil {
+				t.Error(err)
+			} else if val != 10.0 {
+				t.Errorf("%s %v received: %v", fldName, usage, val)
+			}
+		}
+	}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{"Cost": "10", "Duration": time.Duration(10 * time.Second)}}
+	if val, err := statFieldAsFloat64(ev, "Cost"); err != nil {
+		t.Error(err)
+	} else if val != 10.0 {
+		t.Errorf("received: %v", val)
+	}
+	if _, err := statFieldAsFloat64(ev, "Duration"); err == nil {
+		t.Error("Expecting error")
+	}
+}
+
+func TestStatMetricsMarshal(t *testing.T) {
+	ms := NewCodecMsgpackMarshaler()
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{
+			utils.Usage:   time.Duration(10 * time.Second),
+			utils.Account: "1001"}}
+	for _, metricID := range []string{"*p95", utils.MetaMax, utils.MetaMin, utils.MetaDistinctCount} {
+		params := ""
+		if metricID == utils.MetaDistinctCount {
+			params = utils.Account
+		}
+		metric, err := NewStatMetric(metricID, 0, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		metric.AddEvent(ev)
+		marshaled, err := metric.Marshal(ms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rcv, _ := newStoredStatMetric(metricID, 0)
+		if err := rcv.LoadMarshaled(ms, marshaled); err != nil {
+			t.Error(err)
+		} else if rcv.GetStringValue("") != metric.GetStringValue("") {
+			t.Errorf("%s expecting: %s, received: %s", metricID, metric.GetStringValue(""), rcv.GetStringValue(""))
+		}
+	}
+}
+
+func TestStatMetricsBuckets(t *testing.T) {
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{
+			utils.AnswerTime:  time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+			utils.Usage:       time.Duration(10 * time.Second),
+			utils.COST:        "1.5",
+			utils.Destination: "1002"}}
+	ev2 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_2",
+		Event: map[string]interface{}{
+			utils.AnswerTime:  time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+			utils.Usage:       time.Duration(20 * time.Second),
+			utils.COST:        "2.5",
+			utils.Destination: "1003"}}
+	for metricID, eVals := range map[string][]string{
+		utils.MetaACD:           []string{"15s", "20s"},
+		utils.MetaTCD:           []string{"30s", "20s"},
+		utils.MetaACC:           []string{"2", "2.5"},
+		utils.MetaTCC:           []string{"4", "2.5"},
+		utils.MetaDDC:           []string{"2", "1"},
+		utils.MetaDistinctCount: []string{"2", "1"},
+		"*p50":                  []string{"10", "20"},
+	} {
+		metric, err := NewStatMetric(metricID, 0, utils.Destination)
+		if metricID == "*p50" {
+			metric, err = NewStatMetric(metricID, 0, "")
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		metric.AddBucketEvent(ev, "BKT1")
+		metric.AddBucketEvent(ev2, "BKT2")
+		if strVal := metric.GetStringValue(""); strVal != eVals[0] {
+			t.Errorf("%s expecting: %s, received: %s", metricID, eVals[0], strVal)
+		}
+		if err := metric.RemBucket("BKT1"); err != nil {
+			t.Error(err)
+		} else if strVal := metric.GetStringValue(""); strVal != eVals[1] {
+			t.Errorf("%s expecting: %s, received: %s", metricID, eVals[1], strVal)
+		}
+		if err := metric.RemBucket("BKT1"); err != utils.ErrNotFound {
+			t.Errorf("%s expecting: %v, received: %v", metricID, utils.ErrNotFound, err)
+		}
+	}
+}
@@ -283,12 +283,51 @@ func (sS *StatService) V1GetStatQueuesForEvent(ev *utils.CGREvent, reply *StatQu
 	return
 }
 
+// getStatQueue returns the StatQueue with the buckets out of TTL removed
+// so the metrics read do not include them in case of no new events
+func (sS *StatService) getStatQueue(tenant, sqID string) (sq *StatQueue, err error) {
+	if sq, err = sS.dm.GetStatQueue(tenant, sqID, false, ""); err != nil {
+		return
+	}
+	sqPrfl, err := sS.dm.GetStatQueueProfile(tenant, sqID, false, utils.NonTransactional)
+	if err != nil {
+		if err == utils.ErrNotFound { // no profile to expire the buckets on
+			err = nil
+		}
+		return
+	}
+	if sqPrfl.BucketInterval <= 0 || sqPrfl.TTL <= 0 {
+		return
+	}
+	lockID := utils.StatQueuesStringIndex + sqID
+	guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lockID)
+	defer guardian.Guardian.UnguardIDs(lockID)
+	sq.sqPrfl = sqPrfl
+	sq.ttl = utils.DurationPointer(sqPrfl.TTL)
+	if sq.remExpiredBuckets(time.Now()) == 0 ||
+		!sqPrfl.Stored || sS.storeInterval == 0 { // nothing changed or no need to save
+		return
+	}
+	if sq.dirty == nil {
+		sq.dirty = utils.BoolPointer(false)
+	}
+	*sq.dirty = true // the cached queue differs from the stored one
+	if sS.storeInterval == -1 {
+		sS.StoreStatQueue(sq)
+		return
+	}
+	sS.ssqMux.Lock()
+	sS.storedStatQueues[sq.TenantID()] = true
+	sS.ssqMux.Unlock()
+	return
+}
+
 // V1GetQueueStringMetrics returns the metrics of a Queue as string values
 func (sS *StatService) V1GetQueueStringMetrics(args *utils.TenantID, reply *map[string]string) (err error) {
 	if missing := utils.MissingStructFields(args, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
-	sq, err := sS.dm.GetStatQueue(args.Tenant, args.ID, false, "")
+	sq, err := sS.getStatQueue(args.Tenant, args.ID)
 	if err != nil {
 		if err != utils.ErrNotFound {
 			err = utils.NewErrServerError(err)
@@ -308,7 +347,7 @@ func (sS *StatService) V1GetQueueFloatMetrics(args *utils.TenantID, reply *map[s
 	if missing := utils.MissingStructFields(args, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
-	sq, err := sS.dm.GetStatQueue(args.Tenant, args.ID, false, "")
+	sq, err := sS.getStatQueue(args.Tenant, args.ID)
 	if err != nil {
 		if err != utils.ErrNotFound {
 			err = utils.NewErrServerError(err)
@@ -69,7 +69,7 @@ func TestReqFilterPassStatS(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if passes, err := rf.Pass(cdr, "", statS); err != nil {
+	if passes, err := rf.Pass(cdr, "", statS, "cgrates.org"); err != nil {
 		t.Error(err)
 	} else if !passes {
 		t.Error("Not passing")
@@ -20,6 +20,7 @@ package engine
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -743,7 +744,9 @@ func (csvs *CSVStorage) GetTPSuppliers(tpid, id string) ([]*utils.TPSupplierProf
 }
 
 func (csvs *CSVStorage) GetTPAttributes(tpid, id string) ([]*utils.TPAttributeProfile, error) {
-	csvReader, fp, err := csvs.readerFunc(csvs.attributeProfilesFn, csvs.sep, getColumnCount(TPAttribute{}))
+	nrFields := getColumnCount(TPAttribute{})
+	// number of fields checked per record since files written before the Type column have one less
+	csvReader, fp, err := csvs.readerFunc(csvs.attributeProfilesFn, csvs.sep, -1)
 	if err != nil {
 		//log.Print("Could not load AttributeProfile file: ", err)
 		// allow writing of the other values
@@ -754,6 +757,13 @@ func (csvs *CSVStorage) GetTPAttributes(tpid, id string) ([]*utils.TPAttributePr
 	}
 	var tpAls TPAttributes
 	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err == nil && len(record) != nrFields {
+			if len(record) != nrFields-1 {
+				err = fmt.Errorf("wrong number of fields: %d", len(record))
+			} else {
+				record = append(record, "") // no Type, static substitute
+			}
+		}
 		if err != nil {
 			log.Printf("bad line in %s, %s\n", csvs.attributeProfilesFn, err.Error())
 			return nil, err
@@ -145,6 +145,9 @@ type DataDB interface {
 	GetAttributeProfileDrv(string, string) (*AttributeProfile, error)
 	SetAttributeProfileDrv(*AttributeProfile) error
 	RemoveAttributeProfileDrv(string, string) error
+	GetStoredSessionDrv(string) (*StoredSession, error)
+	SetStoredSessionDrv(*StoredSession) error
+	RemoveStoredSessionDrv(string) error
 }
 
 type StorDB interface {
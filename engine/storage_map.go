@@ -1603,6 +1603,38 @@ func (ms *MapStorage) RemoveAttributeProfileDrv(tenant, id string) (err error) {
 	return
 }
 
+// GetStoredSessionDrv retrieves a StoredSession from dataDB
+func (ms *MapStorage) GetStoredSessionDrv(cgrID string) (ss *StoredSession, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.SessionsPrefix+cgrID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &ss)
+	return
+}
+
+// SetStoredSessionDrv stores the state of an active session
+func (ms *MapStorage) SetStoredSessionDrv(ss *StoredSession) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var result []byte
+	if result, err = ms.ms.Marshal(ss); err != nil {
+		return
+	}
+	ms.dict[utils.SessionsPrefix+ss.CGRID] = result
+	return
+}
+
+// RemoveStoredSessionDrv removes a StoredSession from dataDB
+func (ms *MapStorage) RemoveStoredSessionDrv(cgrID string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.dict, utils.SessionsPrefix+cgrID)
+	return
+}
+
 func (ms *MapStorage) GetVersions(itm string) (vrs Versions, err error) {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
@@ -67,6 +67,7 @@ const (
 	colFlt   = "filters"
 	colSpp   = "supplier_profiles"
 	colAttr  = "attribute_profiles"
+	colSes   = "sessions"
 )
 
 var (
@@ -348,6 +349,7 @@ func (ms *MongoStorage) getColNameForPrefix(prefix string) (name string, ok bool
 		utils.FilterPrefix:           colFlt,
 		utils.SupplierProfilePrefix:  colSpp,
 		utils.AttributeProfilePrefix: colAttr,
+		utils.SessionsPrefix:         colSes,
 	}
 	name, ok = colMap[prefix]
 	return
@@ -625,6 +627,12 @@ func (ms *MongoStorage) GetKeysForPrefix(prefix string) (result []string, err er
 		for iter.Next(&idResult) {
 			result = append(result, utils.TimingsPrefix+idResult.Id)
 		}
+	case utils.SessionsPrefix:
+		cgrIDResult := struct{ CGRID string }{}
+		iter := db.C(colSes).Find(bson.M{"cgrid": bson.M{"$regex": bson.RegEx{Pattern: subject}}}).Select(bson.M{"cgrid": 1}).Iter()
+		for iter.Next(&cgrIDResult) {
+			result = append(result, utils.SessionsPrefix+cgrIDResult.CGRID)
+		}
 	case utils.FilterPrefix:
 		iter := db.C(colFlt).Find(bson.M{"id": bson.M{"$regex": bson.RegEx{Pattern: subject}}}).Select(bson.M{"tenant": 1, "id": 1}).Iter()
 		for iter.Next(&idResult) {
@@ -2287,3 +2295,34 @@ func (ms *MongoStorage) RemoveAttributeProfileDrv(tenant, id string) (err error)
 	}
 	return nil
 }
+
+// GetStoredSessionDrv retrieves a StoredSession from dataDB
+func (ms *MongoStorage) GetStoredSessionDrv(cgrID string) (ss *StoredSession, err error) {
+	session, col := ms.conn(colSes)
+	defer session.Close()
+	if err = col.Find(bson.M{"cgrid": cgrID}).One(&ss); err != nil {
+		if err == mgo.ErrNotFound {
+			err = utils.ErrNotFound
+		}
+		return nil, err
+	}
+	return
+}
+
+// SetStoredSessionDrv stores the state of an active session
+func (ms *MongoStorage) SetStoredSessionDrv(ss *StoredSession) (err error) {
+	session, col := ms.conn(colSes)
+	defer session.Close()
+	_, err = col.Upsert(bson.M{"cgrid": ss.CGRID}, ss)
+	return
+}
+
+// RemoveStoredSessionDrv removes a StoredSession from dataDB
+func (ms *MongoStorage) RemoveStoredSessionDrv(cgrID string) (err error) {
+	session, col := ms.conn(colSes)
+	defer session.Close()
+	if err = col.Remove(bson.M{"cgrid": cgrID}); err == mgo.ErrNotFound {
+		err = utils.ErrNotFound
+	}
+	return
+}
@@ -1761,6 +1761,33 @@ func (rs *RedisStorage) RemoveAttributeProfileDrv(tenant, id string) (err error)
 	return
 }
 
+// GetStoredSessionDrv retrieves a StoredSession from dataDB
+func (rs *RedisStorage) GetStoredSessionDrv(cgrID string) (ss *StoredSession, err error) {
+	var values []byte
+	if values, err = rs.Cmd("GET", utils.SessionsPrefix+cgrID).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	err = rs.ms.Unmarshal(values, &ss)
+	return
+}
+
+// SetStoredSessionDrv stores the state of an active session
+func (rs *RedisStorage) SetStoredSessionDrv(ss *StoredSession) (err error) {
+	var result []byte
+	if result, err = rs.ms.Marshal(ss); err != nil {
+		return
+	}
+	return rs.Cmd("SET", utils.SessionsPrefix+ss.CGRID, result).Err
+}
+
+// RemoveStoredSessionDrv removes a StoredSession from dataDB
+func (rs *RedisStorage) RemoveStoredSessionDrv(cgrID string) (err error) {
+	return rs.Cmd("DEL", utils.SessionsPrefix+cgrID).Err
+}
+
 func (rs *RedisStorage) GetStorageType() string {
 	return utils.REDIS
 }
@@ -19,8 +19,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/cgrates/cgrates/cache"
@@ -167,24 +170,29 @@ func (spS *SupplierService) matchingSupplierProfilesForEvent(ev *utils.CGREvent)
 	return
 }
 
-// costForEvent will compute cost out of accounts and rating plans for event
-// returns map[string]interface{} with cost and relevant matching information inside
-func (spS *SupplierService) costForEvent(ev *utils.CGREvent,
-	acntIDs, rpIDs []string) (costData map[string]interface{}, err error) {
+// callDescriptorForEvent builds the CallDescriptor used for cost calculation out of event fields
+// Subject defaults to Account and Category to the configured default one
+func (spS *SupplierService) callDescriptorForEvent(ev *utils.CGREvent) (cd *CallDescriptor, err error) {
 	if err = ev.CheckMandatoryFields([]string{utils.Account,
 		utils.Destination, utils.AnswerTime, utils.Usage}); err != nil {
 		return
 	}
-	var acnt, subj, dst string
+	var acnt, subj, dst, ctgr string
 	if acnt, err = ev.FieldAsString(utils.Account); err != nil {
 		return
 	}
-	if subj, err = ev.FieldAsString(utils.Account); err != nil {
+	if subj, err = ev.FieldAsString(utils.Subject); err != nil {
 		if err != utils.ErrNotFound {
 			return
 		}
 		subj = acnt
 	}
+	if ctgr, err = ev.FieldAsString(utils.Category); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		ctgr = config.CgrConfig().DefaultCategory
+	}
 	if dst, err = ev.FieldAsString(utils.Destination); err != nil {
 		return
 	}
@@ -196,23 +204,36 @@ func (spS *SupplierService) costForEvent(ev *utils.CGREvent,
 	if usage, err = ev.FieldAsDuration(utils.Usage); err != nil {
 		return
 	}
+	return &CallDescriptor{
+		Direction:     utils.OUT,
+		Category:      ctgr,
+		Tenant:        ev.Tenant,
+		Subject:       subj,
+		Account:       acnt,
+		Destination:   dst,
+		TimeStart:     aTime,
+		TimeEnd:       aTime.Add(usage),
+		DurationIndex: usage,
+	}, nil
+}
+
+// costForEvent will compute cost out of accounts and rating plans for event
+// returns map[string]interface{} with cost and relevant matching information inside
+func (spS *SupplierService) costForEvent(ev *utils.CGREvent,
+	acntIDs, rpIDs []string) (costData map[string]interface{}, err error) {
+	evCD, err := spS.callDescriptorForEvent(ev)
+	if err != nil {
+		return
+	}
+	evCD.Category = utils.MetaSuppliers // suppliers are rated within their own category
 	for _, anctID := range acntIDs {
-		cd := &CallDescriptor{
-			Direction:     utils.OUT,
-			Category:      utils.MetaSuppliers,
-			Tenant:        ev.Tenant,
-			Subject:       subj,
-			Account:       anctID,
-			Destination:   dst,
-			TimeStart:     aTime,
-			TimeEnd:       aTime.Add(usage),
-			DurationIndex: usage,
-		}
+		cd := evCD.Clone()
+		cd.Account = anctID
 		if maxDur, err := cd.GetMaxSessionDuration(); err != nil {
 			utils.Logger.Warning(
 				fmt.Sprintf("<%s> ignoring cost for account: %s, err: %s",
-					anctID, err.Error()))
-		} else if maxDur >= usage {
+					utils.SupplierS, anctID, err.Error()))
+		} else if maxDur >= evCD.DurationIndex {
 			return map[string]interface{}{
 				utils.Cost:    0.0,
 				utils.Account: anctID,
@@ -222,10 +243,10 @@ func (spS *SupplierService) costForEvent(ev *utils.CGREvent,
 	for _, rp := range rpIDs { // loop through RatingPlans until we find one without errors
 		rPrfl := &RatingProfile{
 			Id: utils.ConcatenatedKey(utils.OUT,
-				ev.Tenant, utils.MetaSuppliers, subj),
+				ev.Tenant, utils.MetaSuppliers, evCD.Subject),
 			RatingPlanActivations: RatingPlanActivations{
 				&RatingPlanActivation{
-					ActivationTime: aTime,
+					ActivationTime: evCD.TimeStart,
 					RatingPlanId:   rp,
 				},
 			},
@@ -233,18 +254,7 @@ func (spS *SupplierService) costForEvent(ev *utils.CGREvent,
 		// force cache set so it can be picked by calldescriptor for cost calculation
 		cacheKey := utils.RATING_PROFILE_PREFIX + rPrfl.Id
 		cache.Set(cacheKey, rPrfl, true, utils.NonTransactional)
-		cd := &CallDescriptor{
-			Direction:     utils.OUT,
-			Category:      utils.MetaSuppliers,
-			Tenant:        ev.Tenant,
-			Subject:       subj,
-			Account:       acnt,
-			Destination:   dst,
-			TimeStart:     aTime,
-			TimeEnd:       aTime.Add(usage),
-			DurationIndex: usage,
-		}
-		cc, err := cd.GetCost()
+		cc, err := evCD.Clone().GetCost()
 		cache.RemKey(cacheKey, true, utils.NonTransactional) // Remove here so we don't overload memory
 		if err != nil {
 			if err != utils.ErrNotFound {
@@ -260,17 +270,92 @@ func (spS *SupplierService) costForEvent(ev *utils.CGREvent,
 	return
 }
 
+// sellCostForEvent will compute the cost of the event for it's own account/subject
+// used as reference when computing supplier margins
+func (spS *SupplierService) sellCostForEvent(ev *utils.CGREvent) (cost float64, err error) {
+	cd, err := spS.callDescriptorForEvent(ev)
+	if err != nil {
+		return
+	}
+	cc, err := cd.GetCost()
+	if err != nil {
+		return
+	}
+	return NewEventCostFromCallCost(cc, "", "").GetCost(), nil
+}
+
 // statMetrics will query a list of statIDs and return composed metric values
 // first metric found is always returned
-func (spS *SupplierService) statMetrics(statIDs []string, metricIDs []string) (sms map[string]StatMetric, err error) {
+func (spS *SupplierService) statMetrics(statIDs []string, tenant string) (sms map[string]float64, err error) {
+	if spS.statS == nil || reflect.ValueOf(spS.statS).IsNil() {
+		return nil, errors.New("Missing StatS information")
+	}
+	sms = make(map[string]float64)
+	for _, statID := range statIDs {
+		var metrics map[string]float64
+		if err = spS.statS.Call(utils.StatSv1GetQueueFloatMetrics,
+			&utils.TenantID{Tenant: tenant, ID: statID}, &metrics); err != nil {
+			if err.Error() != utils.ErrNotFound.Error() {
+				return nil, err
+			}
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> ignoring stat queue with ID: %s, err: %s",
+					utils.SupplierS, statID, err.Error()))
+			err = nil
+			continue
+		}
+		for metricID, val := range metrics {
+			if _, has := sms[metricID]; !has {
+				sms[metricID] = val
+			}
+		}
+	}
 	return
 }
 
 // resourceUsage returns sum of all resource usages out of list
-func (spS *SupplierService) resourceUsage(resIDs []string) (tUsage float64, err error) {
+func (spS *SupplierService) resourceUsage(resIDs []string, tenant string) (tUsage float64, err error) {
+	if spS.resourceS == nil || reflect.ValueOf(spS.resourceS).IsNil() {
+		return 0, errors.New("Missing ResourceS information")
+	}
+	for _, resID := range resIDs {
+		var res Resource
+		if err = spS.resourceS.Call(utils.ResourceSv1GetResource,
+			&utils.TenantID{Tenant: tenant, ID: resID}, &res); err != nil {
+			if err.Error() != utils.ErrNotFound.Error() {
+				return 0, err
+			}
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> ignoring resource with ID: %s, err: %s",
+					utils.SupplierS, resID, err.Error()))
+			err = nil
+			continue
+		}
+		tUsage += res.totalUsage()
+	}
 	return
 }
 
+// supplierUsage returns the total usage of the supplier resources
+// nil tUsage means that the supplier should be ignored
+func (spS *SupplierService) supplierUsage(prflID string, s *Supplier,
+	ev *utils.CGREvent, extraOpts *optsGetSuppliers) (tUsage *float64, err error) {
+	if len(s.ResourceIDs) == 0 {
+		return utils.Float64Pointer(0), nil
+	}
+	usage, err := spS.resourceUsage(s.ResourceIDs, ev.Tenant)
+	if err != nil {
+		if !extraOpts.ignoreErrors {
+			return nil, err
+		}
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, err: %s",
+				utils.SupplierS, prflID, s.ID, err.Error()))
+		return nil, nil
+	}
+	return &usage, nil
+}
+
 // supliersForEvent will return the list of valid supplier IDs
 // for event based on filters and sorting algorithms
 func (spS *SupplierService) sortedSuppliersForEvent(args *ArgsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
@@ -293,7 +378,13 @@ func (spS *SupplierService) sortedSuppliersForEvent(args *ArgsGetSuppliers) (sor
 		}
 		spls = append(spls, s)
 	}
-	sortedSuppliers, err := spS.sorter.SortSuppliers(splPrfl.ID, splPrfl.Sorting, spls, &args.CGREvent)
+	extraOpts, err := spS.optsForArgs(args, splPrfl.Sorting)
+	if err != nil {
+		return nil, err
+	}
+	extraOpts.sortingParameters = splPrfl.SortingParams
+	sortedSuppliers, err := spS.sorter.SortSuppliers(splPrfl.ID, splPrfl.Sorting,
+		spls, &args.CGREvent, extraOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -310,11 +401,79 @@ func (spS *SupplierService) sortedSuppliersForEvent(args *ArgsGetSuppliers) (sor
 	return sortedSuppliers, nil
 }
 
+// costSortingData returns the sorting data for a supplier based on it's cost
+// nil srtData means that the supplier should be ignored
+func (spS *SupplierService) costSortingData(prflID string, s *Supplier,
+	ev *utils.CGREvent, extraOpts *optsGetSuppliers) (srtData map[string]interface{}, err error) {
+	costData, err := spS.costForEvent(ev, s.AccountIDs, s.RatingPlanIDs)
+	if err != nil {
+		if !extraOpts.ignoreErrors {
+			return nil, err
+		}
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, err: %s",
+				utils.SupplierS, prflID, s.ID, err.Error()))
+		return nil, nil
+	} else if len(costData) == 0 {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, missing cost information",
+				utils.SupplierS, prflID, s.ID))
+		return nil, nil
+	}
+	if extraOpts.maxCost != nil &&
+		costData[utils.Cost].(float64) > *extraOpts.maxCost {
+		return nil, nil
+	}
+	srtData = map[string]interface{}{
+		utils.Weight: s.Weight,
+	}
+	for k, v := range costData {
+		srtData[k] = v
+	}
+	return
+}
+
 type ArgsGetSuppliers struct {
+	IgnoreErrors bool   // ignore suppliers with errors instead of failing the request
+	MaxCost      string // fixed value or *event_cost, suppliers with higher cost are not returned, cost based sorting only
 	utils.CGREvent
 	utils.Paginator
 }
 
+// optsForArgs builds the sorting options out of ArgsGetSuppliers
+func (spS *SupplierService) optsForArgs(args *ArgsGetSuppliers, sorting string) (opts *optsGetSuppliers, err error) {
+	opts = &optsGetSuppliers{ignoreErrors: args.IgnoreErrors}
+	if args.MaxCost != "" {
+		switch sorting {
+		case utils.MetaLeastCost, utils.MetaHighestCost, utils.MetaMaxMargin: // only strategies computing supplier costs can apply MaxCost
+		default:
+			return nil, fmt.Errorf("MaxCost not supported by sorting strategy: %s", sorting)
+		}
+	}
+	switch args.MaxCost {
+	case "":
+	case utils.MetaEventCost: // dynamic cost, the one of the event itself
+		var cost float64
+		if cost, err = spS.sellCostForEvent(&args.CGREvent); err != nil {
+			if !args.IgnoreErrors {
+				return nil, err
+			}
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> ignoring MaxCost for event with ID: %s, err: %s",
+					utils.SupplierS, args.ID, err.Error()))
+			return opts, nil
+		}
+		opts.maxCost = &cost
+	default:
+		var cost float64
+		if cost, err = strconv.ParseFloat(args.MaxCost, 64); err != nil {
+			return nil, err
+		}
+		opts.maxCost = &cost
+	}
+	return
+}
+
 // V1GetSuppliersForEvent returns the list of valid supplier IDs
 func (spS *SupplierService) V1GetSuppliers(args *ArgsGetSuppliers, reply *SortedSuppliers) (err error) {
 	if missing := utils.MissingStructFields(&args.CGREvent, []string{"Tenant", "ID"}); len(missing) != 0 {
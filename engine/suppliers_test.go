@@ -426,3 +426,58 @@ func TestSuppliersSortedForEventWithLimitAndOffset(t *testing.T) {
 		t.Errorf("Expecting: %+v,received: %+v", utils.ToJSON(eFirstSupplierProfile), utils.ToJSON(sprf))
 	}
 }
+
+func TestSuppliersOptsForArgs(t *testing.T) {
+	args := &ArgsGetSuppliers{
+		IgnoreErrors: true,
+		MaxCost:      "0.05",
+	}
+	eOpts := &optsGetSuppliers{
+		ignoreErrors: true,
+		maxCost:      utils.Float64Pointer(0.05),
+	}
+	if opts, err := splserv.optsForArgs(args, utils.MetaLeastCost); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eOpts, opts) {
+		t.Errorf("Expecting: %+v, received: %+v", eOpts, opts)
+	}
+	if _, err := splserv.optsForArgs(args, utils.MetaWeight); err == nil {
+		t.Error("Expecting error for MaxCost with strategy not computing costs")
+	}
+	args.MaxCost = "notANumber"
+	if _, err := splserv.optsForArgs(args, utils.MetaLeastCost); err == nil {
+		t.Error("Expecting error")
+	}
+	args.MaxCost = utils.MetaEventCost // event without cost information, ignored with IgnoreErrors
+	if opts, err := splserv.optsForArgs(args, utils.MetaLeastCost); err != nil {
+		t.Error(err)
+	} else if opts.maxCost != nil {
+		t.Errorf("Unexpected maxCost: %v", *opts.maxCost)
+	}
+}
+
+func TestSuppliersCostSortingDataMaxCost(t *testing.T) {
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "testMaxCost",
+		Event: map[string]interface{}{
+			utils.Account:     "1001",
+			utils.Destination: "49",
+			utils.AnswerTime:  time.Date(2014, 7, 14, 14, 30, 0, 0, time.UTC),
+			utils.Usage:       "60s",
+		},
+	}
+	spl := &Supplier{ID: "SPL_GER", RatingPlanIDs: []string{"GER_ONLY"}, Weight: 10}
+	if srtData, err := splserv.costSortingData("TEST_MAXCOST", spl, ev,
+		&optsGetSuppliers{maxCost: utils.Float64Pointer(1000)}); err != nil {
+		t.Error(err)
+	} else if srtData == nil {
+		t.Error("Expecting supplier within MaxCost to be returned")
+	}
+	if srtData, err := splserv.costSortingData("TEST_MAXCOST", spl, ev,
+		&optsGetSuppliers{maxCost: utils.Float64Pointer(1)}); err != nil {
+		t.Error(err)
+	} else if srtData != nil {
+		t.Errorf("Expecting supplier above MaxCost to be filtered out, received: %+v", srtData)
+	}
+}
@@ -19,8 +19,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"path"
 	"sort"
 	"sync"
 	"time"
@@ -29,6 +31,7 @@ import (
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/guardian"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/streadway/amqp"
 )
 
 type ThresholdProfile struct {
@@ -66,13 +69,18 @@ func (t *Threshold) TenantID() string {
 
 // ProcessEvent processes an ThresholdEvent
 // concurrentActions limits the number of simultaneous action sets executed
-func (t *Threshold) ProcessEvent(args *ArgsProcessEvent, dm *DataManager) (err error) {
+// exports are the destinations where the threshold hit is posted
+func (t *Threshold) ProcessEvent(args *ArgsProcessEvent, dm *DataManager,
+	exports []*config.ThresholdSExportCfg) (err error) {
 	if t.Snooze.After(time.Now()) { // snoozed, not executing actions
 		return
 	}
 	if t.Hits < t.tPrfl.MinHits { // number of hits was not met, will not execute actions
 		return
 	}
+	if len(exports) != 0 {
+		t.postHit(args, exports)
+	}
 	acnt, _ := args.FieldAsString(utils.Account)
 	var acntID string
 	if acnt != "" {
@@ -104,6 +112,70 @@ func (t *Threshold) ProcessEvent(args *ArgsProcessEvent, dm *DataManager) (err e
 	return
 }
 
+// ThresholdHit is the event posted to the ThresholdS exports when a threshold fires
+type ThresholdHit struct {
+	Tenant      string
+	ThresholdID string
+	Hits        int
+	CGREvent    *utils.CGREvent // the event triggering the threshold
+}
+
+// postHit posts the hit towards the export destinations in the background so the
+// threshold processing is not delayed, failures are logged and left to the fallback files
+func (t *Threshold) postHit(args *ArgsProcessEvent, exports []*config.ThresholdSExportCfg) {
+	content, err := json.Marshal(&ThresholdHit{Tenant: t.Tenant, ThresholdID: t.ID,
+		Hits: t.Hits, CGREvent: &args.CGREvent})
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<ThresholdS> failed encoding hit of: %s, error: %s",
+			t.TenantID(), err.Error()))
+		return
+	}
+	for _, expCfg := range exports {
+		go func(expCfg *config.ThresholdSExportCfg) {
+			if errPost := postThresholdHit(content, expCfg); errPost != nil {
+				utils.Logger.Warning(fmt.Sprintf("<ThresholdS> failed posting hit of: %s to: %s, error: %s",
+					t.TenantID(), expCfg.ExportPath, errPost.Error()))
+			}
+		}(expCfg)
+	}
+}
+
+// postThresholdHit posts the JSON content to one export destination
+// failed posts are written in the FailedPostsDir so they can be replayed
+func postThresholdHit(content []byte, expCfg *config.ThresholdSExportCfg) (err error) {
+	cfg := config.CgrConfig()
+	ffn := &utils.FallbackFileName{Module: utils.ThresholdsPoster, Transport: expCfg.ExportFormat,
+		Address: expCfg.ExportPath, RequestID: utils.GenUUID(), FileSuffix: utils.JSNSuffix}
+	fallbackFileName := utils.META_NONE
+	if cfg.FailedPostsDir != utils.META_NONE {
+		fallbackFileName = ffn.AsString()
+	}
+	switch expCfg.ExportFormat {
+	case utils.MetaHTTPjson:
+		fallbackPath := utils.META_NONE
+		if fallbackFileName != utils.META_NONE {
+			fallbackPath = path.Join(cfg.FailedPostsDir, fallbackFileName)
+		}
+		_, err = utils.NewHTTPPoster(cfg.HttpSkipTlsVerify, cfg.ReplyTimeout).Post(expCfg.ExportPath,
+			utils.PosterTransportContentTypes[expCfg.ExportFormat], content, cfg.PosterAttempts, fallbackPath)
+	case utils.MetaAMQPjsonMap:
+		var amqpPoster *utils.AMQPPoster
+		if amqpPoster, err = utils.AMQPPostersCache.GetAMQPPoster(expCfg.ExportPath,
+			cfg.PosterAttempts, cfg.FailedPostsDir); err != nil {
+			return
+		}
+		var chn *amqp.Channel
+		chn, err = amqpPoster.Post(nil, utils.PosterTransportContentTypes[expCfg.ExportFormat],
+			content, fallbackFileName)
+		if chn != nil {
+			chn.Close()
+		}
+	default:
+		err = fmt.Errorf("unsupported export format: <%s>", expCfg.ExportFormat)
+	}
+	return
+}
+
 // Thresholds is a sortable slice of Threshold
 type Thresholds []*Threshold
 
@@ -113,11 +185,12 @@ func (ts Thresholds) Sort() {
 }
 
 func NewThresholdService(dm *DataManager, indexedFields []string, storeInterval time.Duration,
-	filterS *FilterS) (tS *ThresholdService, err error) {
+	filterS *FilterS, exports []*config.ThresholdSExportCfg) (tS *ThresholdService, err error) {
 	return &ThresholdService{dm: dm,
 		indexedFields: indexedFields,
 		storeInterval: storeInterval,
 		filterS:       filterS,
+		exports:       exports,
 		stopBackup:    make(chan struct{}),
 		storedTdIDs:   make(utils.StringMap)}, nil
 }
@@ -128,6 +201,7 @@ type ThresholdService struct {
 	indexedFields []string // fields considered when searching for matching thresholds
 	storeInterval time.Duration
 	filterS       *FilterS
+	exports       []*config.ThresholdSExportCfg // destinations where the threshold hits are posted
 	stopBackup    chan struct{}
 	storedTdIDs   utils.StringMap // keep a record of stats which need saving, map[statsTenantID]bool
 	stMux         sync.RWMutex    // protects storedTdIDs
@@ -287,7 +361,7 @@ func (tS *ThresholdService) processEvent(args *ArgsProcessEvent) (hits int, err
 	var withErrors bool
 	for _, t := range matchTs {
 		t.Hits += 1
-		err = t.ProcessEvent(args, tS.dm)
+		err = t.ProcessEvent(args, tS.dm, tS.exports)
 		if err != nil {
 			utils.Logger.Warning(
 				fmt.Sprintf("<ThresholdService> threshold: %s, ignoring event: %s, error: %s",
@@ -371,3 +445,32 @@ func (tS *ThresholdService) V1GetThreshold(tntID *utils.TenantID, t *Threshold)
 	}
 	return
 }
+
+// V1ResetThreshold resets the Hits and Snooze of a Threshold so it can fire again
+func (tS *ThresholdService) V1ResetThreshold(tntID *utils.TenantID, reply *string) (err error) {
+	if missing := utils.MissingStructFields(tntID, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	lkID := utils.ThresholdStringIndex + tntID.ID // same lock as matchingThresholdsForEvent
+	guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lkID)
+	defer guardian.Guardian.UnguardIDs(lkID)
+	t, err := tS.dm.GetThreshold(tntID.Tenant, tntID.ID, false, utils.NonTransactional)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	t.Hits = 0
+	t.Snooze = time.Time{}
+	if t.dirty != nil && tS.storeInterval != -1 { // recurrent threshold, leave it to the backup loop
+		*t.dirty = true
+		tS.stMux.Lock()
+		tS.storedTdIDs[t.TenantID()] = true
+		tS.stMux.Unlock()
+	} else if err = tS.dm.SetThreshold(t); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return
+}
@@ -18,8 +18,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestThresholdsSort(t *testing.T) {
@@ -40,3 +48,68 @@ func TestThresholdsSort(t *testing.T) {
 		t.Errorf("expecting: %+v, received: %+v", eInst, ts)
 	}
 }
+
+func TestThresholdPostHit(t *testing.T) {
+	rcvHits := make(chan *ThresholdHit, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var hit ThresholdHit
+		if err := json.Unmarshal(body, &hit); err != nil {
+			t.Error(err)
+		}
+		rcvHits <- &hit
+	}))
+	defer srv.Close()
+	th := &Threshold{Tenant: "cgrates.org", ID: "TH_1", Hits: 2,
+		tPrfl: &ThresholdProfile{Tenant: "cgrates.org", ID: "TH_1"}}
+	args := &ArgsProcessEvent{
+		CGREvent: utils.CGREvent{
+			Tenant: "cgrates.org",
+			ID:     "ev1",
+			Event:  map[string]interface{}{utils.Account: "1001"},
+		},
+	}
+	exports := []*config.ThresholdSExportCfg{
+		&config.ThresholdSExportCfg{ExportFormat: utils.MetaHTTPjson, ExportPath: srv.URL}}
+	if err := th.ProcessEvent(args, nil, exports); err != nil {
+		t.Fatal(err)
+	}
+	eHit := &ThresholdHit{Tenant: "cgrates.org", ThresholdID: "TH_1", Hits: 2, CGREvent: &args.CGREvent}
+	if hit := <-rcvHits; !reflect.DeepEqual(eHit, hit) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eHit), utils.ToJSON(hit))
+	}
+	exports[0].ExportFormat = utils.MetaFileCSV // failed posts are not failing the hit
+	if err := th.ProcessEvent(args, nil, exports); err != nil {
+		t.Error(err)
+	}
+	if err := postThresholdHit(nil, exports[0]); err == nil {
+		t.Error("Expecting error for unsupported export format")
+	}
+}
+
+func TestThresholdsV1ResetThreshold(t *testing.T) {
+	data, _ := NewMapStorage()
+	dm := NewDataManager(data)
+	tS, _ := NewThresholdService(dm, nil, -1, nil, nil)
+	th := &Threshold{Tenant: "cgrates.org", ID: "TH_RESET", Hits: 5,
+		Snooze: time.Now().Add(time.Hour)}
+	if err := dm.SetThreshold(th); err != nil {
+		t.Fatal(err)
+	}
+	var reply string
+	if err := tS.V1ResetThreshold(&utils.TenantID{Tenant: "cgrates.org", ID: "TH_RESET"}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Unexpected reply: %s", reply)
+	}
+	var rcvTh Threshold
+	if err := tS.V1GetThreshold(&utils.TenantID{Tenant: "cgrates.org", ID: "TH_RESET"}, &rcvTh); err != nil {
+		t.Error(err)
+	} else if rcvTh.Hits != 0 || !rcvTh.Snooze.IsZero() {
+		t.Errorf("Threshold not reset: %s", utils.ToJSON(rcvTh))
+	}
+	if err := tS.V1ResetThreshold(&utils.TenantID{Tenant: "cgrates.org", ID: "TH_MISSING"},
+		&reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
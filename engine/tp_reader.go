@@ -1798,10 +1798,51 @@ func (tpr *TpReader) LoadFiltersFiltered(tag string) error {
 	for _, th := range tps {
 		mapTHs[utils.TenantID{Tenant: th.Tenant, ID: th.ID}] = th
 	}
+	for tntID, th := range mapTHs { // make sure the filters referenced by *or exist
+		for _, fltrID := range tpFilterOrIDs(th) {
+			if _, has := mapTHs[utils.TenantID{Tenant: tntID.Tenant, ID: fltrID}]; has {
+				continue
+			}
+			if has, err := tpr.dm.HasData(utils.FilterPrefix, utils.ConcatenatedKey(tntID.Tenant, fltrID)); err != nil {
+				return err
+			} else if !has {
+				return fmt.Errorf("broken reference to filter: %+v for filter: %+v", fltrID, th)
+			}
+		}
+	}
+	for tntID := range mapTHs { // no reference cycles, they would never end matching
+		if err := CheckOrFilterCycles(tntID.ID, func(fltrID string) ([]string, error) {
+			if th, has := mapTHs[utils.TenantID{Tenant: tntID.Tenant, ID: fltrID}]; has {
+				return tpFilterOrIDs(th), nil
+			}
+			fltr, err := tpr.dm.GetFilter(tntID.Tenant, fltrID, false, utils.NonTransactional)
+			if err != nil {
+				return nil, err
+			}
+			return fltr.OrFilterIDs(), nil
+		}); err != nil {
+			return err
+		}
+	}
 	tpr.filters = mapTHs
 	return nil
 }
 
+// tpFilterOrIDs returns the filter IDs referenced by the *or filters of a TPFilterProfile
+func tpFilterOrIDs(th *utils.TPFilterProfile) (fltrIDs []string) {
+	for _, fltr := range th.Filters {
+		if baseType, _ := filterBaseType(fltr.Type); baseType != MetaOr {
+			continue
+		}
+		for _, fltrID := range fltr.Values {
+			if !strings.HasPrefix(fltrID, utils.MetaPrefix) {
+				fltrIDs = append(fltrIDs, fltrID)
+			}
+		}
+	}
+	return
+}
+
 func (tpr *TpReader) LoadFilters() error {
 	return tpr.LoadFiltersFiltered("")
 }
@@ -1876,6 +1917,12 @@ func (tpr *TpReader) LoadAttributeProfilesFiltered(tag string) (err error) {
 	}
 	tpr.attributeProfiles = mapRsPfls
 	for tntID, attrP := range mapRsPfls {
+		for _, attr := range attrP.Attributes { // validate the dynamic substitutes before storing
+			if _, err := newAttrSubstitute(attr.Type, attr.Substitute); err != nil {
+				return fmt.Errorf("attribute profile: %s, field: %s, substitute: <%s>, error: %s",
+					tntID.TenantID(), attr.FieldName, attr.Substitute, err.Error())
+			}
+		}
 		if has, err := tpr.dm.HasData(utils.AttributeProfilePrefix, tntID.TenantID()); err != nil {
 			return err
 		} else if !has {
@@ -89,6 +89,7 @@ func (vers Versions) Compare(curent Versions, storType string) string {
 	}
 	stor := map[string]string{
 		utils.COST_DETAILS: "cgr-migrator -migrate=*cost_details",
+		utils.TpAttributes: "cgr-migrator -migrate=*tp_attributes",
 	}
 	switch storType {
 	case utils.MONGO:
@@ -162,6 +163,7 @@ func CurrentStorDBVersions() Versions {
 		utils.TpDestinations:     1,
 		utils.TpRatingPlan:       1,
 		utils.TpRatingProfile:    1,
+		utils.TpAttributes:       2,
 	}
 }
 
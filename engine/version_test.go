@@ -46,4 +46,10 @@ func TestVersionCompare(t *testing.T) {
 	if message4 != "cgr-migrator -migrate=*cost_details" {
 		t.Errorf("Error failed to compare to curent version expected: %s received: %s", "cgr-migrator -migrate=*cost_details", message4)
 	}
+	sqlCur := Versions{utils.COST_DETAILS: 2, utils.TpAttributes: 2}
+	sqlOld := Versions{utils.COST_DETAILS: 2, utils.TpAttributes: 1}
+	message5 := sqlOld.Compare(sqlCur, utils.MYSQL)
+	if message5 != "cgr-migrator -migrate=*tp_attributes" {
+		t.Errorf("Error failed to compare to curent version expected: %s received: %s", "cgr-migrator -migrate=*tp_attributes", message5)
+	}
 }
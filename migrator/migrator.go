@@ -203,6 +203,8 @@ func (m *Migrator) Migrate(taskIDs []string) (err error, stats map[string]int) {
 			err = m.migrateTPcdrstats()
 		case utils.MetaTpDestinations:
 			err = m.migrateTPDestinations()
+		case utils.MetaTpAttributes:
+			err = m.migrateTPAttributes()
 			//DATADB ALL
 		case utils.MetaDataDB:
 			if err := m.migrateAccounts(); err != nil {
@@ -337,6 +339,9 @@ func (m *Migrator) Migrate(taskIDs []string) (err error, stats map[string]int) {
 			if err := m.migrateTPDestinations(); err != nil {
 				log.Print("ERROR: ", utils.MetaTpDestinations, " ", err)
 			}
+			if err := m.migrateTPAttributes(); err != nil {
+				log.Print("ERROR: ", utils.MetaTpAttributes, " ", err)
+			}
 			err = nil
 		}
 	}
@@ -0,0 +1,127 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func (m *Migrator) migrateCurrentTPAttributes() (err error) {
+	tpids, err := m.InStorDB().GetTpIds(utils.TBLTPAttributes)
+	if err != nil {
+		return err
+	}
+
+	for _, tpid := range tpids {
+		ids, err := m.InStorDB().GetTpTableIds(tpid, utils.TBLTPAttributes, utils.TPDistinctIds{"id"}, map[string]string{}, nil)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+
+			attrs, err := m.InStorDB().GetTPAttributes(tpid, id)
+			if err != nil {
+				return err
+			}
+			if attrs != nil {
+				if m.dryRun != true {
+					if err := m.OutStorDB().SetTPAttributes(attrs); err != nil {
+						return err
+					}
+					m.stats[utils.TpAttributes] += 1
+				}
+			}
+		}
+	}
+	return
+}
+
+// migrateV1TPAttributes adds the type column to the tp_attributes table of the old StorDB
+// documents in MongoDB need no change since a missing type decodes as static substitute
+func (m *Migrator) migrateV1TPAttributes() (err error) {
+	var qry string
+	switch m.oldStorDBType {
+	case utils.MYSQL:
+		qry = "ALTER TABLE tp_attributes ADD COLUMN `type` varchar(64) NOT NULL DEFAULT '' AFTER `weight`"
+	case utils.POSTGRES:
+		qry = `ALTER TABLE tp_attributes ADD COLUMN "type" varchar(64) NOT NULL DEFAULT ''`
+	default:
+		return
+	}
+	if m.dryRun {
+		return
+	}
+	if _, err = m.oldStorDB.(*engine.SQLStorage).Db.Exec(qry); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when adding type column to tp_attributes", err.Error()))
+	}
+	return
+}
+
+func (m *Migrator) migrateTPAttributes() (err error) {
+	var vrs engine.Versions
+	current := engine.CurrentStorDBVersions()
+	vrs, err = m.InStorDB().GetVersions(utils.TBLVersions) // the schema of the table we read out of decides
+	if err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when querying oldStorDB for versions", err.Error()))
+	} else if len(vrs) == 0 {
+		return utils.NewCGRError(utils.Migrator,
+			utils.MandatoryIEMissingCaps,
+			utils.UndefinedVersion,
+			"version number is not defined for TPAttributes model")
+	}
+	switch vrs[utils.TpAttributes] {
+	case current[utils.TpAttributes]:
+		if m.sameStorDB {
+			return
+		}
+		if err := m.migrateCurrentTPAttributes(); err != nil {
+			return err
+		}
+		return
+	case 0, 1: // tables created before the type column
+		if err := m.migrateV1TPAttributes(); err != nil {
+			return err
+		}
+		if !m.sameStorDB {
+			if err := m.migrateCurrentTPAttributes(); err != nil {
+				return err
+			}
+		}
+		if m.dryRun {
+			return
+		}
+		vrs = engine.Versions{utils.TpAttributes: current[utils.TpAttributes]}
+		if err := m.OutStorDB().SetVersions(vrs, false); err != nil {
+			return utils.NewCGRError(utils.Migrator,
+				utils.ServerErrorCaps,
+				err.Error(),
+				fmt.Sprintf("error: <%s> when updating TPAttributes version into StorDB", err.Error()))
+		}
+	}
+	return
+}
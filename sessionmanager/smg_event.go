@@ -265,6 +265,39 @@ func (self SMGenericEvent) GetSessionTTLUsage() *time.Duration {
 	}
 }
 
+// GetDebitInterval returns the interval for automatic debits, CGRDebitInterval in event overwrites the configured one
+// there are no per account settings for it, account specific values are populated in the event via AttributeS
+// (ie: attribute profiles with *accounts or Account filters)
+func (self SMGenericEvent) GetDebitInterval(cfgDebitInterval time.Duration) time.Duration {
+	return self.getDurationOverwrite(utils.CGRDebitInterval, cfgDebitInterval)
+}
+
+// GetMaxCallDuration returns the maximum session duration, CGRMaxCallDuration in event overwrites the configured one
+func (self SMGenericEvent) GetMaxCallDuration(cfgMaxCallDuration time.Duration) time.Duration {
+	return self.getDurationOverwrite(utils.CGRMaxCallDuration, cfgMaxCallDuration)
+}
+
+// getDurationOverwrite returns the duration in fieldName or cfgDur if the field is missing or invalid
+func (self SMGenericEvent) getDurationOverwrite(fieldName string, cfgDur time.Duration) time.Duration {
+	valIf, hasVal := self[fieldName]
+	if !hasVal {
+		return cfgDur
+	}
+	durStr, converted := utils.CastFieldIfToString(valIf)
+	if !converted {
+		utils.Logger.Warning(fmt.Sprintf("SMGenericEvent, cannot convert %s, using configured value for event: <%s>",
+			fieldName, self.GetCGRID(utils.META_DEFAULT)))
+		return cfgDur
+	}
+	dur, err := utils.ParseDurationWithNanosecs(durStr)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("SMGenericEvent, cannot parse %s, using configured value for event: <%s>",
+			fieldName, self.GetCGRID(utils.META_DEFAULT)))
+		return cfgDur
+	}
+	return dur
+}
+
 func (self SMGenericEvent) GetMaxUsage(fieldName string, cfgMaxUsage time.Duration) (time.Duration, error) {
 	if fieldName == utils.META_DEFAULT {
 		fieldName = utils.Usage
@@ -150,6 +150,31 @@ func TestSMGenericEventGetSessionTTL(t *testing.T) {
 	}
 }
 
+func TestSMGenericEventGetDurationOverwrites(t *testing.T) {
+	smGev := SMGenericEvent{utils.EVENT_NAME: "TEST_DURATION_OVERWRITES"}
+	cfgDbtItvl := time.Duration(10 * time.Second)
+	if dbtItvl := smGev.GetDebitInterval(cfgDbtItvl); dbtItvl != cfgDbtItvl {
+		t.Errorf("Expecting: %v, received: %v", cfgDbtItvl, dbtItvl)
+	}
+	smGev[utils.CGRDebitInterval] = "30s"
+	if dbtItvl := smGev.GetDebitInterval(cfgDbtItvl); dbtItvl != time.Duration(30*time.Second) {
+		t.Errorf("Received: %v", dbtItvl)
+	}
+	smGev[utils.CGRDebitInterval] = "0s" // disables automatic debits for the session
+	if dbtItvl := smGev.GetDebitInterval(cfgDbtItvl); dbtItvl != 0 {
+		t.Errorf("Received: %v", dbtItvl)
+	}
+	cfgMaxCallDur := time.Duration(3 * time.Hour)
+	smGev[utils.CGRMaxCallDuration] = "invalid"
+	if maxDur := smGev.GetMaxCallDuration(cfgMaxCallDur); maxDur != cfgMaxCallDur {
+		t.Errorf("Expecting: %v, received: %v", cfgMaxCallDur, maxDur)
+	}
+	smGev[utils.CGRMaxCallDuration] = 3600000000000 // nanoseconds as number
+	if maxDur := smGev.GetMaxCallDuration(cfgMaxCallDur); maxDur != time.Duration(time.Hour) {
+		t.Errorf("Received: %v", maxDur)
+	}
+}
+
 func TestSMGenericEventAsCDR(t *testing.T) {
 	smGev := SMGenericEvent{}
 	smGev[utils.EVENT_NAME] = "TEST_EVENT"
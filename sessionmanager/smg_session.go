@@ -54,7 +54,8 @@ type SMGSession struct {
 }
 
 // Called in case of automatic debits
-func (self *SMGSession) debitLoop(debitInterval time.Duration) {
+// onDebit, if not nil, is called after each successful debit
+func (self *SMGSession) debitLoop(debitInterval time.Duration, onDebit func()) {
 	loopIndex := 0
 	sleepDur := time.Duration(0) // start with empty duration for debit
 	for {
@@ -79,6 +80,9 @@ func (self *SMGSession) debitLoop(debitInterval time.Duration) {
 				}
 				return
 			}
+			if onDebit != nil {
+				onDebit()
+			}
 			sleepDur = debitInterval
 			loopIndex++
 		}
@@ -172,6 +176,62 @@ func (self *SMGSession) disconnectSession(reason string) error {
 	return nil
 }
 
+// Send re-authorization order to remote connection
+func (self *SMGSession) reAuthorizeSession() error {
+	if self.clntConn == nil || reflect.ValueOf(self.clntConn).IsNil() {
+		return errors.New("Calling SMGClientV1.ReAuthorizeSession requires bidirectional JSON connection")
+	}
+	var reply string
+	servMethod := "SessionSv1.ReAuthorizeSession"
+	if self.clientProto == 0 { // competibility with OpenSIPS
+		servMethod = "SMGClientV1.ReAuthorizeSession"
+	}
+	if err := self.clntConn.Call(servMethod,
+		utils.AttrReAuthorizeSession{EventStart: self.EventStart}, &reply); err != nil {
+		return err
+	} else if reply != utils.OK {
+		return fmt.Errorf("Unexpected re-authorize reply: %s", reply)
+	}
+	return nil
+}
+
+// asStoredSessionRun converts the session into its dataDB representation
+func (self *SMGSession) asStoredSessionRun() *engine.StoredSessionRun {
+	sRun := &engine.StoredSessionRun{
+		RunID:         self.RunID,
+		Timezone:      self.Timezone,
+		EventStart:    self.EventStart.Clone(),
+		ExtraDuration: self.ExtraDuration,
+		LastUsage:     self.LastUsage,
+		LastDebit:     self.LastDebit,
+		TotalUsage:    self.TotalUsage,
+	}
+	if self.CD != nil {
+		cd := *self.CD // CallDescriptor.Clone does not keep all the fields needed for debits
+		sRun.CD = &cd
+	}
+	if self.EventCost != nil {
+		sRun.EventCost = self.EventCost.Clone()
+	}
+	return sRun
+}
+
+// newSMGSessionFromStored restores a session out of its dataDB representation
+func newSMGSessionFromStored(cgrID string, sRun *engine.StoredSessionRun) *SMGSession {
+	return &SMGSession{
+		CGRID:         cgrID,
+		RunID:         sRun.RunID,
+		Timezone:      sRun.Timezone,
+		EventStart:    SMGenericEvent(sRun.EventStart),
+		CD:            sRun.CD,
+		EventCost:     sRun.EventCost,
+		ExtraDuration: sRun.ExtraDuration,
+		LastUsage:     sRun.LastUsage,
+		LastDebit:     sRun.LastDebit,
+		TotalUsage:    sRun.TotalUsage,
+	}
+}
+
 // Session has ended, check debits and refund the extra charged duration
 func (self *SMGSession) close(usage time.Duration) (err error) {
 	self.mux.Lock()
@@ -64,7 +64,7 @@ type SMGReplicationConn struct {
 	Synchronous bool
 }
 
-func NewSMGeneric(cgrCfg *config.CGRConfig, rals, resS,
+func NewSMGeneric(cgrCfg *config.CGRConfig, dm *engine.DataManager, rals, resS,
 	splS, attrS, cdrsrv rpcclient.RpcClientConnection,
 	smgReplConns []*SMGReplicationConn, timezone string) *SMGeneric {
 	ssIdxCfg := cgrCfg.SessionSCfg().SessionIndexes
@@ -85,6 +85,7 @@ func NewSMGeneric(cgrCfg *config.CGRConfig, rals, resS,
 		cdrsrv = nil
 	}
 	return &SMGeneric{cgrCfg: cgrCfg,
+		dm:                 dm,
 		rals:               rals,
 		resS:               resS,
 		splS:               splS,
@@ -100,11 +101,14 @@ func NewSMGeneric(cgrCfg *config.CGRConfig, rals, resS,
 		pSessionsIndex:     make(map[string]map[string]map[string]utils.StringMap),
 		pSessionsRIndex:    make(map[string][]*riFieldNameVal),
 		sessionTerminators: make(map[string]*smgSessionTerminator),
+		storeInterval:      cgrCfg.SessionSCfg().StoreInterval,
+		stopBackup:         make(chan struct{}),
 		responseCache:      cache.NewResponseCache(cgrCfg.ResponseCacheTTL)}
 }
 
 type SMGeneric struct {
 	cgrCfg             *config.CGRConfig             // Separate from smCfg since there can be multiple
+	dm                 *engine.DataManager           // used to store active sessions
 	rals               rpcclient.RpcClientConnection // RALs connections
 	resS               rpcclient.RpcClientConnection // ResourceS connections
 	splS               rpcclient.RpcClientConnection // SupplierS connections
@@ -125,6 +129,8 @@ type SMGeneric struct {
 	pSIMux             sync.RWMutex                                     // protects pSessionsIndex
 	sessionTerminators map[string]*smgSessionTerminator                 // terminate and cleanup the session if timer expires
 	sTsMux             sync.RWMutex                                     // protects sessionTerminators
+	storeInterval      time.Duration                                    // interval to store active sessions on, -1 on each change
+	stopBackup         chan struct{}                                    // stops the storing loop
 	responseCache      *cache.ResponseCache                             // cache replies here
 }
 
@@ -388,7 +394,7 @@ func (smg *SMGeneric) sessionStart(evStart SMGenericEvent,
 	clntConn rpcclient.RpcClientConnection) (err error) {
 	cgrID := evStart.GetCGRID(utils.META_DEFAULT)
 	_, err = guardian.Guardian.Guard(func() (interface{}, error) { // Lock it on CGRID level
-		if pSS := smg.passiveToActive(cgrID); len(pSS) != 0 {
+		if pSS := smg.passiveToActive(cgrID, clntConn); len(pSS) != 0 {
 			return nil, nil // ToDo: handle here also debits
 		}
 		var sessionRuns []*engine.SessionRun
@@ -403,36 +409,51 @@ func (smg *SMGeneric) sessionStart(evStart SMGenericEvent,
 			smg.recordASession(s)
 			return nil, nil
 		}
-		stopDebitChan := make(chan struct{})
-		for _, sessionRun := range sessionRuns {
-			s := &SMGSession{CGRID: cgrID, EventStart: evStart,
+		ss := make([]*SMGSession, len(sessionRuns))
+		for i, sessionRun := range sessionRuns {
+			ss[i] = &SMGSession{CGRID: cgrID, EventStart: evStart,
 				RunID: sessionRun.DerivedCharger.RunID, Timezone: smg.Timezone,
 				rals: smg.rals, cdrsrv: smg.cdrsrv,
 				CD: sessionRun.CallDescriptor, clntConn: clntConn}
-			smg.recordASession(s)
+			smg.recordASession(ss[i])
 			//utils.Logger.Info(fmt.Sprintf("<SMGeneric> Starting session: %s, runId: %s", sessionId, s.runId))
-			if smg.cgrCfg.SessionSCfg().DebitInterval != 0 {
-				s.stopDebit = stopDebitChan
-				go s.debitLoop(smg.cgrCfg.SessionSCfg().DebitInterval)
-			}
+		}
+		if debitInterval := evStart.GetDebitInterval(smg.cgrCfg.SessionSCfg().DebitInterval); debitInterval != 0 {
+			smg.startDebitLoops(cgrID, ss, debitInterval)
 		}
 		return nil, nil
 	}, smg.cgrCfg.LockingTimeout, cgrID)
 	return
 }
 
+// startDebitLoops starts the automatic debits for the sessions with cgrID,
+// storing them after each debit when configured so
+func (smg *SMGeneric) startDebitLoops(cgrID string, ss []*SMGSession, debitInterval time.Duration) {
+	stopDebitChan := make(chan struct{})
+	for _, s := range ss {
+		s.stopDebit = stopDebitChan
+		go s.debitLoop(debitInterval, func() { smg.storeSessionOnChange(cgrID) })
+	}
+}
+
 // sessionEnd will end a session from outside
 func (smg *SMGeneric) sessionEnd(cgrID string, usage time.Duration) error {
 	_, err := guardian.Guardian.Guard(func() (interface{}, error) { // Lock it on UUID level
 		ss := smg.getSessions(cgrID, false)
 		if len(ss) == 0 {
-			if ss = smg.passiveToActive(cgrID); len(ss) == 0 {
+			if ss = smg.passiveToActive(cgrID, nil); len(ss) == 0 {
 				return nil, nil // ToDo: handle here also debits
 			}
 		}
 		if !smg.unrecordASession(cgrID) { // Unreference it early so we avoid concurrency
 			return nil, nil // Did not find the session so no need to close it anymore
 		}
+		if smg.sessionsStored() {
+			if err := smg.dm.RemoveStoredSession(cgrID); err != nil && err != utils.ErrNotFound {
+				utils.Logger.Warning(fmt.Sprintf("<SMGeneric> failed removing stored session: %s, error: %s",
+					cgrID, err.Error()))
+			}
+		}
 		for idx, s := range ss[cgrID] {
 			if s.RunID == utils.META_NONE {
 				continue
@@ -474,7 +495,7 @@ func (smg *SMGeneric) sessionRelocate(initialID, cgrID, newOriginID string) erro
 		}
 		ss := smg.getSessions(initialID, false)
 		if len(ss) == 0 { // No need of relocation
-			if ss = smg.passiveToActive(initialID); len(ss) == 0 {
+			if ss = smg.passiveToActive(initialID, nil); len(ss) == 0 {
 				return nil, utils.ErrNotFound
 			}
 		}
@@ -495,8 +516,7 @@ func (smg *SMGeneric) sessionRelocate(initialID, cgrID, newOriginID string) erro
 
 // replicateSessions will replicate session based on configuration
 func (smg *SMGeneric) replicateSessionsWithID(cgrID string, passiveSessions bool, smgReplConns []*SMGReplicationConn) (err error) {
-	if len(smgReplConns) == 0 ||
-		(smg.cgrCfg.SessionSCfg().DebitInterval != 0 && !passiveSessions) { // Replicating active not supported
+	if len(smgReplConns) == 0 {
 		return
 	}
 	ssMux := &smg.aSessionsMux
@@ -507,6 +527,10 @@ func (smg *SMGeneric) replicateSessionsWithID(cgrID string, passiveSessions bool
 	}
 	ssMux.RLock()
 	ss := ssMp[cgrID]
+	if !passiveSessions && len(ss) != 0 && ss[0].stopDebit != nil { // Replicating active with debit loop not supported
+		ssMux.RUnlock()
+		return
+	}
 	if len(ss) != 0 {
 		ss[0].mux.RLock() // lock session so we can clone it after releasing the map lock
 	}
@@ -537,6 +561,123 @@ func (smg *SMGeneric) replicateSessionsWithID(cgrID string, passiveSessions bool
 	return
 }
 
+// sessionsStored returns true if active sessions should be stored into dataDB
+func (smg *SMGeneric) sessionsStored() bool {
+	return smg.dm != nil && smg.storeInterval != 0
+}
+
+// storeSessionWithID will store the active session with cgrID into dataDB or remove it if no longer active
+func (smg *SMGeneric) storeSessionWithID(cgrID string) (err error) {
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) { // Lock it on CGRID level so we do not store ended sessions
+		ss := smg.getSessions(cgrID, false)[cgrID]
+		if len(ss) == 0 {
+			if err := smg.dm.RemoveStoredSession(cgrID); err != nil && err != utils.ErrNotFound {
+				return nil, err
+			}
+			return nil, nil
+		}
+		sSession := &engine.StoredSession{CGRID: cgrID,
+			Runs: make([]*engine.StoredSessionRun, len(ss))}
+		for i, s := range ss {
+			s.mux.RLock()
+			sSession.Runs[i] = s.asStoredSessionRun()
+			s.mux.RUnlock()
+		}
+		return nil, smg.dm.SetStoredSession(sSession)
+	}, smg.cgrCfg.LockingTimeout, cgrID)
+	return
+}
+
+// storeSessionOnChange stores the session with cgrID when storing on each change is configured
+func (smg *SMGeneric) storeSessionOnChange(cgrID string) {
+	if !smg.sessionsStored() || smg.storeInterval != -1 {
+		return
+	}
+	if err := smg.storeSessionWithID(cgrID); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<SMGeneric> failed storing session: %s, error: %s",
+			cgrID, err.Error()))
+	}
+}
+
+// storeSessions represents one task of complete backup
+func (smg *SMGeneric) storeSessions() {
+	for cgrID := range smg.getSessions("", false) {
+		if err := smg.storeSessionWithID(cgrID); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<SMGeneric> failed storing session: %s, error: %s",
+				cgrID, err.Error()))
+		}
+	}
+}
+
+// runBackup will regularly store the active sessions into dataDB
+func (smg *SMGeneric) runBackup() {
+	if smg.storeInterval <= 0 {
+		return
+	}
+	for {
+		select {
+		case <-smg.stopBackup:
+			return
+		default:
+		}
+		smg.storeSessions()
+		time.Sleep(smg.storeInterval)
+	}
+}
+
+// restoreSessions loads the sessions stored in dataDB as passive ones,
+// they become active again as soon as their agents send updates for them
+func (smg *SMGeneric) restoreSessions() (err error) {
+	keys, err := smg.dm.DataDB().GetKeysForPrefix(utils.SessionsPrefix)
+	if err != nil {
+		return
+	}
+	cgrIDs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		cgrID := key[len(utils.SessionsPrefix):]
+		sSession, err := smg.dm.GetStoredSession(cgrID)
+		if err != nil {
+			return err
+		}
+		ss := make([]*SMGSession, len(sSession.Runs))
+		for i, sRun := range sSession.Runs {
+			ss[i] = newSMGSessionFromStored(cgrID, sRun)
+		}
+		if err = smg.setPassiveSessions(cgrID, ss); err != nil {
+			return err
+		}
+		cgrIDs = append(cgrIDs, cgrID)
+	}
+	if len(cgrIDs) == 0 {
+		return
+	}
+	utils.Logger.Info(fmt.Sprintf("<SMGeneric> restored %d sessions from dataDB", len(cgrIDs)))
+	time.AfterFunc(smg.cgrCfg.SessionSCfg().RestoreTimeout,
+		func() { smg.terminateUnclaimedSessions(cgrIDs) })
+	return
+}
+
+// terminateUnclaimedSessions ends the restored sessions which were not claimed back by their agents,
+// these are considered finished while the engine was down so we charge their last known usage
+func (smg *SMGeneric) terminateUnclaimedSessions(cgrIDs []string) {
+	for _, cgrID := range cgrIDs {
+		pSS := smg.getSessions(cgrID, true)
+		if len(pSS) == 0 { // claimed or removed meanwhile
+			continue
+		}
+		s := pSS[cgrID][0]
+		utils.Logger.Warning(fmt.Sprintf("<SMGeneric> terminating restored session: %s, not claimed by any agent", cgrID))
+		smg.sessionEnd(cgrID, s.TotalUsage)
+		if smg.cdrsrv == nil {
+			continue
+		}
+		cdr := s.EventStart.AsCDR(smg.cgrCfg, smg.Timezone)
+		cdr.Usage = s.TotalUsage
+		var reply string
+		smg.cdrsrv.Call("CdrsV1.ProcessCDR", cdr, &reply)
+	}
+}
+
 // getSessions is used to return in a thread-safe manner active or passive sessions
 func (smg *SMGeneric) getSessions(cgrID string, passiveSessions bool) (aSS map[string][]*SMGSession) {
 	ssMux := &smg.aSessionsMux
@@ -606,24 +747,38 @@ func (smg *SMGeneric) deletePassiveSessions(cgrID string) {
 }
 
 // passiveToActive will transition the sessions from passive to active table
-// ToDo: test
-func (smg *SMGeneric) passiveToActive(cgrID string) (pSessions map[string][]*SMGSession) {
+// clntConn, if not nil, becomes the connection used to disconnect the sessions
+// and the automatic debits are resumed since the agent continues the sessions
+func (smg *SMGeneric) passiveToActive(cgrID string,
+	clntConn rpcclient.RpcClientConnection) (pSessions map[string][]*SMGSession) {
 	pSessions = smg.getSessions(cgrID, true)
 	if len(pSessions) == 0 {
 		return
 	}
+	var debitSS []*SMGSession
 	for _, s := range pSessions[cgrID] {
 		smg.recordASession(s)
 		s.rals = smg.rals
 		s.cdrsrv = smg.cdrsrv
+		if clntConn != nil {
+			s.clntConn = clntConn
+			if s.CD != nil && s.RunID != utils.META_NONE {
+				debitSS = append(debitSS, s)
+			}
+		}
 	}
 	smg.deletePassiveSessions(cgrID)
+	if len(debitSS) != 0 {
+		if debitInterval := debitSS[0].EventStart.GetDebitInterval(
+			smg.cgrCfg.SessionSCfg().DebitInterval); debitInterval != 0 {
+			smg.startDebitLoops(cgrID, debitSS, debitInterval)
+		}
+	}
 	return
 }
 
-// asActiveSessions returns sessions from either active or passive table as []*ActiveSession
-func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSessions bool) (aSessions []*ActiveSession, counter int, err error) {
-	aSessions = make([]*ActiveSession, 0) // Make sure we return at least empty list and not nil
+// filterSessions returns sessions from either active or passive table matching all the filters
+func (smg *SMGeneric) filterSessions(fltrs map[string]string, passiveSessions bool) (remainingSessions []*SMGSession, err error) {
 	// Check first based on indexes so we can downsize the list of matching sessions
 	matchingSessionIDs, checkedFilters := smg.getSessionIDsMatchingIndexes(fltrs, passiveSessions)
 	if len(matchingSessionIDs) == 0 && len(checkedFilters) != 0 {
@@ -634,7 +789,6 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 			delete(fltrs, fltrFldName)
 		}
 	}
-	var remainingSessions []*SMGSession // Survived index matching
 	var ss map[string][]*SMGSession
 	if passiveSessions {
 		ss = smg.getSessions(fltrs[utils.CGRID], true)
@@ -645,7 +799,7 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 		if _, hasCGRID := matchingSessionIDs[cgrID]; !hasCGRID && len(checkedFilters) != 0 {
 			continue
 		}
-		for _, s := range sGrp {
+		for _, s := range sGrp { // Survived index matching
 			remainingSessions = append(remainingSessions, s)
 		}
 	}
@@ -653,7 +807,7 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 		for i := 0; i < len(remainingSessions); {
 			sMp, err := remainingSessions[i].EventStart.AsMapStringString()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 			if _, hasRunID := sMp[utils.MEDI_RUNID]; !hasRunID {
 				sMp[utils.MEDI_RUNID] = utils.META_DEFAULT
@@ -672,6 +826,16 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 			i++
 		}
 	}
+	return
+}
+
+// asActiveSessions returns sessions from either active or passive table as []*ActiveSession
+func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSessions bool) (aSessions []*ActiveSession, counter int, err error) {
+	aSessions = make([]*ActiveSession, 0) // Make sure we return at least empty list and not nil
+	remainingSessions, err := smg.filterSessions(fltrs, passiveSessions)
+	if err != nil {
+		return nil, 0, err
+	}
 	if count {
 		return nil, len(remainingSessions), nil
 	}
@@ -691,6 +855,9 @@ func (smg *SMGeneric) GetMaxUsage(gev SMGenericEvent) (maxUsage time.Duration, e
 	}
 	defer smg.responseCache.Cache(cacheKey, &cache.CacheItem{Value: maxUsage, Err: err})
 	storedCdr := gev.AsCDR(config.CgrConfig(), smg.Timezone)
+	if storedCdr.Usage == 0 { // limit to the maximum call duration out of event, RALs will apply the configured one otherwise
+		storedCdr.Usage = gev.GetMaxCallDuration(0)
+	}
 	var maxDur float64
 	if err = smg.rals.Call("Responder.GetDerivedMaxSessionTime", storedCdr, &maxDur); err != nil {
 		return
@@ -712,7 +879,8 @@ func (smg *SMGeneric) InitiateSession(gev SMGenericEvent, clnt rpcclient.RpcClie
 		smg.sessionEnd(cgrID, 0)
 		return
 	}
-	if smg.cgrCfg.SessionSCfg().DebitInterval != 0 { // Session handled by debit loop
+	if gev.GetDebitInterval(smg.cgrCfg.SessionSCfg().DebitInterval) != 0 { // Session handled by debit loop
+		smg.storeSessionOnChange(cgrID)
 		maxUsage = time.Duration(-1 * time.Second)
 		return
 	}
@@ -731,10 +899,6 @@ func (smg *SMGeneric) UpdateSession(gev SMGenericEvent, clnt rpcclient.RpcClient
 		return item.Value.(time.Duration), item.Err
 	}
 	defer smg.responseCache.Cache(cacheKey, &cache.CacheItem{Value: maxUsage, Err: err})
-	if smg.cgrCfg.SessionSCfg().DebitInterval != 0 { // Not possible to update a session with debit loop active
-		err = errors.New("ACTIVE_DEBIT_LOOP")
-		return
-	}
 	if gev.HasField(utils.InitialOriginID) {
 		initialCGRID := gev.GetCGRID(utils.InitialOriginID)
 		err = smg.sessionRelocate(initialCGRID, cgrID, gev.GetOriginID(utils.META_DEFAULT))
@@ -745,6 +909,7 @@ func (smg *SMGeneric) UpdateSession(gev SMGenericEvent, clnt rpcclient.RpcClient
 			return
 		}
 		smg.replicateSessionsWithID(initialCGRID, false, smg.smgReplConns)
+		smg.storeSessionOnChange(initialCGRID)
 	}
 	smg.resetTerminatorTimer(cgrID,
 		gev.GetSessionTTL(
@@ -759,7 +924,7 @@ func (smg *SMGeneric) UpdateSession(gev SMGenericEvent, clnt rpcclient.RpcClient
 		return
 	}
 	if maxUsage, err = gev.GetMaxUsage(utils.META_DEFAULT,
-		smg.cgrCfg.SessionSCfg().MaxCallDuration); err != nil {
+		gev.GetMaxCallDuration(smg.cgrCfg.SessionSCfg().MaxCallDuration)); err != nil {
 		if err == utils.ErrNotFound {
 			err = utils.ErrMandatoryIeMissing
 		}
@@ -767,13 +932,18 @@ func (smg *SMGeneric) UpdateSession(gev SMGenericEvent, clnt rpcclient.RpcClient
 	}
 	aSessions := smg.getSessions(cgrID, false)
 	if len(aSessions) == 0 {
-		if aSessions = smg.passiveToActive(cgrID); len(aSessions) == 0 {
+		if aSessions = smg.passiveToActive(cgrID, clnt); len(aSessions) == 0 {
 			utils.Logger.Err(fmt.Sprintf("<SMGeneric> SessionUpdate with no active sessions for event: <%s>", cgrID))
 			err = rpcclient.ErrSessionNotFound
 			return
 		}
 	}
+	if aSessions[cgrID][0].stopDebit != nil { // not possible to update a session with debit loop active
+		err = errors.New("ACTIVE_DEBIT_LOOP")
+		return
+	}
 	defer smg.replicateSessionsWithID(gev.GetCGRID(utils.META_DEFAULT), false, smg.smgReplConns)
+	defer smg.storeSessionOnChange(cgrID)
 	for _, s := range aSessions[cgrID] {
 		if s.RunID == utils.META_NONE {
 			maxUsage = time.Duration(-1 * time.Second)
@@ -814,7 +984,7 @@ func (smg *SMGeneric) TerminateSession(gev SMGenericEvent, clnt rpcclient.RpcCli
 			if sessionIDs = smg.getSessionIDsForPrefix(sessionIDPrefix, false); len(sessionIDs) == 0 {
 				sessionIDs = smg.getSessionIDsForPrefix(sessionIDPrefix, true)
 				for _, sessionID := range sessionIDs { // activate sessions for prefix
-					smg.passiveToActive(sessionID)
+					smg.passiveToActive(sessionID, nil)
 				}
 			}
 		}
@@ -838,7 +1008,7 @@ func (smg *SMGeneric) TerminateSession(gev SMGenericEvent, clnt rpcclient.RpcCli
 	for _, sessionID := range sessionIDs {
 		aSessions := smg.getSessions(sessionID, false)
 		if len(aSessions) == 0 {
-			if aSessions = smg.passiveToActive(cgrID); len(aSessions) == 0 {
+			if aSessions = smg.passiveToActive(cgrID, nil); len(aSessions) == 0 {
 				utils.Logger.Err(fmt.Sprintf("<SMGeneric> SessionTerminate with no active sessions for cgrID: <%s>", cgrID))
 				continue
 			}
@@ -980,12 +1150,25 @@ func (smg *SMGeneric) ProcessCDR(gev SMGenericEvent) (err error) {
 	return
 }
 
-func (smg *SMGeneric) Connect() error {
-	return nil
+// Connect restores the sessions stored in dataDB and starts the storing loop
+func (smg *SMGeneric) Connect() (err error) {
+	if !smg.sessionsStored() {
+		return
+	}
+	if err = smg.restoreSessions(); err != nil {
+		return
+	}
+	go smg.runBackup()
+	return
 }
 
 // System shutdown
 func (smg *SMGeneric) Shutdown() error {
+	if smg.sessionsStored() { // keep the sessions in dataDB so we can restore them on next start
+		close(smg.stopBackup)
+		smg.storeSessions()
+		return nil
+	}
 	for ssId := range smg.getSessions("", false) { // Force sessions shutdown
 		smg.sessionEnd(ssId, time.Duration(smg.cgrCfg.MaxCallDuration))
 	}
@@ -1286,11 +1469,109 @@ func (smg *SMGeneric) BiRPCV1ReplicatePassiveSessions(clnt rpcclient.RpcClientCo
 	return
 }
 
+// sessionFilters builds the filters used to match active sessions out of API arguments
+func sessionFilters(cgrID string, filters map[string]string) map[string]string {
+	fltrs := make(map[string]string)
+	for fldName, fldVal := range filters {
+		if fldVal == "" {
+			fldVal = utils.META_NONE
+		}
+		fltrs[fldName] = fldVal
+	}
+	if cgrID != "" {
+		fltrs[utils.CGRID] = cgrID
+	}
+	return fltrs
+}
+
+type ArgsForceDisconnect struct {
+	CGRID   string            // disconnect only the session with this CGRID
+	Filters map[string]string // disconnect all active sessions matching these fields
+	Reason  string            // passed to the agent, defaults to FORCED_DISCONNECT
+}
+
+// BiRPCV1ForceDisconnect will send a disconnect request to the agents owning the matched active sessions
+// the sessions themselves are terminated when the agents report back the hangup
+func (smg *SMGeneric) BiRPCV1ForceDisconnect(clnt rpcclient.RpcClientConnection,
+	args ArgsForceDisconnect, reply *string) (err error) {
+	if args.CGRID == "" && len(args.Filters) == 0 { // do not disconnect all the sessions by mistake
+		return utils.NewErrMandatoryIeMissing(utils.CGRID, "Filters")
+	}
+	ss, err := smg.filterSessions(sessionFilters(args.CGRID, args.Filters), false)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	} else if len(ss) == 0 {
+		return utils.ErrNotFound
+	}
+	reason := args.Reason
+	if reason == "" {
+		reason = utils.ErrForcedDisconnect.Error()
+	}
+	disconnected := make(map[string]bool) // derived runs share the client connection, disconnect only once
+	var failed bool
+	for _, s := range ss {
+		if disconnected[s.CGRID] {
+			continue
+		}
+		disconnected[s.CGRID] = true
+		if err := s.disconnectSession(reason); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<SMGeneric> Could not force disconnect session: %s, error: %s",
+				s.CGRID, err.Error()))
+			failed = true
+		}
+	}
+	if failed {
+		return utils.ErrPartiallyExecuted
+	}
+	*reply = utils.OK
+	return
+}
+
+type ArgsReAuthorize struct {
+	CGRID   string            // re-authorize only the session with this CGRID
+	Filters map[string]string // re-authorize all active sessions matching these fields
+}
+
+// BiRPCV1ReAuthorize will ask the agents owning the matched active sessions to re-authorize them
+// eg: after a balance topup so the clients can request again units from us
+func (smg *SMGeneric) BiRPCV1ReAuthorize(clnt rpcclient.RpcClientConnection,
+	args ArgsReAuthorize, reply *string) (err error) {
+	if args.CGRID == "" && len(args.Filters) == 0 { // do not re-authorize all the sessions by mistake
+		return utils.NewErrMandatoryIeMissing(utils.CGRID, "Filters")
+	}
+	ss, err := smg.filterSessions(sessionFilters(args.CGRID, args.Filters), false)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	} else if len(ss) == 0 {
+		return utils.ErrNotFound
+	}
+	reAuthorized := make(map[string]bool) // derived runs share the client connection, re-authorize only once
+	var failed bool
+	for _, s := range ss {
+		if reAuthorized[s.CGRID] {
+			continue
+		}
+		reAuthorized[s.CGRID] = true
+		if err := s.reAuthorizeSession(); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<SMGeneric> Could not re-authorize session: %s, error: %s",
+				s.CGRID, err.Error()))
+			failed = true
+		}
+	}
+	if failed {
+		return utils.ErrPartiallyExecuted
+	}
+	*reply = utils.OK
+	return
+}
+
 type V1AuthorizeArgs struct {
-	GetAttributes      bool
-	AuthorizeResources bool
-	GetMaxUsage        bool
-	GetSuppliers       bool
+	GetAttributes         bool
+	AuthorizeResources    bool
+	GetMaxUsage           bool
+	GetSuppliers          bool
+	SuppliersMaxCost      string
+	SuppliersIgnoreErrors bool
 	utils.CGREvent
 	utils.Paginator
 }
@@ -1357,8 +1638,10 @@ func (smg *SMGeneric) BiRPCv1AuthorizeEvent(clnt rpcclient.RpcClientConnection,
 		}
 		var splsReply engine.SortedSuppliers
 		sArgs := &engine.ArgsGetSuppliers{
-			CGREvent:  args.CGREvent,
-			Paginator: args.Paginator,
+			IgnoreErrors: args.SuppliersIgnoreErrors,
+			MaxCost:      args.SuppliersMaxCost,
+			CGREvent:     args.CGREvent,
+			Paginator:    args.Paginator,
 		}
 		if err = smg.splS.Call(utils.SupplierSv1GetSuppliers,
 			sArgs, &splsReply); err != nil {
@@ -20,8 +20,10 @@ package sessionmanager
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -34,7 +36,7 @@ func init() {
 }
 
 func TestSMGSessionIndexing(t *testing.T) {
-	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, "UTC")
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, "UTC")
 	smGev := SMGenericEvent{
 		utils.EVENT_NAME:       "TEST_EVENT",
 		utils.TOR:              "*voice",
@@ -389,7 +391,7 @@ func TestSMGSessionIndexing(t *testing.T) {
 }
 
 func TestSMGActiveSessions(t *testing.T) {
-	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, "UTC")
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, "UTC")
 	smGev1 := SMGenericEvent{
 		utils.EVENT_NAME:       "TEST_EVENT",
 		utils.TOR:              "*voice",
@@ -463,7 +465,7 @@ func TestSMGActiveSessions(t *testing.T) {
 }
 
 func TestGetPassiveSessions(t *testing.T) {
-	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, "UTC")
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, "UTC")
 	if pSS := smg.getSessions("", true); len(pSS) != 0 {
 		t.Errorf("PassiveSessions: %+v", pSS)
 	}
@@ -532,3 +534,263 @@ func TestGetPassiveSessions(t *testing.T) {
 		t.Errorf("PassiveSessions: %+v", pSS)
 	}
 }
+
+type smgClientRecorder struct {
+	disconnected []utils.AttrDisconnectSession
+	reAuthorized []utils.AttrReAuthorizeSession
+	methods      []string
+}
+
+func (r *smgClientRecorder) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	r.methods = append(r.methods, serviceMethod)
+	switch serviceMethod {
+	case "SMGClientV1.DisconnectSession":
+		r.disconnected = append(r.disconnected, args.(utils.AttrDisconnectSession))
+	case "SMGClientV1.ReAuthorizeSession", "SessionSv1.ReAuthorizeSession":
+		r.reAuthorized = append(r.reAuthorized, args.(utils.AttrReAuthorizeSession))
+	default:
+		return utils.ErrNotImplemented
+	}
+	*(reply.(*string)) = utils.OK
+	return nil
+}
+
+func TestSMGForceDisconnect(t *testing.T) {
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, "UTC")
+	clnt := new(smgClientRecorder)
+	smGev1 := SMGenericEvent{
+		utils.EVENT_NAME:  "TEST_EVENT",
+		utils.TOR:         "*voice",
+		utils.OriginID:    "111",
+		utils.Account:     "account1",
+		utils.Destination: "+4986517174963",
+		utils.Tenant:      "cgrates.org",
+		utils.RequestType: "*prepaid",
+		utils.AnswerTime:  "2015-11-09 14:22:02",
+		utils.OriginHost:  "127.0.0.1",
+	}
+	cgrID1 := smGev1.GetCGRID(utils.META_DEFAULT)
+	smg.recordASession(&SMGSession{CGRID: cgrID1, RunID: utils.META_DEFAULT,
+		EventStart: smGev1, clntConn: clnt})
+	smg.recordASession(&SMGSession{CGRID: cgrID1, RunID: "secondRun",
+		EventStart: smGev1, clntConn: clnt})
+	smGev2 := SMGenericEvent{
+		utils.EVENT_NAME:  "TEST_EVENT",
+		utils.TOR:         "*voice",
+		utils.OriginID:    "222",
+		utils.Account:     "account2",
+		utils.Destination: "+4986517174963",
+		utils.Tenant:      "itsyscom.com",
+		utils.RequestType: "*prepaid",
+		utils.AnswerTime:  "2015-11-09 14:22:02",
+		utils.OriginHost:  "127.0.0.1",
+	}
+	smg.recordASession(&SMGSession{CGRID: smGev2.GetCGRID(utils.META_DEFAULT),
+		RunID: utils.META_DEFAULT, EventStart: smGev2})
+	var reply string
+	if err := smg.BiRPCV1ForceDisconnect(nil, ArgsForceDisconnect{}, &reply); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing(utils.CGRID, "Filters").Error() {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := smg.BiRPCV1ForceDisconnect(nil,
+		ArgsForceDisconnect{Filters: map[string]string{utils.Tenant: "nonexistent.org"}},
+		&reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := smg.BiRPCV1ForceDisconnect(nil,
+		ArgsForceDisconnect{CGRID: cgrID1}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Received reply: %s", reply)
+	}
+	if len(clnt.disconnected) != 1 { // both runs share the same client, disconnect once
+		t.Errorf("Disconnects: %+v", clnt.disconnected)
+	} else if clnt.disconnected[0].Reason != utils.ErrForcedDisconnect.Error() {
+		t.Errorf("Disconnect reason: %s", clnt.disconnected[0].Reason)
+	}
+	// second session has no client connection to disconnect through
+	if err := smg.BiRPCV1ForceDisconnect(nil,
+		ArgsForceDisconnect{Filters: map[string]string{utils.RequestType: "*prepaid"},
+			Reason: "MAINTENANCE"}, &reply); err != utils.ErrPartiallyExecuted {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrPartiallyExecuted, err)
+	}
+	if len(clnt.disconnected) != 2 {
+		t.Errorf("Disconnects: %+v", clnt.disconnected)
+	} else if clnt.disconnected[1].Reason != "MAINTENANCE" {
+		t.Errorf("Disconnect reason: %s", clnt.disconnected[1].Reason)
+	}
+}
+
+func TestSMGReAuthorize(t *testing.T) {
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, nil, nil, nil, nil, "UTC")
+	clnt := new(smgClientRecorder)
+	smGev := SMGenericEvent{
+		utils.EVENT_NAME:  "TEST_EVENT",
+		utils.TOR:         "*data",
+		utils.OriginID:    "reauth1",
+		utils.Account:     "account1",
+		utils.Destination: "data",
+		utils.Tenant:      "cgrates.org",
+		utils.RequestType: "*prepaid",
+		utils.AnswerTime:  "2015-11-09 14:22:02",
+		utils.OriginHost:  "127.0.0.1",
+	}
+	cgrID := smGev.GetCGRID(utils.META_DEFAULT)
+	smg.recordASession(&SMGSession{CGRID: cgrID, RunID: utils.META_DEFAULT,
+		EventStart: smGev, clntConn: clnt})
+	smg.recordASession(&SMGSession{CGRID: cgrID, RunID: "secondRun",
+		EventStart: smGev, clntConn: clnt})
+	var reply string
+	if err := smg.BiRPCV1ReAuthorize(nil, ArgsReAuthorize{}, &reply); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing(utils.CGRID, "Filters").Error() {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := smg.BiRPCV1ReAuthorize(nil,
+		ArgsReAuthorize{Filters: map[string]string{utils.Account: "account2"}},
+		&reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := smg.BiRPCV1ReAuthorize(nil,
+		ArgsReAuthorize{Filters: map[string]string{utils.Account: "account1"}},
+		&reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Received reply: %s", reply)
+	}
+	if len(clnt.reAuthorized) != 1 { // both runs share the same client, re-authorize once
+		t.Errorf("ReAuthorized: %+v", clnt.reAuthorized)
+	} else if !reflect.DeepEqual(map[string]interface{}(smGev), clnt.reAuthorized[0].EventStart) {
+		t.Errorf("Expecting: %+v, received: %+v", smGev, clnt.reAuthorized[0].EventStart)
+	}
+	if len(clnt.disconnected) != 0 {
+		t.Errorf("Disconnects: %+v", clnt.disconnected)
+	}
+	// SessionSv1 clients are reached over their own API
+	smGev2 := smGev.Clone()
+	smGev2[utils.OriginID] = "reauth2"
+	smGev2[utils.Account] = "account2"
+	smg.recordASession(&SMGSession{CGRID: smGev2.GetCGRID(utils.META_DEFAULT), RunID: utils.META_DEFAULT,
+		EventStart: smGev2, clntConn: clnt, clientProto: 1.0})
+	if err := smg.BiRPCV1ReAuthorize(nil,
+		ArgsReAuthorize{Filters: map[string]string{utils.Account: "account2"}},
+		&reply); err != nil {
+		t.Error(err)
+	}
+	if eMethods := []string{"SMGClientV1.ReAuthorizeSession", "SessionSv1.ReAuthorizeSession"}; !reflect.DeepEqual(eMethods, clnt.methods) {
+		t.Errorf("Expecting: %+v, received: %+v", eMethods, clnt.methods)
+	}
+}
+
+func TestSMGStoreRestoreSessions(t *testing.T) {
+	data, _ := engine.NewMapStorage()
+	dm := engine.NewDataManager(data)
+	smg := NewSMGeneric(smgCfg, dm, nil, nil, nil, nil, nil, nil, "UTC")
+	smg.storeInterval = -1
+	smGev := SMGenericEvent{
+		utils.EVENT_NAME:  "TEST_EVENT",
+		utils.TOR:         "*voice",
+		utils.OriginID:    "333",
+		utils.Account:     "account1",
+		utils.Destination: "+4986517174963",
+		utils.Tenant:      "cgrates.org",
+		utils.RequestType: "*prepaid",
+		utils.AnswerTime:  "2015-11-09 14:22:02",
+		utils.OriginHost:  "127.0.0.1",
+	}
+	cgrID := smGev.GetCGRID(utils.META_DEFAULT)
+	smg.recordASession(&SMGSession{CGRID: cgrID, RunID: utils.META_NONE,
+		EventStart: smGev, TotalUsage: time.Duration(30 * time.Second)})
+	smg.storeSessionOnChange(cgrID)
+	if sSession, err := dm.GetStoredSession(cgrID); err != nil {
+		t.Fatal(err)
+	} else if len(sSession.Runs) != 1 ||
+		sSession.Runs[0].TotalUsage != time.Duration(30*time.Second) {
+		t.Errorf("Received: %s", utils.ToJSON(sSession))
+	}
+	// restart, sessions should come back as passive
+	smg = NewSMGeneric(smgCfg, dm, nil, nil, nil, nil, nil, nil, "UTC")
+	smg.storeInterval = -1
+	if err := smg.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if aSS := smg.getSessions(cgrID, false); len(aSS) != 0 {
+		t.Errorf("Active sessions: %+v", aSS)
+	}
+	clnt := new(smgClientRecorder)
+	if aSS := smg.passiveToActive(cgrID, clnt); len(aSS[cgrID]) != 1 {
+		t.Errorf("Activated sessions: %+v", aSS)
+	} else if s := aSS[cgrID][0]; s.TotalUsage != time.Duration(30*time.Second) ||
+		s.EventStart.GetOriginID(utils.META_DEFAULT) != "333" {
+		t.Errorf("Restored session: %+v", s)
+	} else if s.clntConn != clnt {
+		t.Errorf("Client connection not set on restored session")
+	}
+	if err := smg.sessionEnd(cgrID, time.Duration(35*time.Second)); err != nil {
+		t.Error(err)
+	}
+	if _, err := dm.GetStoredSession(cgrID); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+// smgFailingRALs fails all the requests so the debit loops end by disconnecting the session
+type smgFailingRALs struct{}
+
+func (smgFailingRALs) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.ErrServerError
+}
+
+// smgDisconnectNotifier notifies the disconnects received out of the debit loops
+type smgDisconnectNotifier chan utils.AttrDisconnectSession
+
+func (n smgDisconnectNotifier) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if serviceMethod != "SMGClientV1.DisconnectSession" {
+		return utils.ErrNotImplemented
+	}
+	n <- args.(utils.AttrDisconnectSession)
+	*(reply.(*string)) = utils.OK
+	return nil
+}
+
+func TestSMGPassiveToActiveDebitLoop(t *testing.T) {
+	smg := NewSMGeneric(smgCfg, nil, smgFailingRALs{}, nil, nil, nil, nil, nil, "UTC")
+	smGev := SMGenericEvent{
+		utils.EVENT_NAME:       "TEST_EVENT",
+		utils.TOR:              "*voice",
+		utils.OriginID:         "debitloop1",
+		utils.Account:          "account1",
+		utils.Destination:      "+4986517174963",
+		utils.Tenant:           "cgrates.org",
+		utils.RequestType:      "*prepaid",
+		utils.AnswerTime:       "2015-11-09 14:22:02",
+		utils.CGRDebitInterval: "10s",
+	}
+	cgrID := smGev.GetCGRID(utils.META_DEFAULT)
+	if err := smg.setPassiveSessions(cgrID, []*SMGSession{&SMGSession{CGRID: cgrID,
+		RunID: utils.META_DEFAULT, EventStart: smGev, CD: &engine.CallDescriptor{}}}); err != nil {
+		t.Fatal(err)
+	}
+	if aSS := smg.passiveToActive(cgrID, nil); len(aSS[cgrID]) != 1 {
+		t.Fatalf("Activated sessions: %+v", aSS)
+	} else if aSS[cgrID][0].stopDebit != nil {
+		t.Error("Debit loop started without client connection")
+	}
+	if err := smg.setPassiveSessions(cgrID, []*SMGSession{&SMGSession{CGRID: cgrID,
+		RunID: utils.META_DEFAULT, EventStart: smGev, CD: &engine.CallDescriptor{}}}); err != nil {
+		t.Fatal(err)
+	}
+	clnt := make(smgDisconnectNotifier, 1)
+	if aSS := smg.passiveToActive(cgrID, clnt); len(aSS[cgrID]) != 1 {
+		t.Fatalf("Activated sessions: %+v", aSS)
+	} else if aSS[cgrID][0].stopDebit == nil {
+		t.Error("Debit loop not resumed")
+	}
+	select {
+	case dscArgs := <-clnt:
+		if dscArgs.Reason != utils.ErrServerError.Error() {
+			t.Errorf("Disconnect reason: %s", dscArgs.Reason)
+		}
+	case <-time.After(time.Second):
+		t.Error("Debit loop not running")
+	}
+}
@@ -1269,6 +1269,14 @@ type TPResource struct {
 	Stored             bool
 	Weight             float64  // Weight to sort the ResourceLimits
 	Thresholds         []string // Thresholds to check after changing Limit
+	RateInterval       string
+	TimedLimits        []*TPResourceTimedLimit
+}
+
+// TPResourceTimedLimit overrides the Limit of a TPResource while the timing is active
+type TPResourceTimedLimit struct {
+	TimingID string
+	Limit    string
 }
 
 // TPActivationInterval represents an activation interval for an item
@@ -1328,6 +1336,24 @@ type AttrDisconnectSession struct {
 	Reason     string
 }
 
+// DiameterPeer is the state of one peer connected to DiameterAgent
+type DiameterPeer struct {
+	OriginHost       string
+	OriginRealm      string
+	RemoteAddr       string
+	Listener         string // address of the listener the peer is connected to
+	State            string
+	Applications     []int // applications negotiated in CER/CEA
+	ConnectedAt      time.Time
+	LastActivity     time.Time
+	WatchdogFailures int
+}
+
+// Attributes to send on SessionReAuthorize by SMG
+type AttrReAuthorizeSession struct {
+	EventStart map[string]interface{}
+}
+
 // TPStats is used in APIs to manage remotely offline Stats config
 type TPStats struct {
 	TPid               string
@@ -1343,6 +1369,7 @@ type TPStats struct {
 	Weight             float64
 	MinItems           int
 	Thresholds         []string
+	BucketInterval     string
 }
 
 type MetricWithParams struct {
@@ -1406,6 +1433,7 @@ type TPSupplierProfile struct {
 type TPAttribute struct {
 	FieldName  string
 	Initial    string
+	Type       string
 	Substitute string
 	Append     bool
 }
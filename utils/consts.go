@@ -222,6 +222,7 @@ const (
 	ResourceProfilesPrefix        = "rsp_"
 	ThresholdPrefix               = "thd_"
 	TimingsPrefix                 = "tmg_"
+	SessionsPrefix                = "ses_"
 	FilterPrefix                  = "ftr_"
 	FilterIndex                   = "fti_"
 	CDR_STATS_PREFIX              = "cst_"
@@ -251,6 +252,8 @@ const (
 	META_CONSTANT                 = "*constant"
 	META_FILLER                   = "*filler"
 	META_HANDLER                  = "*handler"
+	MetaUsageDifference           = "*usage_difference"
+	MetaVariable                  = "*variable"
 	META_HTTP_POST                = "*http_post"
 	MetaHTTPjson                  = "*http_json"
 	MetaHTTPjsonCDR               = "*http_json_cdr"
@@ -330,6 +333,8 @@ const (
 	SessionTTLMaxDelay           = "SessionTTLMaxDelay"
 	SessionTTLLastUsed           = "SessionTTLLastUsed"
 	SessionTTLUsage              = "SessionTTLUsage"
+	CGRDebitInterval             = "CGRDebitInterval"
+	CGRMaxCallDuration           = "CGRMaxCallDuration"
 	HandlerSubstractUsage        = "*substract_usage"
 	XML                          = "xml"
 	MetaGOBrpc                   = "*gob"
@@ -393,6 +398,7 @@ const (
 	FileLockPrefix               = "file_"
 	ActionsPoster                = "act"
 	CDRPoster                    = "cdr"
+	ThresholdsPoster             = "thd"
 	MetaFileCSV                  = "*file_csv"
 	MetaFileFWV                  = "*file_fwv"
 	Accounts                     = "Accounts"
@@ -459,6 +465,7 @@ const (
 	TpDestinations               = "TpDestinations"
 	TpRatingPlan                 = "TpRatingPlan"
 	TpRatingProfile              = "TpRatingProfile"
+	TpAttributes                 = "TpAttributes"
 	Timing                       = "Timing"
 	RQF                          = "RQF"
 	Resource                     = "Resource"
@@ -491,24 +498,41 @@ const (
 	MetaDataDB                   = "*datadb"
 	MetaWeight                   = "*weight"
 	MetaLeastCost                = "*least_cost"
+	MetaHighestCost              = "*highest_cost"
+	MetaMaxMargin                = "*max_margin"
+	MetaQOS                      = "*qos"
+	MetaEventCost                = "*event_cost"
+	MetaLoadDistribution         = "*load_distribution"
+	MetaLeastUsed                = "*least_used"
+	MetaAscending                = "*asc"
+	MetaDescending               = "*desc"
 	Weight                       = "Weight"
 	Cost                         = "Cost"
 	RatingPlanID                 = "RatingPlanID"
+	SellCost                     = "SellCost"
+	Margin                       = "Margin"
+	ResourceUsage                = "ResourceUsage"
+	Ratio                        = "Ratio"
+	Load                         = "Load"
 	MetaSessionS                 = "*sessions"
 	FreeSWITCHAgent              = "FreeSWITCHAgent"
 )
 
 //MetaMetrics
 const (
-	MetaASR     = "*asr"
-	MetaACD     = "*acd"
-	MetaTCD     = "*tcd"
-	MetaACC     = "*acc"
-	MetaTCC     = "*tcc"
-	MetaPDD     = "*pdd"
-	MetaDDC     = "*ddc"
-	MetaSum     = "*sum"
-	MetaAverage = "*average"
+	MetaASR           = "*asr"
+	MetaACD           = "*acd"
+	MetaTCD           = "*tcd"
+	MetaACC           = "*acc"
+	MetaTCC           = "*tcc"
+	MetaPDD           = "*pdd"
+	MetaDDC           = "*ddc"
+	MetaSum           = "*sum"
+	MetaAverage       = "*average"
+	MetaMax           = "*max"
+	MetaMin           = "*min"
+	MetaDistinctCount = "*distinct_count"
+	MetaPercentile    = "*p" // prefix followed by the percentile, ie: *p95
 )
 
 //Services
@@ -548,6 +572,7 @@ const (
 	MetaTpDestinations     = "*tp_destinations"
 	MetaTpRatingPlan       = "*tp_rating_plan"
 	MetaTpRatingProfile    = "*tp_rating_profile"
+	MetaTpAttributes       = "*tp_attributes"
 )
 
 // MetaFilterIndexesAPIs
@@ -572,9 +597,11 @@ const (
 
 //ThresholdS APIs
 const (
-	ThresholdSv1ProcessEvent    = "ThresholdSv1.ProcessEvent"
-	ThresholdSv1GetThreshold    = "ThresholdSv1.GetThreshold"
-	ThresholdSv1GetThresholdIDs = "ThresholdSv1.GetThresholdIDs"
+	ThresholdSv1ProcessEvent          = "ThresholdSv1.ProcessEvent"
+	ThresholdSv1GetThreshold          = "ThresholdSv1.GetThreshold"
+	ThresholdSv1GetThresholdIDs       = "ThresholdSv1.GetThresholdIDs"
+	ThresholdSv1GetThresholdsForEvent = "ThresholdSv1.GetThresholdsForEvent"
+	ThresholdSv1ResetThreshold        = "ThresholdSv1.ResetThreshold"
 )
 
 //StatS APIs
@@ -582,6 +609,7 @@ const (
 	StatSv1ProcessEvent             = "StatSv1.ProcessEvent"
 	StatSv1GetQueueIDs              = "StatSv1.GetQueueIDs"
 	StatSv1GetGetQueueStringMetrics = "StatSv1.GetQueueStringMetrics"
+	StatSv1GetQueueFloatMetrics     = "StatSv1.GetQueueFloatMetrics"
 )
 
 //ResourceS APIs
@@ -590,6 +618,11 @@ const (
 	ResourceSv1GetResourcesForEvent = "ResourceSv1.GetResourcesForEvent"
 	ResourceSv1AllocateResources    = "ResourceSv1.AllocateResources"
 	ResourceSv1ReleaseResources     = "ResourceSv1.ReleaseResources"
+	ResourceSv1GetResource          = "ResourceSv1.GetResource"
+	ResourceSv1GetResourceIDs       = "ResourceSv1.GetResourceIDs"
+	ResourceSv1GetResourcesDetails  = "ResourceSv1.GetResourcesDetails"
+	ResourceSv1ReserveResources     = "ResourceSv1.ReserveResources"
+	ResourceSv1ConfirmResources     = "ResourceSv1.ConfirmResources"
 )
 
 //SessionS APIs
@@ -602,10 +635,17 @@ const (
 	SessionSv1ProcessEvent      = "SessionSv1.ProcessEvent"
 	SessionSv1DisconnectSession = "SessionSv1.DisconnectSession"
 	SMGenericV1InitiateSession  = "SMGenericV1.InitiateSession"
+	SMGenericV1ForceDisconnect  = "SMGenericV1.ForceDisconnect"
+	SMGenericV1ReAuthorize      = "SMGenericV1.ReAuthorize"
 	SMGenericV2InitiateSession  = "SMGenericV2.InitiateSession"
 	SMGenericV2UpdateSession    = "SMGenericV2.UpdateSession"
 )
 
+//DiameterAgent APIs
+const (
+	DiameterAgentV1GetPeers = "DiameterAgentV1.GetPeers"
+)
+
 //CSV file name
 const (
 	TIMINGS_CSV           = "Timings.csv"
@@ -49,6 +49,7 @@ var (
 	ErrPartiallyExecuted       = errors.New("PARTIALLY_EXECUTED")
 	ErrMaxUsageExceeded        = errors.New("MAX_USAGE_EXCEEDED")
 	ErrUnallocatedResource     = errors.New("UNALLOCATED_RESOURCE")
+	ErrForcedDisconnect        = errors.New("FORCED_DISCONNECT")
 )
 
 // NewCGRError initialises a new CGRError
@@ -69,7 +69,7 @@ func NewFallbackFileNameFronString(fileName string) (ffn *FallbackFileName, err
 	moduleIdx := strings.Index(fileName, HandlerArgSep)
 	ffn.Module = fileName[:moduleIdx]
 	var supportedModule bool
-	for _, prfx := range []string{ActionsPoster, CDRPoster} {
+	for _, prfx := range []string{ActionsPoster, CDRPoster, ThresholdsPoster} {
 		if strings.HasPrefix(ffn.Module, prfx) {
 			supportedModule = true
 			break